@@ -536,6 +536,64 @@ func (b *Block) GetTokenPledgedForDetails() string {
 	return b.getTrasnInfoString(TIRefIDKey)
 }
 
+func (b *Block) GetQuorumSignature() []string {
+	qsi, ok := b.bm[TCQuorumSignatureKey]
+	if !ok {
+		return nil
+	}
+	switch qs := qsi.(type) {
+	case []string:
+		return qs
+	case []interface{}:
+		sigs := make([]string, 0)
+		for _, s := range qs {
+			sigs = append(sigs, util.GetString(s))
+		}
+		return sigs
+	}
+	return nil
+}
+
+func (b *Block) GetPledgeDetails() []PledgeDetail {
+	pdm, ok := b.bm[TCPledgeDetailsKey]
+	if !ok {
+		return nil
+	}
+	pds := make([]PledgeDetail, 0)
+	addDetails := func(did string, v interface{}) {
+		switch pl := v.(type) {
+		case []map[string]interface{}:
+			for _, p := range pl {
+				pds = append(pds, newPledgeDetail(did, p))
+			}
+		case []interface{}:
+			for _, p := range pl {
+				pds = append(pds, newPledgeDetail(did, p))
+			}
+		}
+	}
+	switch m := pdm.(type) {
+	case map[string]interface{}:
+		for k, v := range m {
+			addDetails(k, v)
+		}
+	case map[interface{}]interface{}:
+		for k, v := range m {
+			addDetails(util.GetString(k), v)
+		}
+	}
+	return pds
+}
+
+func newPledgeDetail(did string, p interface{}) PledgeDetail {
+	return PledgeDetail{
+		Token:        util.GetStringFromMap(p, PDTokenKey),
+		TokenType:    util.GetIntFromMap(p, PDTokenTypeKey),
+		DID:          did,
+		TokenBlockID: util.GetStringFromMap(p, PDTokenBlockIDKey),
+	}
+}
+
 func (b *Block) GetTransType() string {
 	return b.getBlkString(TCTransTypeKey)
 }
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
@@ -64,11 +65,16 @@ func (c *Client) multiFormRequest(method string, path string, field map[string]s
 }
 
 func (c *Client) sendJSONRequest(method string, path string, query map[string]string, input interface{}, output interface{}, timeout ...time.Duration) error {
+	return c.sendJSONRequestWithContext(context.Background(), method, path, query, input, output, timeout...)
+}
+
+func (c *Client) sendJSONRequestWithContext(ctx context.Context, method string, path string, query map[string]string, input interface{}, output interface{}, timeout ...time.Duration) error {
 	req, err := c.basicRequest(method, path, input)
 	if err != nil {
 		c.log.Error("Failed to get http request")
 		return err
 	}
+	req = req.WithContext(ctx)
 	if query != nil {
 		q := req.URL.Query()
 		for k, v := range query {
@@ -14,3 +14,70 @@ func (c *Client) SetupDB(sc *config.StorageConfig) (string, bool) {
 	}
 	return br.Message, br.Status
 }
+
+func (c *Client) GetMigrations() (*model.MigrationResponse, error) {
+	var rm model.MigrationResponse
+	err := c.sendJSONRequest("GET", setup.APIGetMigrations, nil, nil, &rm)
+	if err != nil {
+		return nil, err
+	}
+	return &rm, nil
+}
+
+func (c *Client) RunMigrations() (*model.MigrationResponse, error) {
+	var rm model.MigrationResponse
+	err := c.sendJSONRequest("POST", setup.APIRunMigrations, nil, nil, &rm)
+	if err != nil {
+		return nil, err
+	}
+	return &rm, nil
+}
+
+func (c *Client) Backup(path string, password string) (string, bool) {
+	m := model.BackupRequest{
+		Path:     path,
+		Password: password,
+	}
+	var br model.BasicResponse
+	err := c.sendJSONRequest("POST", setup.APIBackup, nil, &m, &br)
+	if err != nil {
+		return "Failed to create backup, " + err.Error(), false
+	}
+	return br.Message, br.Status
+}
+
+func (c *Client) GetTokenIndexStatus() (*model.TokenIndexStatusResponse, error) {
+	var rm model.TokenIndexStatusResponse
+	err := c.sendJSONRequest("GET", setup.APIGetTokenIndexStatus, nil, nil, &rm)
+	if err != nil {
+		return nil, err
+	}
+	return &rm, nil
+}
+
+func (c *Client) GetPubSubMetrics() (*model.PubSubMetricsResponse, error) {
+	var rm model.PubSubMetricsResponse
+	err := c.sendJSONRequest("GET", setup.APIGetPubSubMetrics, nil, nil, &rm)
+	if err != nil {
+		return nil, err
+	}
+	return &rm, nil
+}
+
+func (c *Client) VerifyTokenChain(vr *model.VerifyTokenChainRequest) (*model.VerifyTokenChainResponse, error) {
+	var rm model.VerifyTokenChainResponse
+	err := c.sendJSONRequest("POST", setup.APIVerifyTokenChain, nil, vr, &rm)
+	if err != nil {
+		return nil, err
+	}
+	return &rm, nil
+}
+
+func (c *Client) VerifyWallet(vr *model.VerifyWalletRequest) (*model.VerifyWalletResponse, error) {
+	var rm model.VerifyWalletResponse
+	err := c.sendJSONRequest("POST", setup.APIVerifyWallet, nil, vr, &rm)
+	if err != nil {
+		return nil, err
+	}
+	return &rm, nil
+}
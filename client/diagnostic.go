@@ -44,3 +44,57 @@ func (c *Client) GetSmartContractTokenData(token string, latest bool) (*model.Sm
 	return &sctDataReply, nil
 
 }
+
+func (c *Client) GetSmartContractHistory(token string) (*model.SCHistoryResponse, error) {
+	hr := &model.SCHistoryRequest{
+		Token: token,
+	}
+	var resp model.SCHistoryResponse
+	err := c.sendJSONRequest("POST", setup.APIGetSmartContractHistory, nil, hr, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) GetSmartContractState(sr *model.SCStateRequest) (*model.SCStateResponse, error) {
+	var resp model.SCStateResponse
+	err := c.sendJSONRequest("POST", setup.APIGetSmartContractState, nil, sr, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) GetSmartContractStateDiff(dr *model.SCStateDiffRequest) (*model.SCStateDiffResponse, error) {
+	var resp model.SCStateDiffResponse
+	err := c.sendJSONRequest("POST", setup.APIGetSmartContractStateDiff, nil, dr, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) GetCallbackDeliveries(token string, status string) (*model.CallbackDeliveryResponse, error) {
+	q := make(map[string]string)
+	q["token"] = token
+	q["status"] = status
+	var resp model.CallbackDeliveryResponse
+	err := c.sendJSONRequest("GET", setup.APIGetCallbackDeliveries, q, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) ReplayCallbackDelivery(id string) (string, bool) {
+	rr := model.ReplayCallbackRequest{
+		ID: id,
+	}
+	var rm model.BasicResponse
+	err := c.sendJSONRequest("POST", setup.APIReplayCallbackDelivery, nil, &rr, &rm)
+	if err != nil {
+		return "Failed to replay callback delivery, " + err.Error(), false
+	}
+	return rm.Message, rm.Status
+}
@@ -56,6 +56,70 @@ func (c *Client) RemoveAllQuorum() (string, bool) {
 	return rm.Message, rm.Status
 }
 
+func (c *Client) GetPledgeLeases() (*model.PledgeLeaseResponse, error) {
+	var rm model.PledgeLeaseResponse
+	err := c.sendJSONRequest("GET", setup.APIGetPledgeLeases, nil, nil, &rm)
+	if err != nil {
+		return nil, err
+	}
+	return &rm, nil
+}
+
+func (c *Client) GetQuorumHealth() (*model.QuorumHealthResponse, error) {
+	var rm model.QuorumHealthResponse
+	err := c.sendJSONRequest("GET", setup.APIGetQuorumHealth, nil, nil, &rm)
+	if err != nil {
+		return nil, err
+	}
+	return &rm, nil
+}
+
+func (c *Client) GetUnpledgeStatus() (*model.UnpledgeStatusResponse, error) {
+	var rm model.UnpledgeStatusResponse
+	err := c.sendJSONRequest("GET", setup.APIGetUnpledgeStatus, nil, nil, &rm)
+	if err != nil {
+		return nil, err
+	}
+	return &rm, nil
+}
+
+func (c *Client) RetryUnpledge(token string) (string, bool) {
+	m := model.UnpledgeTokenRequest{
+		Token: token,
+	}
+	var rm model.BasicResponse
+	err := c.sendJSONRequest("POST", setup.APIRetryUnpledge, nil, &m, &rm)
+	if err != nil {
+		return "Failed to retry unpledge, " + err.Error(), false
+	}
+	return rm.Message, rm.Status
+}
+
+func (c *Client) CancelUnpledge(token string) (string, bool) {
+	m := model.UnpledgeTokenRequest{
+		Token: token,
+	}
+	var rm model.BasicResponse
+	err := c.sendJSONRequest("POST", setup.APICancelUnpledge, nil, &m, &rm)
+	if err != nil {
+		return "Failed to cancel unpledge, " + err.Error(), false
+	}
+	return rm.Message, rm.Status
+}
+
+func (c *Client) VerifyUnpledgeProof(token string, proof []byte) (*model.UnpledgeProofResponse, error) {
+	m := model.UnpledgeProofRequest{
+		Token: token,
+		Proof: proof,
+	}
+	var rm model.UnpledgeProofResponse
+	err := c.sendJSONRequest("POST", setup.APIVerifyUnpledgeProof, nil, &m, &rm)
+	if err != nil {
+		return nil, err
+	}
+	return &rm, nil
+}
+
 func (c *Client) SetupQuorum(did string, pwd string, privPwd string) (string, bool) {
 	m := model.QuorumSetup{
 		DID:             did,
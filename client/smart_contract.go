@@ -108,6 +108,27 @@ func (c *Client) SubscribeContract(smartContractToken string) (*model.BasicRespo
 	return &response, nil
 }
 
+func (c *Client) UnsubscribeContract(smartContractToken string) (string, bool) {
+	var response model.BasicResponse
+	subscription := model.NewSubscription{
+		SmartContractToken: smartContractToken,
+	}
+	err := c.sendJSONRequest("POST", setup.APIUnsubscribeContract, nil, &subscription, &response)
+	if err != nil {
+		return "Failed to unsubscribe contract, " + err.Error(), false
+	}
+	return response.Message, response.Status
+}
+
+func (c *Client) GetContractSubscriptions() (*model.SCSubscriptionResponse, error) {
+	var response model.SCSubscriptionResponse
+	err := c.sendJSONRequest("GET", setup.APIGetContractSubscriptions, nil, nil, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
 func (c *Client) ExecuteSmartContract(executeRequest *model.ExecuteSmartContractRequest) (*model.BasicResponse, error) {
 	var basicResponse model.BasicResponse
 	err := c.sendJSONRequest("POST", setup.APIExecuteSmartContract, nil, executeRequest, &basicResponse, time.Minute*2)
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"time"
 
 	"github.com/rubixchain/rubixgoplatform/core/model"
@@ -8,8 +9,13 @@ import (
 )
 
 func (c *Client) TransferRBT(rt *model.RBTTransferRequest) (*model.BasicResponse, error) {
+	return c.TransferRBTWithContext(context.Background(), rt)
+}
+
+// TransferRBTWithContext will initiate the transfer, node cancels the transfer once the context is done
+func (c *Client) TransferRBTWithContext(ctx context.Context, rt *model.RBTTransferRequest) (*model.BasicResponse, error) {
 	var br model.BasicResponse
-	err := c.sendJSONRequest("POST", setup.APIInitiateRBTTransfer, nil, rt, &br, time.Minute*2)
+	err := c.sendJSONRequestWithContext(ctx, "POST", setup.APIInitiateRBTTransfer, nil, rt, &br, time.Minute*2)
 	if err != nil {
 		c.log.Error("Failed RBT Transfer", "err", err)
 		return nil, err
@@ -76,6 +76,27 @@ const (
 	DumpSmartContractTokenChainCmd string = "dumpsmartcontracttokenchain"
 	GetTokenBlock                  string = "gettokenblock"
 	GetSmartContractData           string = "getsmartcontractdata"
+	GetPledgeLeasesCmd             string = "getpledgeleases"
+	GetMigrationsCmd               string = "getmigrations"
+	RunMigrationsCmd               string = "runmigrations"
+	BackupCmd                      string = "backup"
+	RestoreCmd                     string = "restore"
+	VerifyWalletCmd                string = "verifywallet"
+	VerifyTokenChainCmd            string = "verifytokenchain"
+	TokenIndexStatusCmd            string = "tokenindexstatus"
+	UnpledgeStatusCmd              string = "unpledgestatus"
+	RetryUnpledgeCmd               string = "retryunpledge"
+	CancelUnpledgeCmd              string = "cancelunpledge"
+	VerifyUnpledgeProofCmd         string = "verifyunpledgeproof"
+	PubSubMetricsCmd               string = "pubsubmetrics"
+	SCHistoryCmd                   string = "smartcontracthistory"
+	SCStateCmd                     string = "smartcontractstate"
+	SCStateDiffCmd                 string = "smartcontractstatediff"
+	CallbackDeliveriesCmd          string = "callbackdeliveries"
+	ReplayCallbackCmd              string = "replaycallback"
+	UnsubscribeContractCmd         string = "unsubscribesct"
+	ContractSubscriptionsCmd       string = "subscriptions"
+	GetQuorumHealthCmd             string = "getquorumhealth"
 )
 
 var commands = []string{VersionCmd,
@@ -120,6 +141,27 @@ var commands = []string{VersionCmd,
 	DumpSmartContractTokenChainCmd,
 	GetTokenBlock,
 	GetSmartContractData,
+	GetPledgeLeasesCmd,
+	GetMigrationsCmd,
+	RunMigrationsCmd,
+	BackupCmd,
+	RestoreCmd,
+	VerifyWalletCmd,
+	VerifyTokenChainCmd,
+	TokenIndexStatusCmd,
+	UnpledgeStatusCmd,
+	RetryUnpledgeCmd,
+	CancelUnpledgeCmd,
+	VerifyUnpledgeProofCmd,
+	PubSubMetricsCmd,
+	SCHistoryCmd,
+	SCStateCmd,
+	SCStateDiffCmd,
+	CallbackDeliveriesCmd,
+	ReplayCallbackCmd,
+	UnsubscribeContractCmd,
+	ContractSubscriptionsCmd,
+	GetQuorumHealthCmd,
 }
 var commandsHelp = []string{"To get tool version",
 	"To get help",
@@ -162,7 +204,28 @@ var commandsHelp = []string{"To get tool version",
 	"This command will subscribe to a smart contract token",
 	"This command will dump the smartcontract token chain",
 	"This command gets token block",
-	"This command gets the smartcontract data from latest block"}
+	"This command gets the smartcontract data from latest block",
+	"This command will get the active pledge leases of the quorum",
+	"This command will get the applied & pending wallet migrations",
+	"This command will run the pending wallet migrations",
+	"This command will create the backup of the running node",
+	"This command will restore the node from the backup, node should not be running",
+	"This command will verify the token chains against the wallet tokens",
+	"This command will verify all the blocks of the token chain",
+	"This command will get the build status of the token index",
+	"This command will get the status of the unpledge queue",
+	"This command will retry the failed or cancelled token unpledge",
+	"This command will cancel the pending token unpledge",
+	"This command will verify the unpledge proof of the token",
+	"This command will get the message counters of the pubsub topics",
+	"This command will list the executions of the smart contract",
+	"This command will get the smart contract state as of the block number or the unix time",
+	"This command will get the smart contract state difference between two blocks",
+	"This command will list the smart contract callback deliveries",
+	"This command will replay the smart contract callback delivery",
+	"This command will unsubscribe the smart contract",
+	"This command will list the smart contracts subscribed by the node",
+	"This command will get the recorded health of the public quorums"}
 
 type Command struct {
 	cfg                config.Config
@@ -216,6 +279,19 @@ type Command struct {
 	tokenList          string
 	batchID            string
 	fileMode           bool
+	backupFile         string
+	backupPassword     string
+	force              bool
+	repair             bool
+	skipSig            bool
+	tokenType          string
+	proofFile          string
+	blockNo            uint64
+	fromBlock          uint64
+	toBlock            uint64
+	atTime             int64
+	deliveryID         string
+	deliveryStatus     string
 	file               string
 	userID             string
 	userInfo           string
@@ -388,7 +464,7 @@ func Run(args []string) {
 	flag.StringVar(&cmd.pubKeyFile, "pubKeyFile", did.PubKeyFileName, "Public key file")
 	flag.StringVar(&cmd.quorumList, "quorumList", "quorumlist.json", "Quorum list")
 	flag.StringVar(&cmd.srvName, "srvName", "explorer_service", "Service name")
-	flag.IntVar(&cmd.storageType, "storageType", storage.StorageDBType, "Storage type")
+	flag.IntVar(&cmd.storageType, "storageType", storage.StorageDBType, "Storage type (1 - DB, 2 - LevelDB, 3 - Memory)")
 	flag.StringVar(&cmd.dbName, "dbName", "ServiceDB", "Service database name")
 	flag.StringVar(&cmd.dbType, "dbType", "SQLServer", "DB Type, supported database are SQLServer, PostgressSQL, MySQL & Sqlite3")
 	flag.StringVar(&cmd.dbAddress, "dbAddress", "localhost", "Database address")
@@ -409,6 +485,19 @@ func Run(args []string) {
 	flag.StringVar(&cmd.batchID, "bid", "batchID1", "Batch ID")
 	flag.BoolVar(&cmd.fileMode, "fmode", false, "File mode")
 	flag.StringVar(&cmd.file, "file", "file.txt", "File to be uploaded")
+	flag.StringVar(&cmd.backupFile, "backupFile", "", "Backup archive file")
+	flag.StringVar(&cmd.backupPassword, "backupPassword", "", "Backup encryption password")
+	flag.BoolVar(&cmd.force, "force", false, "Replace the existing node state on restore")
+	flag.BoolVar(&cmd.repair, "repair", false, "Apply the repair plan of the wallet verification")
+	flag.BoolVar(&cmd.skipSig, "skipSig", false, "Skip the block signature verification")
+	flag.StringVar(&cmd.tokenType, "tokenType", "rbt", "Token type (rbt, part, nft, data, sc)")
+	flag.StringVar(&cmd.proofFile, "proofFile", "", "Unpledge proof file")
+	flag.Uint64Var(&cmd.blockNo, "blockNo", 0, "Block number")
+	flag.Uint64Var(&cmd.fromBlock, "fromBlock", 0, "From block number")
+	flag.Uint64Var(&cmd.toBlock, "toBlock", 0, "To block number")
+	flag.Int64Var(&cmd.atTime, "atTime", 0, "Unix time")
+	flag.StringVar(&cmd.deliveryID, "deliveryID", "", "Callback delivery ID")
+	flag.StringVar(&cmd.deliveryStatus, "deliveryStatus", "", "Callback delivery status (pending, delivered, failed)")
 	flag.StringVar(&cmd.userID, "uid", "testuser", "User ID for token creation")
 	flag.StringVar(&cmd.userInfo, "uinfo", "", "User info for token creation")
 	flag.IntVar(&timeout, "timeout", 0, "Timeout for the server")
@@ -514,6 +603,48 @@ func Run(args []string) {
 		cmd.AddQuorurm()
 	case GetAllQuorumCmd:
 		cmd.GetAllQuorum()
+	case GetPledgeLeasesCmd:
+		cmd.GetPledgeLeases()
+	case GetMigrationsCmd:
+		cmd.GetMigrations()
+	case RunMigrationsCmd:
+		cmd.RunMigrations()
+	case BackupCmd:
+		cmd.Backup()
+	case RestoreCmd:
+		cmd.Restore()
+	case VerifyWalletCmd:
+		cmd.VerifyWallet()
+	case VerifyTokenChainCmd:
+		cmd.VerifyTokenChain()
+	case TokenIndexStatusCmd:
+		cmd.GetTokenIndexStatus()
+	case UnpledgeStatusCmd:
+		cmd.GetUnpledgeStatus()
+	case RetryUnpledgeCmd:
+		cmd.RetryUnpledge()
+	case CancelUnpledgeCmd:
+		cmd.CancelUnpledge()
+	case VerifyUnpledgeProofCmd:
+		cmd.VerifyUnpledgeProof()
+	case PubSubMetricsCmd:
+		cmd.GetPubSubMetrics()
+	case SCHistoryCmd:
+		cmd.GetSmartContractHistory()
+	case SCStateCmd:
+		cmd.GetSmartContractState()
+	case SCStateDiffCmd:
+		cmd.GetSmartContractStateDiff()
+	case CallbackDeliveriesCmd:
+		cmd.GetCallbackDeliveries()
+	case ReplayCallbackCmd:
+		cmd.ReplayCallbackDelivery()
+	case UnsubscribeContractCmd:
+		cmd.UnsubscribeContract()
+	case ContractSubscriptionsCmd:
+		cmd.GetContractSubscriptions()
+	case GetQuorumHealthCmd:
+		cmd.GetQuorumHealth()
 	case RemoveAllQuorumCmd:
 		cmd.RemoveAllQuorum()
 	case SetupQuorumCmd:
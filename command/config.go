@@ -1,6 +1,11 @@
 package command
 
-import "github.com/rubixchain/rubixgoplatform/core/config"
+import (
+	"fmt"
+
+	"github.com/rubixchain/rubixgoplatform/core"
+	"github.com/rubixchain/rubixgoplatform/core/config"
+)
 
 func (cmd *Command) setupDB() {
 	sc := &config.StorageConfig{
@@ -19,3 +24,81 @@ func (cmd *Command) setupDB() {
 	}
 	cmd.log.Info("DB setup done successfully")
 }
+
+func (cmd *Command) GetMigrations() {
+	response, err := cmd.c.GetMigrations()
+	if err != nil {
+		cmd.log.Error("Invalid response from the node", "err", err)
+		return
+	}
+	if !response.Status {
+		cmd.log.Error("Failed to get migrations from node", "msg", response.Message)
+		return
+	}
+	pending := 0
+	for _, m := range response.Result {
+		if m.Applied {
+			fmt.Printf("Version : %d, Name : %s, Applied : %s\n", m.Version, m.Name, m.AppliedTime.String())
+		} else {
+			pending++
+			fmt.Printf("Version : %d, Name : %s, Pending\n", m.Version, m.Name)
+		}
+	}
+	cmd.log.Info("Got all migrations successfully", "pending", pending)
+}
+
+func (cmd *Command) RunMigrations() {
+	response, err := cmd.c.RunMigrations()
+	if err != nil {
+		cmd.log.Error("Invalid response from the node", "err", err)
+		return
+	}
+	for _, m := range response.Result {
+		fmt.Printf("Version : %d, Name : %s, Applied : %s\n", m.Version, m.Name, m.AppliedTime.String())
+	}
+	if !response.Status {
+		cmd.log.Error("Failed to run migrations", "msg", response.Message)
+		return
+	}
+	cmd.log.Info("Migrations done successfully", "applied", len(response.Result))
+}
+
+func (cmd *Command) getBackupPassword() bool {
+	if cmd.forcePWD {
+		pwd, err := getpassword("Enter backup password: ")
+		if err != nil {
+			cmd.log.Error("Failed to get password")
+			return false
+		}
+		cmd.backupPassword = pwd
+	}
+	return true
+}
+
+func (cmd *Command) Backup() {
+	if !cmd.getBackupPassword() {
+		return
+	}
+	msg, status := cmd.c.Backup(cmd.backupFile, cmd.backupPassword)
+	if !status {
+		cmd.log.Error("Failed to create backup", "msg", msg)
+		return
+	}
+	cmd.log.Info(msg)
+}
+
+func (cmd *Command) Restore() {
+	if cmd.backupFile == "" {
+		cmd.log.Error("Backup file is required")
+		return
+	}
+	if !cmd.getBackupPassword() {
+		return
+	}
+	m, err := core.RestoreBackup(cmd.backupFile, cmd.runDir, cmd.backupPassword, cmd.encKey, cmd.force)
+	if err != nil {
+		cmd.log.Error("Failed to restore backup", "err", err)
+		return
+	}
+	cmd.log.Info("Node restored successfully", "created", m.CreationTime.String(), "files", len(m.Files))
+}
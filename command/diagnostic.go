@@ -1,10 +1,13 @@
 package command
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/rubixchain/rubixgoplatform/block"
+	"github.com/rubixchain/rubixgoplatform/core/model"
 	"github.com/rubixchain/rubixgoplatform/util"
 )
 
@@ -228,3 +231,194 @@ func (cmd *Command) getSmartContractData() {
 	*/
 
 }
+
+func (cmd *Command) VerifyWallet() {
+	vr := model.VerifyWalletRequest{
+		DID:           cmd.did,
+		SkipSignature: cmd.skipSig,
+		Repair:        cmd.repair,
+	}
+	response, err := cmd.c.VerifyWallet(&vr)
+	if err != nil {
+		cmd.log.Error("Invalid response from the node", "err", err)
+		return
+	}
+	if !response.Status {
+		cmd.log.Error("Failed to verify wallet", "msg", response.Message)
+		return
+	}
+	rp := response.Result
+	for _, wi := range rp.Issues {
+		fmt.Printf("Token : %s, DID : %s, Issue : %s, Message : %s\n", wi.Token, wi.DID, wi.Issue, wi.Message)
+		if wi.Repairable {
+			fmt.Printf("    Repair : set status %d, Repaired : %t\n", wi.RepairStatus, wi.Repaired)
+		}
+	}
+	rb, err := json.MarshalIndent(rp, "", "  ")
+	if err != nil {
+		cmd.log.Error("Failed to write wallet report", "err", err)
+		return
+	}
+	err = os.WriteFile("verify_wallet.json", rb, 0644)
+	if err != nil {
+		cmd.log.Error("Failed to write wallet report", "err", err)
+		return
+	}
+	cmd.log.Info("Wallet verification done, report written to verify_wallet.json", "tokens", rp.TokensChecked, "blocks", rp.BlocksChecked, "issues", len(rp.Issues))
+}
+
+func (cmd *Command) VerifyTokenChain() {
+	if cmd.token == "" {
+		cmd.log.Error("Token is required")
+		return
+	}
+	vr := model.VerifyTokenChainRequest{
+		Token:     cmd.token,
+		TokenType: cmd.tokenType,
+	}
+	response, err := cmd.c.VerifyTokenChain(&vr)
+	if err != nil {
+		cmd.log.Error("Invalid response from the node", "err", err)
+		return
+	}
+	if !response.Status {
+		cmd.log.Error("Failed to verify token chain", "msg", response.Message)
+		return
+	}
+	tr := response.Result
+	for _, br := range tr.Blocks {
+		fmt.Printf("Block : %d, ID : %s, Type : %s, Owner : %s, Signatures : %d, Quorum Signatures : %d, Pledges : %d, Valid : %t\n",
+			br.BlockNumber, br.BlockID, br.TransType, br.Owner, len(br.Signatures), len(br.QuorumSignatures), br.PledgeDetails, br.Valid)
+		for _, e := range br.Errors {
+			fmt.Printf("    Error : %s\n", e)
+		}
+	}
+	for _, e := range tr.Errors {
+		fmt.Printf("Error : %s\n", e)
+	}
+	if !tr.Valid {
+		cmd.log.Error("Token chain is invalid", "token", tr.Token)
+		return
+	}
+	cmd.log.Info("Token chain is valid", "token", tr.Token, "blocks", len(tr.Blocks))
+}
+
+func (cmd *Command) GetTokenIndexStatus() {
+	response, err := cmd.c.GetTokenIndexStatus()
+	if err != nil {
+		cmd.log.Error("Invalid response from the node", "err", err)
+		return
+	}
+	if !response.Status {
+		cmd.log.Error("Failed to get token index status", "msg", response.Message)
+		return
+	}
+	ts := response.Result
+	fmt.Printf("Indexed : %d, Total : %d, Building : %t\n", ts.Indexed, ts.Total, ts.Building)
+	fmt.Printf("Indexed Levels : %v\n", ts.IndexedLevels)
+}
+
+func (cmd *Command) GetPubSubMetrics() {
+	response, err := cmd.c.GetPubSubMetrics()
+	if err != nil {
+		cmd.log.Error("Invalid response from the node", "err", err)
+		return
+	}
+	if !response.Status {
+		cmd.log.Error("Failed to get pubsub metrics", "msg", response.Message)
+		return
+	}
+	for _, tm := range response.Result {
+		fmt.Printf("Topic : %s, Subscribed : %t, Received : %d, Delivered : %d, Dropped : %d, Published : %d, Errors : %d, Resubscribes : %d\n",
+			tm.Topic, tm.Subscribed, tm.Received, tm.Delivered, tm.Dropped, tm.Published, tm.Errors, tm.Resubscribes)
+	}
+}
+
+func (cmd *Command) GetSmartContractHistory() {
+	response, err := cmd.c.GetSmartContractHistory(cmd.smartContractToken)
+	if err != nil {
+		cmd.log.Error("Invalid response from the node", "err", err)
+		return
+	}
+	if !response.Status {
+		cmd.log.Error("Failed to get smart contract history", "msg", response.Message)
+		return
+	}
+	for _, se := range response.History {
+		fmt.Printf("Block : %d, Executor : %s, State Hash : %s, Fuel : %d, Time : %s, Data : %s\n",
+			se.BlockNo, se.ExecutorDID, se.StateHash, se.Fuel, se.Time.Format(time.RFC3339), se.Data)
+	}
+}
+
+func (cmd *Command) GetSmartContractState() {
+	sr := model.SCStateRequest{
+		Token:   cmd.smartContractToken,
+		BlockNo: cmd.blockNo,
+		Time:    cmd.atTime,
+	}
+	response, err := cmd.c.GetSmartContractState(&sr)
+	if err != nil {
+		cmd.log.Error("Invalid response from the node", "err", err)
+		return
+	}
+	if !response.Status {
+		cmd.log.Error("Failed to get smart contract state", "msg", response.Message)
+		return
+	}
+	if response.Execution != nil {
+		fmt.Printf("Block : %d, State Hash : %s\n", response.Execution.BlockNo, response.Execution.StateHash)
+	}
+	fmt.Printf("State : %s\n", response.State)
+}
+
+func (cmd *Command) GetSmartContractStateDiff() {
+	dr := model.SCStateDiffRequest{
+		Token:     cmd.smartContractToken,
+		FromBlock: cmd.fromBlock,
+		ToBlock:   cmd.toBlock,
+	}
+	response, err := cmd.c.GetSmartContractStateDiff(&dr)
+	if err != nil {
+		cmd.log.Error("Invalid response from the node", "err", err)
+		return
+	}
+	if !response.Status {
+		cmd.log.Error("Failed to get smart contract state diff", "msg", response.Message)
+		return
+	}
+	d := response.Diff
+	fmt.Printf("From State Hash : %s, To State Hash : %s, Changed : %t\n", d.FromStateHash, d.ToStateHash, d.Changed)
+	for _, ch := range d.Changes {
+		fmt.Printf("Key : %s, Change : %s, From : %s, To : %s\n", ch.Key, ch.Op, string(ch.From), string(ch.To))
+	}
+}
+
+func (cmd *Command) GetCallbackDeliveries() {
+	response, err := cmd.c.GetCallbackDeliveries(cmd.smartContractToken, cmd.deliveryStatus)
+	if err != nil {
+		cmd.log.Error("Invalid response from the node", "err", err)
+		return
+	}
+	if !response.Status {
+		cmd.log.Error("Failed to get callback deliveries", "msg", response.Message)
+		return
+	}
+	for _, cd := range response.Result {
+		fmt.Printf("ID : %s, Token : %s, URL : %s, Status : %s, Attempts : %d, Next Attempt : %s, Error : %s\n",
+			cd.ID, cd.Token, cd.URL, cd.Status, cd.Attempts, cd.NextAttempt.Format(time.RFC3339), cd.LastError)
+	}
+	cmd.log.Info("Got callback deliveries successfully")
+}
+
+func (cmd *Command) ReplayCallbackDelivery() {
+	if cmd.deliveryID == "" {
+		cmd.log.Error("Delivery ID is required")
+		return
+	}
+	msg, status := cmd.c.ReplayCallbackDelivery(cmd.deliveryID)
+	if !status {
+		cmd.log.Error("Failed to replay callback delivery", "msg", msg)
+		return
+	}
+	cmd.log.Info(msg)
+}
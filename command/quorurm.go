@@ -2,6 +2,8 @@ package command
 
 import (
 	"fmt"
+	"os"
+	"time"
 )
 
 func (cmd *Command) AddQuorurm() {
@@ -38,6 +40,118 @@ func (cmd *Command) RemoveAllQuorum() {
 	cmd.log.Info(msg)
 }
 
+func (cmd *Command) GetPledgeLeases() {
+	response, err := cmd.c.GetPledgeLeases()
+	if err != nil {
+		cmd.log.Error("Invalid response from the node", "err", err)
+		return
+	}
+	if !response.Status {
+		cmd.log.Error("Failed to get pledge leases from node", "msg", response.Message)
+		return
+	}
+	for _, pl := range response.Result {
+		fmt.Printf("Token : %s, Value : %v, Quorum : %s, Initiator : %s, Request : %s, Expiry : %s\n", pl.Token, pl.TokenValue, pl.QuorumDID, pl.InitiatorID, pl.ReqID, pl.Expiry.String())
+	}
+	cmd.log.Info("Got all pledge leases successfully")
+}
+
+func (cmd *Command) GetQuorumHealth() {
+	response, err := cmd.c.GetQuorumHealth()
+	if err != nil {
+		cmd.log.Error("Invalid response from the node", "err", err)
+		return
+	}
+	if !response.Status {
+		cmd.log.Error("Failed to get quorum health from node", "msg", response.Message)
+		return
+	}
+	for _, qh := range response.Result {
+		fmt.Printf("DID : %s, Healthy : %t, Score : %.3f, Success : %d, Failure : %d, Consecutive Failures : %d, Median Latency : %dms\n",
+			qh.DID, qh.Healthy, qh.Score, qh.SuccessCount, qh.FailureCount, qh.ConsecutiveFailures, qh.MedianLatency)
+	}
+	cmd.log.Info("Got quorum health successfully")
+}
+
+func (cmd *Command) GetUnpledgeStatus() {
+	response, err := cmd.c.GetUnpledgeStatus()
+	if err != nil {
+		cmd.log.Error("Invalid response from the node", "err", err)
+		return
+	}
+	if !response.Status {
+		cmd.log.Error("Failed to get unpledge status from node", "msg", response.Message)
+		return
+	}
+	us := response.Result
+	for _, ts := range us.Tokens {
+		fmt.Printf("Token : %s, Status : %s, Attempts : %d, Hashes : %d, Reason : %s\n", ts.Token, ts.Status, ts.Attempts, ts.Hashes, ts.Reason)
+	}
+	eta := "unknown"
+	if us.ETA > 0 {
+		eta = (time.Duration(us.ETA) * time.Second).String()
+	}
+	fmt.Printf("Workers : %d, Queued : %d, Working : %d, Failed : %d, Hash Rate : %.0f/s, ETA : %s\n", us.Workers, us.Queued, us.Working, us.Failed, us.HashRate, eta)
+	cmd.log.Info("Got unpledge status successfully")
+}
+
+func (cmd *Command) RetryUnpledge() {
+	if cmd.token == "" {
+		cmd.log.Error("Token is required")
+		return
+	}
+	msg, status := cmd.c.RetryUnpledge(cmd.token)
+	if !status {
+		cmd.log.Error("Failed to retry unpledge", "msg", msg)
+		return
+	}
+	cmd.log.Info(msg)
+}
+
+func (cmd *Command) CancelUnpledge() {
+	if cmd.token == "" {
+		cmd.log.Error("Token is required")
+		return
+	}
+	msg, status := cmd.c.CancelUnpledge(cmd.token)
+	if !status {
+		cmd.log.Error("Failed to cancel unpledge", "msg", msg)
+		return
+	}
+	cmd.log.Info(msg)
+}
+
+func (cmd *Command) VerifyUnpledgeProof() {
+	if cmd.token == "" {
+		cmd.log.Error("Token is required")
+		return
+	}
+	var proof []byte
+	if cmd.proofFile != "" {
+		var err error
+		proof, err = os.ReadFile(cmd.proofFile)
+		if err != nil {
+			cmd.log.Error("Failed to read the proof file", "err", err)
+			return
+		}
+	}
+	response, err := cmd.c.VerifyUnpledgeProof(cmd.token, proof)
+	if err != nil {
+		cmd.log.Error("Invalid response from the node", "err", err)
+		return
+	}
+	if !response.Status {
+		cmd.log.Error("Failed to verify unpledge proof", "msg", response.Message)
+		return
+	}
+	pr := response.Result
+	if !pr.Valid {
+		cmd.log.Error("Unpledge proof is invalid", "token", pr.Token, "reason", pr.Reason)
+		return
+	}
+	cmd.log.Info("Unpledge proof is valid", "token", pr.Token, "records", pr.Records, "compact", pr.Compact)
+}
+
 func (cmd *Command) SetupQuorum() {
 	if cmd.forcePWD {
 		pwd, err := getpassword("Enter quorum key password: ")
@@ -1,6 +1,8 @@
 package command
 
 import (
+	"fmt"
+
 	"github.com/rubixchain/rubixgoplatform/client"
 	"github.com/rubixchain/rubixgoplatform/core"
 	"github.com/rubixchain/rubixgoplatform/core/model"
@@ -94,6 +96,36 @@ func (cmd *Command) SubscribeContract() {
 	cmd.log.Info("New event subscribed successfully")
 }
 
+func (cmd *Command) UnsubscribeContract() {
+	if cmd.smartContractToken == "" {
+		cmd.log.Error("Smart contract token is required")
+		return
+	}
+	msg, status := cmd.c.UnsubscribeContract(cmd.smartContractToken)
+	if !status {
+		cmd.log.Error("Failed to unsubscribe contract", "msg", msg)
+		return
+	}
+	cmd.log.Info(msg)
+}
+
+func (cmd *Command) GetContractSubscriptions() {
+	response, err := cmd.c.GetContractSubscriptions()
+	if err != nil {
+		cmd.log.Error("Invalid response from the node", "err", err)
+		return
+	}
+	if !response.Status {
+		cmd.log.Error("Failed to get contract subscriptions", "msg", response.Message)
+		return
+	}
+	for _, sub := range response.Result {
+		fmt.Printf("Token : %s, Active : %t, Peer : %s, DID : %s, Last Block : %s\n",
+			sub.SmartContractToken, sub.Active, sub.PeerID, sub.DID, sub.LastBlockID)
+	}
+	cmd.log.Info("Got contract subscriptions successfully")
+}
+
 func (cmd *Command) deploySmartcontract() {
 	deployRequest := model.DeploySmartContractRequest{
 		SmartContractToken: cmd.smartContractToken,
@@ -0,0 +1,687 @@
+package core
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rubixchain/rubixgoplatform/core/config"
+	"github.com/rubixchain/rubixgoplatform/core/storage"
+	"github.com/rubixchain/rubixgoplatform/core/wallet"
+	"github.com/rubixchain/rubixgoplatform/wrapper/apiconfig"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	BackupDir          string = "backup/"
+	BackupManifestFile string = "manifest.json"
+	BackupNodeDir      string = "node"
+	BackupVersion      int    = 1
+	backupEncMagic     string = "RBXBKENC"
+)
+
+// BackupFile is the file in the backup archive
+type BackupFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// BackupManifest describes the backup archive, all the paths are relative
+// to the node directory. Items are restored as a whole, i.e. the existing
+// item is replaced and not merged.
+type BackupManifest struct {
+	Version      int          `json:"version"`
+	CreationTime time.Time    `json:"creation_time"`
+	TestNet      bool         `json:"test_net"`
+	StorageType  int          `json:"storage_type"`
+	DBPath       string       `json:"db_path"`
+	ChainDir     string       `json:"chain_dir"`
+	ConfigFile   string       `json:"config_file"`
+	Items        []string     `json:"items"`
+	Files        []BackupFile `json:"files"`
+}
+
+// relNodePath returns the path relative to the node directory
+func (c *Core) relNodePath(p string) string {
+	r, err := filepath.Rel(c.cfg.DirPath, p)
+	if err != nil || r == ".." || strings.HasPrefix(r, ".."+string(filepath.Separator)) {
+		return RubixRootDir + filepath.Base(p)
+	}
+	return filepath.ToSlash(r)
+}
+
+// isNodeDir checks whether the directory under the DID root is used by the node
+// itself rather than the DID
+func isNodeDir(name string) bool {
+	switch name {
+	case MainNetDir, TestNetDir, strings.TrimSuffix(TestNetDIDDir, "/"), strings.TrimSuffix(BackupDir, "/"):
+		return true
+	}
+	return strings.HasSuffix(name, ".ldb")
+}
+
+// Backup writes the archive of the node state while the node is running.
+// The writes are not paused, each of the wallet DB & the token chains is
+// copied consistently but the archive is not the snapshot of a single point
+// in time. The wallet DB is copied before the token chains, a token chain
+// ahead of the DB is completed by the transfer reconciler after the restore
+// and the other differences are reported by the wallet verification.
+func (c *Core) Backup(path string, password string) (string, error) {
+	c.backupLock.Lock()
+	defer c.backupLock.Unlock()
+	sc := c.cfg.CfgData.StorageConfig
+	if c.testNet {
+		sc = c.cfg.CfgData.TestStorageConfig
+	}
+	if sc.StorageType == storage.StorageMemType {
+		return "", fmt.Errorf("memory storage can not be backed up")
+	}
+	if path == "" {
+		path = c.cfg.DirPath + BackupDir + "rubix-backup-" + time.Now().Format("20060102150405") + ".tar.gz"
+	}
+	err := os.MkdirAll(filepath.Dir(path), os.ModeDir|os.ModePerm)
+	if err != nil {
+		return "", err
+	}
+	sd, err := ioutil.TempDir(c.cfg.DirPath, "backup-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(sd)
+	nd := filepath.Join(sd, BackupNodeDir)
+	m := BackupManifest{
+		Version:      BackupVersion,
+		CreationTime: time.Now(),
+		TestNet:      c.testNet,
+		StorageType:  sc.StorageType,
+		DBPath:       c.relNodePath(c.dbPath),
+		ChainDir:     c.relNodePath(c.tcDir),
+		ConfigFile:   c.relNodePath(c.cfgFile),
+		Items:        make([]string, 0),
+	}
+	err = os.MkdirAll(filepath.Dir(filepath.Join(nd, m.DBPath)), os.ModeDir|os.ModePerm)
+	if err != nil {
+		return "", err
+	}
+	err = c.s.Backup(filepath.Join(nd, m.DBPath))
+	if err != nil {
+		c.log.Error("Failed to backup the wallet DB", "err", err)
+		return "", err
+	}
+	m.Items = append(m.Items, m.DBPath)
+	cd := filepath.Join(nd, m.ChainDir)
+	err = os.MkdirAll(cd, os.ModeDir|os.ModePerm)
+	if err != nil {
+		return "", err
+	}
+	err = c.w.BackupTokenChains(cd + "/")
+	if err != nil {
+		return "", err
+	}
+	for _, tc := range wallet.TokenChainStorages() {
+		m.Items = append(m.Items, m.ChainDir+"/"+tc)
+	}
+	fis, err := ioutil.ReadDir(c.didDir)
+	if err != nil {
+		return "", err
+	}
+	for _, fi := range fis {
+		if !fi.IsDir() || isNodeDir(fi.Name()) {
+			continue
+		}
+		item := c.relNodePath(filepath.Join(c.didDir, fi.Name()))
+		err = copyBackupDir(filepath.Join(c.didDir, fi.Name()), filepath.Join(nd, item))
+		if err != nil {
+			c.log.Error("Failed to backup the DID", "did", fi.Name(), "err", err)
+			return "", err
+		}
+		m.Items = append(m.Items, item)
+	}
+	err = copyBackupFile(c.cfgFile, filepath.Join(nd, m.ConfigFile))
+	if err != nil {
+		c.log.Error("Failed to backup the config", "err", err)
+		return "", err
+	}
+	m.Items = append(m.Items, m.ConfigFile)
+	m.Files, err = backupFiles(nd)
+	if err != nil {
+		return "", err
+	}
+	mb, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	err = ioutil.WriteFile(filepath.Join(sd, BackupManifestFile), mb, 0644)
+	if err != nil {
+		return "", err
+	}
+	err = writeBackupFile(sd, path+".tmp", password)
+	if err != nil {
+		os.Remove(path + ".tmp")
+		return "", err
+	}
+	err = os.Rename(path+".tmp", path)
+	if err != nil {
+		return "", err
+	}
+	c.log.Info("Backup created", "path", path, "files", len(m.Files))
+	return path, nil
+}
+
+// RestoreBackup validates the backup archive and restores the node state into
+// the directory, the node must not be running. The existing state is replaced
+// only if force is set.
+func RestoreBackup(archive string, dirPath string, password string, encKey string, force bool) (*BackupManifest, error) {
+	f, err := os.Open(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	br := bufio.NewReader(f)
+	var r io.Reader = br
+	magic, _ := br.Peek(len(backupEncMagic))
+	if string(magic) == backupEncMagic {
+		if password == "" {
+			return nil, fmt.Errorf("backup is encrypted, password is required")
+		}
+		r, err = newBackupDecrypter(br, password)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = os.MkdirAll(dirPath, os.ModeDir|os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+	sd, err := ioutil.TempDir(dirPath, "restore-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(sd)
+	err = readBackupArchive(r, sd)
+	if err != nil {
+		return nil, err
+	}
+	// read till the end, the truncated encrypted backup fails here
+	_, err = io.Copy(io.Discard, r)
+	if err != nil {
+		return nil, err
+	}
+	m, err := validateBackup(sd)
+	if err != nil {
+		return nil, err
+	}
+	nd := filepath.Join(sd, BackupNodeDir)
+	if encKey != "" && m.ConfigFile != "" {
+		var cfg config.Config
+		err = apiconfig.LoadAPIConfig(filepath.Join(nd, m.ConfigFile), encKey, &cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the backup config, invalid encryption key")
+		}
+	}
+	for _, item := range m.Items {
+		if _, err := os.Stat(filepath.Join(dirPath, item)); err == nil && !force {
+			return nil, fmt.Errorf("%s already exist, use force to replace the node state", item)
+		}
+	}
+	for _, item := range m.Items {
+		t := filepath.Join(dirPath, item)
+		err = os.RemoveAll(t)
+		if err != nil {
+			return nil, err
+		}
+		err = os.MkdirAll(filepath.Dir(t), os.ModeDir|os.ModePerm)
+		if err != nil {
+			return nil, err
+		}
+		err = os.Rename(filepath.Join(nd, item), t)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// validateBackup checks the manifest & the checksum of all the files
+func validateBackup(sd string) (*BackupManifest, error) {
+	mb, err := ioutil.ReadFile(filepath.Join(sd, BackupManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("invalid backup, manifest is missing")
+	}
+	var m BackupManifest
+	err = json.Unmarshal(mb, &m)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backup, failed to parse manifest")
+	}
+	if m.Version == 0 || m.Version > BackupVersion {
+		return nil, fmt.Errorf("unsupported backup version %d", m.Version)
+	}
+	nd := filepath.Join(sd, BackupNodeDir)
+	fs, err := backupFiles(nd)
+	if err != nil {
+		return nil, err
+	}
+	fm := make(map[string]BackupFile)
+	for _, f := range m.Files {
+		fm[f.Path] = f
+	}
+	if len(fs) != len(fm) {
+		return nil, fmt.Errorf("invalid backup, files does not match the manifest")
+	}
+	for _, f := range fs {
+		mf, ok := fm[f.Path]
+		if !ok || mf.Size != f.Size || mf.SHA256 != f.SHA256 {
+			return nil, fmt.Errorf("invalid backup, %s is corrupted", f.Path)
+		}
+	}
+	for _, item := range m.Items {
+		if !isSafeBackupPath(item) {
+			return nil, fmt.Errorf("invalid backup item %s", item)
+		}
+		if _, err := os.Stat(filepath.Join(nd, item)); err != nil {
+			return nil, fmt.Errorf("invalid backup, %s is missing", item)
+		}
+	}
+	return &m, nil
+}
+
+func isSafeBackupPath(p string) bool {
+	if p == "" || filepath.IsAbs(p) || strings.HasPrefix(p, "/") {
+		return false
+	}
+	for _, e := range strings.Split(filepath.ToSlash(p), "/") {
+		if e == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+// backupFiles returns all the files under the directory along with the checksum
+func backupFiles(dir string) ([]BackupFile, error) {
+	fs := make([]BackupFile, 0)
+	err := filepath.Walk(dir, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fi.IsDir() {
+			return nil
+		}
+		r, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		h := sha256.New()
+		n, err := io.Copy(h, f)
+		if err != nil {
+			return err
+		}
+		fs = append(fs, BackupFile{Path: filepath.ToSlash(r), Size: n, SHA256: hex.EncodeToString(h.Sum(nil))})
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return fs, nil
+	}
+	return fs, err
+}
+
+func copyBackupFile(src string, dst string) error {
+	err := os.MkdirAll(filepath.Dir(dst), os.ModeDir|os.ModePerm)
+	if err != nil {
+		return err
+	}
+	sf, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer sf.Close()
+	df, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer df.Close()
+	_, err = io.Copy(df, sf)
+	return err
+}
+
+func copyBackupDir(src string, dst string) error {
+	return filepath.Walk(src, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		r, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		if fi.IsDir() {
+			return os.MkdirAll(filepath.Join(dst, r), os.ModeDir|os.ModePerm)
+		}
+		return copyBackupFile(p, filepath.Join(dst, r))
+	})
+}
+
+// writeBackupArchive writes the directory as the gzip compressed tar
+func writeBackupArchive(dir string, w io.Writer) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	err := filepath.Walk(dir, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		r, err := filepath.Rel(dir, p)
+		if err != nil || r == "." {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(fi, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(r)
+		err = tw.WriteHeader(hdr)
+		if err != nil {
+			return err
+		}
+		if fi.IsDir() {
+			return nil
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	err = tw.Close()
+	if err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// writeBackupFile writes the archive of the directory into the file, the
+// archive is encrypted if the password is provided
+func writeBackupFile(dir string, path string, password string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if password == "" {
+		err = writeBackupArchive(dir, f)
+	} else {
+		var e *backupEncrypter
+		e, err = newBackupEncrypter(f, password)
+		if err != nil {
+			return err
+		}
+		err = writeBackupArchive(dir, e)
+		if err == nil {
+			err = e.Close()
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// readBackupArchive extracts the archive into the directory
+func readBackupArchive(r io.Reader, dir string) error {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("invalid backup archive, %s", err.Error())
+	}
+	defer gr.Close()
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("invalid backup archive, %s", err.Error())
+		}
+		if !isSafeBackupPath(hdr.Name) {
+			return fmt.Errorf("invalid backup archive, unsafe path %s", hdr.Name)
+		}
+		t := filepath.Join(dir, filepath.FromSlash(hdr.Name))
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(t, os.ModeDir|os.ModePerm)
+		case tar.TypeReg:
+			err = os.MkdirAll(filepath.Dir(t), os.ModeDir|os.ModePerm)
+			if err != nil {
+				return err
+			}
+			var f *os.File
+			f, err = os.Create(t)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(f, tr)
+			f.Close()
+		default:
+			return fmt.Errorf("invalid backup archive, unsupported entry %s", hdr.Name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// The encrypted backup starts with the header
+//
+//	magic | version (1) | scrypt logN (1) | scrypt r (4) | scrypt p (4) | salt (16) | nonce prefix (8)
+//
+// followed by the chunks of the archive, each chunk is
+//
+//	final flag (1) | length (4) | AES-GCM sealed chunk
+//
+// The nonce of the chunk is the prefix followed by the chunk counter and the
+// final flag is authenticated, so the reordered or the truncated backup fails.
+const (
+	backupEncVersion  byte   = 1
+	backupScryptLogN  byte   = 15
+	backupScryptR     uint32 = 8
+	backupScryptP     uint32 = 1
+	backupSaltSize    int    = 16
+	backupPrefixSize  int    = 8
+	backupChunkSize   int    = 64 * 1024
+	backupMaxLogN     byte   = 20
+	backupMaxScryptRP uint32 = 64
+)
+
+func backupCipher(password string, salt []byte, logN byte, r uint32, p uint32) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(password), salt, 1<<logN, int(r), int(p), 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func backupNonce(prefix []byte, ctr uint32) []byte {
+	nonce := make([]byte, backupPrefixSize+4)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[backupPrefixSize:], ctr)
+	return nonce
+}
+
+// backupEncrypter encrypts the archive while it is written, Close must be
+// called to write the final chunk
+type backupEncrypter struct {
+	w      io.Writer
+	aead   cipher.AEAD
+	prefix []byte
+	ctr    uint32
+	buf    []byte
+}
+
+func newBackupEncrypter(w io.Writer, password string) (*backupEncrypter, error) {
+	salt := make([]byte, backupSaltSize)
+	prefix := make([]byte, backupPrefixSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	if _, err := io.ReadFull(rand.Reader, prefix); err != nil {
+		return nil, err
+	}
+	aead, err := backupCipher(password, salt, backupScryptLogN, backupScryptR, backupScryptP)
+	if err != nil {
+		return nil, err
+	}
+	hdr := append([]byte(backupEncMagic), backupEncVersion, backupScryptLogN)
+	hdr = binary.BigEndian.AppendUint32(hdr, backupScryptR)
+	hdr = binary.BigEndian.AppendUint32(hdr, backupScryptP)
+	hdr = append(hdr, salt...)
+	hdr = append(hdr, prefix...)
+	if _, err := w.Write(hdr); err != nil {
+		return nil, err
+	}
+	return &backupEncrypter{w: w, aead: aead, prefix: prefix, buf: make([]byte, 0, backupChunkSize)}, nil
+}
+
+func (e *backupEncrypter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		if len(e.buf) == backupChunkSize {
+			if err := e.seal(false); err != nil {
+				return 0, err
+			}
+		}
+		c := copy(e.buf[len(e.buf):backupChunkSize], p)
+		e.buf = e.buf[:len(e.buf)+c]
+		p = p[c:]
+	}
+	return n, nil
+}
+
+// Close writes the final chunk, it does not close the underlying writer
+func (e *backupEncrypter) Close() error {
+	return e.seal(true)
+}
+
+func (e *backupEncrypter) seal(final bool) error {
+	if e.ctr == math.MaxUint32 {
+		return fmt.Errorf("backup is too large to encrypt")
+	}
+	flag := []byte{0}
+	if final {
+		flag[0] = 1
+	}
+	ct := e.aead.Seal(nil, backupNonce(e.prefix, e.ctr), e.buf, flag)
+	e.ctr++
+	e.buf = e.buf[:0]
+	hdr := binary.BigEndian.AppendUint32(flag, uint32(len(ct)))
+	if _, err := e.w.Write(hdr); err != nil {
+		return err
+	}
+	_, err := e.w.Write(ct)
+	return err
+}
+
+// backupDecrypter decrypts the archive while it is read, the end of the
+// archive is reported only after the final chunk is authenticated
+type backupDecrypter struct {
+	r      io.Reader
+	aead   cipher.AEAD
+	prefix []byte
+	ctr    uint32
+	buf    []byte
+	final  bool
+}
+
+func newBackupDecrypter(r io.Reader, password string) (*backupDecrypter, error) {
+	hdr := make([]byte, len(backupEncMagic)+2+8+backupSaltSize+backupPrefixSize)
+	if _, err := io.ReadFull(r, hdr); err != nil {
+		return nil, fmt.Errorf("invalid encrypted backup")
+	}
+	h := hdr[len(backupEncMagic):]
+	if h[0] != backupEncVersion {
+		return nil, fmt.Errorf("unsupported encrypted backup version %d", h[0])
+	}
+	logN := h[1]
+	rp := binary.BigEndian.Uint32(h[2:6])
+	pp := binary.BigEndian.Uint32(h[6:10])
+	if logN == 0 || logN > backupMaxLogN || rp == 0 || pp == 0 || rp*pp > backupMaxScryptRP {
+		return nil, fmt.Errorf("invalid encrypted backup, unsupported key parameters")
+	}
+	salt := h[10 : 10+backupSaltSize]
+	prefix := h[10+backupSaltSize:]
+	aead, err := backupCipher(password, salt, logN, rp, pp)
+	if err != nil {
+		return nil, err
+	}
+	return &backupDecrypter{r: r, aead: aead, prefix: prefix}, nil
+}
+
+func (d *backupDecrypter) Read(p []byte) (int, error) {
+	for len(d.buf) == 0 {
+		if d.final {
+			return 0, io.EOF
+		}
+		if err := d.open(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.buf)
+	d.buf = d.buf[n:]
+	return n, nil
+}
+
+func (d *backupDecrypter) open() error {
+	hdr := make([]byte, 5)
+	if _, err := io.ReadFull(d.r, hdr); err != nil {
+		return fmt.Errorf("invalid encrypted backup, backup is truncated")
+	}
+	l := binary.BigEndian.Uint32(hdr[1:])
+	if hdr[0] > 1 || int(l) > backupChunkSize+d.aead.Overhead() {
+		return fmt.Errorf("invalid encrypted backup")
+	}
+	ct := make([]byte, l)
+	if _, err := io.ReadFull(d.r, ct); err != nil {
+		return fmt.Errorf("invalid encrypted backup, backup is truncated")
+	}
+	plain, err := d.aead.Open(ct[:0], backupNonce(d.prefix, d.ctr), ct, hdr[:1])
+	if err != nil {
+		return fmt.Errorf("failed to decrypt the backup, invalid password")
+	}
+	d.ctr++
+	d.buf = plain
+	if hdr[0] == 1 {
+		d.final = true
+		// nothing must follow the final chunk
+		if n, _ := d.r.Read(make([]byte, 1)); n != 0 {
+			return fmt.Errorf("invalid encrypted backup, unexpected data after the archive")
+		}
+	}
+	return nil
+}
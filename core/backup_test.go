@@ -0,0 +1,130 @@
+package core
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rubixchain/rubixgoplatform/core/config"
+	"github.com/rubixchain/rubixgoplatform/core/storage"
+	"github.com/rubixchain/rubixgoplatform/core/wallet"
+	"github.com/rubixchain/rubixgoplatform/wrapper/logger"
+)
+
+func TestBackupRestore(t *testing.T) {
+	sd, err := ioutil.TempDir("", "backuptest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir", err)
+	}
+	defer os.RemoveAll(sd)
+	item := RubixRootDir + "bafybmitestdid"
+	nd := filepath.Join(sd, BackupNodeDir, item)
+	os.MkdirAll(nd, os.ModeDir|os.ModePerm)
+	ioutil.WriteFile(filepath.Join(nd, "pubKey.pem"), []byte("public key"), 0644)
+	fs, err := backupFiles(filepath.Join(sd, BackupNodeDir))
+	if err != nil {
+		t.Fatal("Failed to get backup files", err)
+	}
+	m := BackupManifest{Version: BackupVersion, Items: []string{item}, Files: fs}
+	mb, _ := json.Marshal(m)
+	ioutil.WriteFile(filepath.Join(sd, BackupManifestFile), mb, 0644)
+	archive := filepath.Join(sd, "backup.tar.gz")
+	err = writeBackupFile(sd, archive, "password")
+	if err != nil {
+		t.Fatal("Failed to write archive", err)
+	}
+	data, _ := ioutil.ReadFile(archive)
+	ioutil.WriteFile(archive+".trunc", data[:len(data)-10], 0600)
+	if _, err := RestoreBackup(archive+".trunc", filepath.Join(sd, "trunc"), "password", "", false); err == nil {
+		t.Fatal("Restored the truncated backup")
+	}
+	rd := filepath.Join(sd, "restore")
+	if _, err := RestoreBackup(archive, rd, "wrong", "", false); err == nil {
+		t.Fatal("Restored with the wrong password")
+	}
+	if _, err := RestoreBackup(archive, rd, "password", "", false); err != nil {
+		t.Fatal("Failed to restore backup", err)
+	}
+	rb, err := ioutil.ReadFile(filepath.Join(rd, item, "pubKey.pem"))
+	if err != nil || string(rb) != "public key" {
+		t.Fatal("Restored file mismatch")
+	}
+	if _, err := RestoreBackup(archive, rd, "password", "", false); err == nil {
+		t.Fatal("Existing node state replaced without force")
+	}
+	if _, err := RestoreBackup(archive, rd, "password", "", true); err != nil {
+		t.Fatal("Failed to restore backup with force", err)
+	}
+	// tampered file must fail the checksum
+	ioutil.WriteFile(filepath.Join(sd, BackupNodeDir, item, "pubKey.pem"), []byte("tampered"), 0644)
+	writeBackupFile(sd, archive, "")
+	if _, err := RestoreBackup(archive, rd, "", "", true); err == nil {
+		t.Fatal("Restored the corrupted backup")
+	}
+}
+
+func TestCoreBackup(t *testing.T) {
+	sd, err := ioutil.TempDir("", "corebackuptest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir", err)
+	}
+	defer os.RemoveAll(sd)
+	dir := sd + "/node/"
+	log := logger.New(&logger.LoggerOptions{Name: "test", Color: []logger.ColorOption{logger.ColorOff}})
+	s, err := storage.NewStorageMemory()
+	if err != nil {
+		t.Fatal("Failed to create storage", err)
+	}
+	w, err := wallet.InitMemoryWallet(s, log)
+	if err != nil {
+		t.Fatal("Failed to init wallet", err)
+	}
+	cfg := &config.Config{DirPath: dir}
+	cfg.CfgData.StorageConfig.StorageType = storage.StorageLDBType
+	c := &Core{
+		cfg:     cfg,
+		s:       s,
+		w:       w,
+		log:     log,
+		cfgFile: dir + "api_config.json",
+		didDir:  dir + RubixRootDir,
+		tcDir:   dir + RubixRootDir + MainNetDir + "/",
+		dbPath:  dir + RubixRootDir + DefaultMainNetLDB,
+	}
+	did := "bafybmitestdid"
+	os.MkdirAll(c.didDir+did, os.ModeDir|os.ModePerm)
+	ioutil.WriteFile(c.didDir+did+"/pubKey.pem", []byte("public key"), 0644)
+	ioutil.WriteFile(c.cfgFile, []byte("config"), 0600)
+	err = w.CreateToken(&wallet.Token{TokenID: "testtoken", DID: did, TokenValue: 1, TokenStatus: wallet.TokenIsFree})
+	if err != nil {
+		t.Fatal("Failed to create token", err)
+	}
+	path, err := c.Backup(sd+"/backup.tar.gz", "password")
+	if err != nil {
+		t.Fatal("Failed to backup", err)
+	}
+	rd := sd + "/restore/"
+	m, err := RestoreBackup(path, rd, "password", "", false)
+	if err != nil {
+		t.Fatal("Failed to restore backup", err)
+	}
+	rb, err := ioutil.ReadFile(rd + RubixRootDir + did + "/pubKey.pem")
+	if err != nil || string(rb) != "public key" {
+		t.Fatal("Restored DID mismatch")
+	}
+	rs, err := storage.NewStorageLDB(rd + m.DBPath)
+	if err != nil {
+		t.Fatal("Failed to open restored DB", err)
+	}
+	defer rs.Close()
+	rw, err := wallet.InitWallet(rs, rd+m.ChainDir+"/", log)
+	if err != nil {
+		t.Fatal("Failed to open restored wallet", err)
+	}
+	tk, err := rw.ReadToken("testtoken")
+	if err != nil || tk.DID != did {
+		t.Fatal("Restored token mismatch", err)
+	}
+}
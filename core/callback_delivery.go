@@ -0,0 +1,352 @@
+package core
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rubixchain/rubixgoplatform/core/model"
+	"github.com/rubixchain/rubixgoplatform/wrapper/uuid"
+)
+
+const (
+	CallbackDeliveryStorage string = "callbackdelivery"
+)
+
+// Callback delivery states
+const (
+	CallbackPending int = iota + 1
+	CallbackDelivered
+	CallbackFailed
+)
+
+const (
+	CallbackMaxAttempts  int           = 8
+	CallbackMinBackoff   time.Duration = 5 * time.Second
+	CallbackMaxBackoff   time.Duration = 10 * time.Minute
+	CallbackTimeout      time.Duration = 10 * time.Second
+	CallbackPollInterval time.Duration = 5 * time.Second
+	CallbackMaxURLs      int           = 8
+)
+
+// Headers of the callback request, the signature is the hex encoded
+// HMAC-SHA256 of the timestamp and the body joined by "." using the secret
+// registered with the callback URL
+const (
+	CallbackSignatureHeader string = "X-Rubix-Signature"
+	CallbackTimestampHeader string = "X-Rubix-Timestamp"
+	CallbackDeliveryHeader  string = "X-Rubix-Delivery"
+)
+
+// CallbackDelivery is the delivery of the smart contract update to the
+// callback URL, the failed attempts are retried with the exponential backoff
+type CallbackDelivery struct {
+	ID           string    `gorm:"column:id;primaryKey" json:"id"`
+	Token        string    `gorm:"column:token" json:"token"`
+	URL          string    `gorm:"column:url" json:"url"`
+	Payload      string    `gorm:"column:payload" json:"payload"`
+	Status       int       `gorm:"column:status" json:"status"`
+	Attempts     int       `gorm:"column:attempts" json:"attempts"`
+	LastError    string    `gorm:"column:last_error" json:"last_error"`
+	NextAttempt  time.Time `gorm:"column:next_attempt" json:"next_attempt"`
+	CreationTime time.Time `gorm:"column:creation_time" json:"creation_time"`
+	UpdateTime   time.Time `gorm:"column:update_time" json:"update_time"`
+}
+
+func (c *Core) initCallbackDelivery() error {
+	c.cbChan = make(chan struct{}, 1)
+	return c.s.Init(CallbackDeliveryStorage, &CallbackDelivery{}, true)
+}
+
+func callbackStatusString(status int) string {
+	switch status {
+	case CallbackPending:
+		return "pending"
+	case CallbackDelivered:
+		return "delivered"
+	case CallbackFailed:
+		return "failed"
+	}
+	return "unknown"
+}
+
+func callbackStatus(status string) int {
+	switch status {
+	case "pending":
+		return CallbackPending
+	case "delivered":
+		return CallbackDelivered
+	case "failed":
+		return CallbackFailed
+	}
+	return 0
+}
+
+// callbackBackoff returns the delay before the next attempt, the delay is
+// doubled on each failed attempt up to the maximum
+func callbackBackoff(attempts int) time.Duration {
+	d := CallbackMinBackoff
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= CallbackMaxBackoff {
+			return CallbackMaxBackoff
+		}
+	}
+	return d
+}
+
+// signCallback returns the signature of the callback body
+func signCallback(secret string, ts string, body []byte) string {
+	m := hmac.New(sha256.New, []byte(secret))
+	m.Write([]byte(ts))
+	m.Write([]byte("."))
+	m.Write(body)
+	return "sha256=" + hex.EncodeToString(m.Sum(nil))
+}
+
+// newCallbackSecret generates the secret of the callback URL
+func newCallbackSecret() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (c *Core) signalCallbacks() {
+	select {
+	case c.cbChan <- struct{}{}:
+	default:
+	}
+}
+
+// queueCallbacks queues the delivery of the payload to all the callback URLs
+// of the smart contract
+func (c *Core) queueCallbacks(token string, payload interface{}) {
+	cbs, err := c.w.GetSmartContractCallBacks(token)
+	if err != nil || len(cbs) == 0 {
+		c.log.Debug("No callback URL registered for the smart contract", "token", token)
+		return
+	}
+	pb, err := json.Marshal(payload)
+	if err != nil {
+		c.log.Error("Failed to marshal callback payload", "err", err)
+		return
+	}
+	st := time.Now()
+	for _, cb := range cbs {
+		cd := CallbackDelivery{
+			ID:           uuid.New().String(),
+			Token:        token,
+			URL:          cb.CallBackUrl,
+			Payload:      string(pb),
+			Status:       CallbackPending,
+			NextAttempt:  st,
+			CreationTime: st,
+			UpdateTime:   st,
+		}
+		err = c.s.Write(CallbackDeliveryStorage, &cd)
+		if err != nil {
+			c.log.Error("Failed to queue callback delivery", "url", cb.CallBackUrl, "err", err)
+		}
+	}
+	c.signalCallbacks()
+}
+
+// callbackWorker delivers the pending callbacks which are due, the pending
+// deliveries of the last run are resumed on the start
+func (c *Core) callbackWorker() {
+	t := time.NewTicker(CallbackPollInterval)
+	defer t.Stop()
+	for {
+		c.deliverCallbacks()
+		select {
+		case <-c.cbChan:
+		case <-t.C:
+		}
+	}
+}
+
+// deliverCallbacks delivers the due callbacks, the URLs are delivered
+// concurrently and the deliveries of the URL in the order. The later
+// deliveries of the URL wait while the earlier one is pending, so the rest of
+// the deliveries of the URL are left for the next pass once an attempt fails
+// and the unreachable URL costs at most one timeout per pass. The delivery
+// which failed for good does not block the later ones.
+func (c *Core) deliverCallbacks() {
+	var cds []CallbackDelivery
+	err := c.s.Read(CallbackDeliveryStorage, &cds, "status=?", CallbackPending)
+	if err != nil || len(cds) == 0 {
+		return
+	}
+	sort.SliceStable(cds, func(i, j int) bool {
+		return cds[i].CreationTime.Before(cds[j].CreationTime)
+	})
+	now := time.Now()
+	urls := make([]string, 0)
+	byURL := make(map[string][]*CallbackDelivery)
+	blocked := make(map[string]bool)
+	for i := range cds {
+		u := cds[i].URL
+		if blocked[u] {
+			continue
+		}
+		// the earlier delivery is in the backoff
+		if cds[i].NextAttempt.After(now) {
+			blocked[u] = true
+			continue
+		}
+		if _, ok := byURL[u]; !ok {
+			urls = append(urls, u)
+		}
+		byURL[u] = append(byURL[u], &cds[i])
+	}
+	sem := make(chan struct{}, CallbackMaxURLs)
+	var wg sync.WaitGroup
+	for _, url := range urls {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(uds []*CallbackDelivery) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			for _, cd := range uds {
+				if !c.deliverCallback(cd) {
+					return
+				}
+			}
+		}(byURL[url])
+	}
+	wg.Wait()
+}
+
+// deliverCallback makes the delivery attempt, returns true if it is delivered
+func (c *Core) deliverCallback(cd *CallbackDelivery) bool {
+	cd.Attempts++
+	err := c.postCallback(cd)
+	cd.UpdateTime = time.Now()
+	if err == nil {
+		cd.Status = CallbackDelivered
+		cd.LastError = ""
+		c.log.Debug("Callback delivered", "token", cd.Token, "url", cd.URL)
+	} else {
+		cd.LastError = err.Error()
+		if cd.Attempts >= CallbackMaxAttempts {
+			cd.Status = CallbackFailed
+			c.log.Error("Callback delivery failed", "token", cd.Token, "url", cd.URL, "attempts", cd.Attempts, "err", err)
+		} else {
+			cd.NextAttempt = cd.UpdateTime.Add(callbackBackoff(cd.Attempts))
+			c.log.Debug("Callback delivery attempt failed", "token", cd.Token, "url", cd.URL, "attempts", cd.Attempts, "err", err)
+		}
+	}
+	err = c.s.Update(CallbackDeliveryStorage, cd, "id=?", cd.ID)
+	if err != nil {
+		c.log.Error("Failed to update callback delivery", "id", cd.ID, "err", err)
+	}
+	return cd.Status == CallbackDelivered
+}
+
+func (c *Core) postCallback(cd *CallbackDelivery) error {
+	body := []byte(cd.Payload)
+	req, err := http.NewRequest("POST", cd.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	req.Header.Set(CallbackTimestampHeader, ts)
+	req.Header.Set(CallbackDeliveryHeader, cd.ID)
+	// callback URLs registered before the signing support have no secret
+	cb := c.w.GetSmartContractCallBack(cd.Token, cd.URL)
+	if cb != nil && cb.Secret != "" {
+		req.Header.Set(CallbackSignatureHeader, signCallback(cb.Secret, ts, body))
+	}
+	client := &http.Client{Timeout: CallbackTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("callback returned status %s", resp.Status)
+	}
+	return nil
+}
+
+// ListCallbackDeliveries returns the callback deliveries of the smart
+// contract with the status, all the deliveries are returned if the token or
+// the status is empty
+func (c *Core) ListCallbackDeliveries(token string, status string) ([]model.CallbackDelivery, error) {
+	q := "id!=?"
+	args := []interface{}{""}
+	if token != "" {
+		q += " AND token=?"
+		args = append(args, token)
+	}
+	if status != "" {
+		st := callbackStatus(status)
+		if st == 0 {
+			return nil, fmt.Errorf("invalid delivery status %s", status)
+		}
+		q += " AND status=?"
+		args = append(args, st)
+	}
+	mcds := make([]model.CallbackDelivery, 0)
+	var cds []CallbackDelivery
+	err := c.s.Read(CallbackDeliveryStorage, &cds, q, args...)
+	if err != nil {
+		return mcds, nil
+	}
+	for _, cd := range cds {
+		mcds = append(mcds, model.CallbackDelivery{
+			ID:           cd.ID,
+			Token:        cd.Token,
+			URL:          cd.URL,
+			Payload:      cd.Payload,
+			Status:       callbackStatusString(cd.Status),
+			Attempts:     cd.Attempts,
+			LastError:    cd.LastError,
+			NextAttempt:  cd.NextAttempt,
+			CreationTime: cd.CreationTime,
+			UpdateTime:   cd.UpdateTime,
+		})
+	}
+	return mcds, nil
+}
+
+// ReplayCallbackDelivery queues the delivery again with the fresh attempts
+func (c *Core) ReplayCallbackDelivery(id string) error {
+	var cd CallbackDelivery
+	err := c.s.Read(CallbackDeliveryStorage, &cd, "id=?", id)
+	if err != nil || cd.ID == "" {
+		return fmt.Errorf("delivery not found")
+	}
+	if cd.Status == CallbackPending {
+		return fmt.Errorf("delivery is already pending")
+	}
+	cd.Status = CallbackPending
+	cd.Attempts = 0
+	cd.LastError = ""
+	cd.NextAttempt = time.Now()
+	cd.UpdateTime = cd.NextAttempt
+	err = c.s.Update(CallbackDeliveryStorage, &cd, "id=?", cd.ID)
+	if err != nil {
+		c.log.Error("Failed to update callback delivery", "id", cd.ID, "err", err)
+		return err
+	}
+	c.signalCallbacks()
+	return nil
+}
@@ -0,0 +1,151 @@
+package core
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rubixchain/rubixgoplatform/core/storage"
+	"github.com/rubixchain/rubixgoplatform/core/wallet"
+	"github.com/rubixchain/rubixgoplatform/wrapper/logger"
+)
+
+func TestCallbackDelivery(t *testing.T) {
+	sig := signCallback("secret", "1700000000", []byte(`{"a":1}`))
+	if sig != "sha256=49f24e537407743fa4a0242bb63b94b9a47ee99cbbe071ccd8a22550ae411686" {
+		t.Fatal("Callback signature mismatch", sig)
+	}
+	if callbackBackoff(1) != CallbackMinBackoff || callbackBackoff(3) != 4*CallbackMinBackoff {
+		t.Fatal("Callback backoff mismatch")
+	}
+	if callbackBackoff(CallbackMaxAttempts*4) != CallbackMaxBackoff {
+		t.Fatal("Callback backoff is not limited")
+	}
+	for _, st := range []int{CallbackPending, CallbackDelivered, CallbackFailed} {
+		if callbackStatus(callbackStatusString(st)) != st {
+			t.Fatal("Callback status mismatch", st)
+		}
+	}
+}
+
+type callbackRequest struct {
+	body []byte
+	hdr  http.Header
+}
+
+func TestCallbackDeliveryHTTP(t *testing.T) {
+	var l sync.Mutex
+	reqs := make([]callbackRequest, 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		l.Lock()
+		reqs = append(reqs, callbackRequest{body: b, hdr: r.Header.Clone()})
+		n := len(reqs)
+		l.Unlock()
+		if n == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+	requests := func() []callbackRequest {
+		l.Lock()
+		defer l.Unlock()
+		return append([]callbackRequest{}, reqs...)
+	}
+	log := logger.New(&logger.LoggerOptions{Name: "test", Color: []logger.ColorOption{logger.ColorOff}})
+	s, err := storage.NewStorageMemory()
+	if err != nil {
+		t.Fatal("Failed to create storage", err)
+	}
+	w, err := wallet.InitMemoryWallet(s, log)
+	if err != nil {
+		t.Fatal("Failed to init wallet", err)
+	}
+	c := &Core{s: s, w: w, log: log}
+	err = c.initCallbackDelivery()
+	if err != nil {
+		t.Fatal("Failed to init callback delivery", err)
+	}
+	err = w.AddSmartContractCallBack(&wallet.SCCallBack{SmartContractHash: "sc1", CallBackUrl: srv.URL, Secret: "secret"})
+	if err != nil {
+		t.Fatal("Failed to add callback", err)
+	}
+	readDelivery := func() CallbackDelivery {
+		var cds []CallbackDelivery
+		err := c.s.Read(CallbackDeliveryStorage, &cds, "token=?", "sc1")
+		if err != nil || len(cds) != 1 {
+			t.Fatal("Failed to read callback delivery", err)
+		}
+		return cds[0]
+	}
+	c.queueCallbacks("sc1", map[string]string{"smart_contract_hash": "sc1"})
+	// first attempt fails and it is retried after the backoff
+	c.deliverCallbacks()
+	cd := readDelivery()
+	if cd.Status != CallbackPending || cd.Attempts != 1 || cd.LastError == "" {
+		t.Fatal("Failed attempt is not recorded", cd)
+	}
+	if d := cd.NextAttempt.Sub(cd.UpdateTime); d != callbackBackoff(1) {
+		t.Fatal("Backoff mismatch", d)
+	}
+	c.deliverCallbacks()
+	if len(requests()) != 1 {
+		t.Fatal("Delivery retried before the backoff")
+	}
+	cd.NextAttempt = time.Now().Add(-time.Second)
+	c.s.Update(CallbackDeliveryStorage, &cd, "id=?", cd.ID)
+	c.deliverCallbacks()
+	cd = readDelivery()
+	if cd.Status != CallbackDelivered || cd.Attempts != 2 || cd.LastError != "" {
+		t.Fatal("Delivery is not recorded", cd)
+	}
+	rs := requests()
+	if len(rs) != 2 {
+		t.Fatal("Invalid number of requests", len(rs))
+	}
+	r := rs[1]
+	if r.hdr.Get(CallbackDeliveryHeader) != cd.ID || string(r.body) != cd.Payload {
+		t.Fatal("Delivery request mismatch")
+	}
+	if r.hdr.Get(CallbackSignatureHeader) != signCallback("secret", r.hdr.Get(CallbackTimestampHeader), r.body) {
+		t.Fatal("Delivery signature mismatch")
+	}
+	mcds, _ := c.ListCallbackDeliveries("sc1", "delivered")
+	if len(mcds) != 1 || mcds[0].ID != cd.ID {
+		t.Fatal("Delivered callback is not listed")
+	}
+	// replay delivers again with the fresh attempts
+	err = c.ReplayCallbackDelivery(cd.ID)
+	if err != nil {
+		t.Fatal("Failed to replay delivery", err)
+	}
+	if cd = readDelivery(); cd.Status != CallbackPending || cd.Attempts != 0 {
+		t.Fatal("Replayed delivery is not pending", cd)
+	}
+	if c.ReplayCallbackDelivery(cd.ID) == nil || c.ReplayCallbackDelivery("unknown") == nil {
+		t.Fatal("Pending or unknown delivery is replayed")
+	}
+	c.deliverCallbacks()
+	if cd = readDelivery(); cd.Status != CallbackDelivered || cd.Attempts != 1 || len(requests()) != 3 {
+		t.Fatal("Replayed delivery is not delivered", cd)
+	}
+	// later delivery to the URL waits for the earlier one in the backoff
+	cd.Status = CallbackPending
+	cd.NextAttempt = time.Now().Add(time.Minute)
+	c.s.Update(CallbackDeliveryStorage, &cd, "id=?", cd.ID)
+	c.queueCallbacks("sc1", map[string]string{"smart_contract_hash": "sc1", "seq": "2"})
+	c.deliverCallbacks()
+	if len(requests()) != 3 {
+		t.Fatal("Later delivery overtakes the pending one")
+	}
+	cd.NextAttempt = time.Now().Add(-time.Second)
+	c.s.Update(CallbackDeliveryStorage, &cd, "id=?", cd.ID)
+	c.deliverCallbacks()
+	rs = requests()
+	if len(rs) != 5 || string(rs[3].body) != cd.Payload || string(rs[4].body) == cd.Payload {
+		t.Fatal("Deliveries are not in order", len(rs))
+	}
+}
@@ -24,6 +24,16 @@ type StorageConfig struct {
 	DBPassword  string `json:"db_password"`
 }
 
+// ConsensusConfig defines the consensus timing, all durations are in seconds
+type ConsensusConfig struct {
+	Timeout            int `json:"timeout"`
+	CreditCheckTimeout int `json:"credit_check_timeout"`
+	PledgeTimeout      int `json:"pledge_timeout"`
+	SignatureTimeout   int `json:"signature_timeout"`
+	ReceiverTimeout    int `json:"receiver_timeout"`
+	PledgeLeaseTimeout int `json:"pledge_lease_timeout"`
+}
+
 // ConfigData defines configuration data
 type ConfigData struct {
 	Ports             Ports             `json:"ports"`
@@ -31,6 +41,15 @@ type ConfigData struct {
 	Services          map[string]string `json:"services"`
 	StorageConfig     StorageConfig     `json:"storage_config"`
 	TestStorageConfig StorageConfig     `json:"test_storage_config"`
+	ConsensusConfig   ConsensusConfig   `json:"consensus_config"`
+	ManualMigration   bool              `json:"manual_migration"`
+	DisableTokenIndex bool              `json:"disable_token_index"`
+	UnpledgeWorkers   int               `json:"unpledge_workers"`
+	CompactProof      bool              `json:"compact_unpledge_proof"`
+	MaxPeerForwards   int               `json:"max_peer_forwards"`
+	SCFuelLimit       uint64            `json:"sc_fuel_limit"`
+	SCMemoryPages     uint32            `json:"sc_memory_pages"`
+	SCMaxStateSize    int               `json:"sc_max_state_size"`
 }
 
 type Config struct {
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,16 +11,18 @@ import (
 	"sync"
 	"time"
 
-	ipfsnode "github.com/ipfs/go-ipfs-api"
 	"github.com/rubixchain/rubixgoplatform/core/config"
+	"github.com/rubixchain/rubixgoplatform/core/ipfsclient"
 	"github.com/rubixchain/rubixgoplatform/core/ipfsport"
 	"github.com/rubixchain/rubixgoplatform/core/pubsub"
 	"github.com/rubixchain/rubixgoplatform/core/service"
 	"github.com/rubixchain/rubixgoplatform/core/storage"
 	"github.com/rubixchain/rubixgoplatform/core/unpledge"
 	"github.com/rubixchain/rubixgoplatform/core/wallet"
+	"github.com/rubixchain/rubixgoplatform/core/wasm"
 	"github.com/rubixchain/rubixgoplatform/did"
 	didm "github.com/rubixchain/rubixgoplatform/did"
+	"github.com/rubixchain/rubixgoplatform/token"
 	"github.com/rubixchain/rubixgoplatform/util"
 	"github.com/rubixchain/rubixgoplatform/wrapper/apiconfig"
 	econfig "github.com/rubixchain/rubixgoplatform/wrapper/config"
@@ -53,9 +56,13 @@ const (
 	RubixRootDir      string = "Rubix/"
 	DefaultMainNetDB  string = "rubix.db"
 	DefaultTestNetDB  string = "rubixtest.db"
+	DefaultMainNetLDB string = "rubix.ldb"
+	DefaultTestNetLDB string = "rubixtest.ldb"
+	ArbitaryLDB       string = "arbitary.ldb"
 	MainNetDir        string = "MainNet"
 	TestNetDir        string = "TestNet"
 	TestNetDIDDir     string = "TestNetDID/"
+	TokenIndexDir     string = "tokenindex"
 )
 
 const (
@@ -78,12 +85,18 @@ type Core struct {
 	ipfsLock      sync.RWMutex
 	qlock         sync.RWMutex
 	rlock         sync.Mutex
-	ipfs          *ipfsnode.Shell
+	leaseLock     sync.Mutex
+	pledging      map[string]bool
+	backupLock    sync.Mutex
+	schLock       sync.Mutex
+	ipfs          ipfsclient.Client
 	ipfsState     bool
 	ipfsChan      chan bool
 	d             *did.DID
 	up            *unpledge.UnPledge
 	didDir        string
+	tcDir         string
+	dbPath        string
 	pm            *ipfsport.PeerManager
 	qm            *QuorumManager
 	l             *ipfsport.Listener
@@ -103,10 +116,14 @@ type Core struct {
 	s             storage.Storage
 	as            storage.Storage
 	srv           *service.Service
+	ti            *token.TokenIndex
+	tiBuilding    bool
 	arbitaryMode  bool
 	arbitaryAddr  []string
 	ec            *ExplorerClient
 	secret        []byte
+	scrt          *wasm.Runtime
+	cbChan        chan struct{}
 }
 
 func InitConfig(configFile string, encKey string, node uint16) error {
@@ -215,6 +232,7 @@ func NewCore(cfg *config.Config, cfgFile string, encKey string, log logger.Logge
 		}
 		tcDir = cfg.DirPath + RubixRootDir + TestNetDir + "/"
 	}
+	c.tcDir = tcDir
 
 	sc := cfg.CfgData.StorageConfig
 	if c.testNet {
@@ -237,6 +255,7 @@ func NewCore(cfg *config.Config, cfgFile string, encKey string, log logger.Logge
 			c.log.Error("Failed to create storage DB", "err", err)
 			return nil, fmt.Errorf("failed to create storage DB")
 		}
+		c.dbPath = sc.DBAddress
 		if c.arbitaryMode {
 			scfg.DBName = "ArbitaryDB"
 			c.as, err = storage.NewStorageDB(scfg)
@@ -245,31 +264,102 @@ func NewCore(cfg *config.Config, cfgFile string, encKey string, log logger.Logge
 				return nil, fmt.Errorf("failed to create storage DB")
 			}
 		}
+	case storage.StorageLDBType:
+		dbDir := sc.DBAddress
+		if dbDir == "" {
+			dbDir = cfg.DirPath + RubixRootDir + DefaultMainNetLDB
+			if c.testNet {
+				dbDir = cfg.DirPath + RubixRootDir + DefaultTestNetLDB
+			}
+		}
+		c.s, err = storage.NewStorageLDB(dbDir)
+		if err != nil {
+			c.log.Error("Failed to create storage LDB", "err", err)
+			return nil, fmt.Errorf("failed to create storage LDB")
+		}
+		c.dbPath = dbDir
+		if c.arbitaryMode {
+			c.as, err = storage.NewStorageLDB(cfg.DirPath + RubixRootDir + ArbitaryLDB)
+			if err != nil {
+				c.log.Error("Failed to create storage LDB", "err", err)
+				return nil, fmt.Errorf("failed to create storage LDB")
+			}
+		}
+	case storage.StorageMemType:
+		c.s, err = storage.NewStorageMemory()
+		if err != nil {
+			c.log.Error("Failed to create memory storage", "err", err)
+			return nil, fmt.Errorf("failed to create memory storage")
+		}
+		if c.arbitaryMode {
+			c.as, err = storage.NewStorageMemory()
+			if err != nil {
+				c.log.Error("Failed to create memory storage", "err", err)
+				return nil, fmt.Errorf("failed to create memory storage")
+			}
+		}
 	default:
 		c.log.Error("Unsupported DB type, please check the configuration", "type", sc.StorageType)
 		return nil, fmt.Errorf("unsupported DB type, please check the configuration")
 	}
 
-	c.w, err = wallet.InitWallet(c.s, tcDir, c.log)
+	if sc.StorageType == storage.StorageMemType {
+		c.w, err = wallet.InitMemoryWallet(c.s, c.log)
+	} else {
+		c.w, err = wallet.InitWallet(c.s, tcDir, c.log)
+	}
 	if err != nil {
 		c.log.Error("Failed to setup wallet", "err", err)
 		return nil, err
 	}
+	if !cfg.CfgData.ManualMigration {
+		_, err = c.w.RunMigrations()
+		if err != nil {
+			c.log.Error("Failed to migrate wallet", "err", err)
+			return nil, err
+		}
+	}
+	err = c.initTokenIndex()
+	if err != nil {
+		c.log.Error("Failed to open token index", "err", err)
+		return nil, err
+	}
 	c.qm, err = NewQuorumManager(c.s, c.log)
 	if err != nil {
 		c.log.Error("Failed to setup quorum manager", "err", err)
 		return nil, err
 	}
+	err = c.initPledgeLease()
+	if err != nil {
+		c.log.Error("Failed to init pledge lease storage", "err", err)
+		return nil, err
+	}
+	err = c.initTransferJournal()
+	if err != nil {
+		c.log.Error("Failed to init transfer journal storage", "err", err)
+		return nil, err
+	}
+	err = c.initSCHistory()
+	if err != nil {
+		c.log.Error("Failed to init smart contract history storage", "err", err)
+		return nil, err
+	}
+	err = c.initCallbackDelivery()
+	if err != nil {
+		c.log.Error("Failed to init callback delivery storage", "err", err)
+		return nil, err
+	}
 	err = util.CreateDir(c.cfg.DirPath + "unpledge")
 	if err != nil {
 		c.log.Error("Failed to create unpledge", "err", err)
 		return nil, err
 	}
-	c.up, err = unpledge.InitUnPledge(c.s, c.w, c.testNet, c.cfg.DirPath+"unpledge/", c.Unpledge, c.log)
+	c.up, err = unpledge.InitUnPledge(c.s, c.w, c.testNet, c.cfg.DirPath+"unpledge/", c.cfg.CfgData.UnpledgeWorkers, c.Unpledge, c.log)
 	if err != nil {
 		c.log.Error("Failed to init unpledge", "err", err)
 		return nil, err
 	}
+	c.up.SetCompactProof(c.cfg.CfgData.CompactProof)
 	if c.arbitaryMode {
 		c.srv, err = service.NewService(c.s, c.as, c.log)
 		if err != nil {
@@ -283,6 +373,16 @@ func NewCore(cfg *config.Config, cfgFile string, encKey string, log logger.Logge
 		c.log.Error("Failed to init explorer", "err", err)
 		return nil, err
 	}
+	lim := &wasm.Limits{
+		Fuel:         cfg.CfgData.SCFuelLimit,
+		MemoryPages:  cfg.CfgData.SCMemoryPages,
+		MaxStateSize: cfg.CfgData.SCMaxStateSize,
+	}
+	c.scrt, err = wasm.NewRuntime(c.log, lim)
+	if err != nil {
+		c.log.Error("Failed to init smart contract runtime", "err", err)
+		return nil, err
+	}
 	return c, nil
 }
 
@@ -304,8 +404,9 @@ func (c *Core) SetupCore() error {
 		bs = nil
 	}
 	c.pm = ipfsport.NewPeerManager(c.cfg.CfgData.Ports.ReceiverPort+11, c.cfg.CfgData.Ports.ReceiverPort+10, 5000, c.ipfs, c.log, bs, c.peerID)
+	c.pm.SetMaxPeerForwards(c.cfg.CfgData.MaxPeerForwards)
 	c.d = did.InitDID(c.didDir, c.log, c.ipfs)
-	c.ps, err = pubsub.NewPubSub(c.ipfs, c.log)
+	c.ps, err = pubsub.NewPubSub(pubsub.NewIPFSTransport(c.ipfs), c.verifyPubSub, c.log)
 	if err != nil {
 		return err
 	}
@@ -317,7 +418,6 @@ func (c *Core) SetupCore() error {
 	c.w.SetupWallet(c.ipfs)
 	c.PingSetup()
 	c.peerSetup()
-	c.w.AddDIDLastChar()
 	c.SetupToken()
 	c.QuroumSetup()
 	c.PinService()
@@ -347,6 +447,14 @@ func (c *Core) Start() (bool, string) {
 		c.log.Error("failed to start ping port", "err", err)
 		return false, "Failed to start ping port"
 	}
+	// complete or roll back the transfers interrupted by the last shutdown
+	c.reconcileTransfers()
+	go c.callbackWorker()
+	// subscriptions are lost on the restart, subscribe them again
+	c.resumeContractSubscriptions()
+	if !c.cfg.CfgData.DisableTokenIndex {
+		go c.buildTokenIndex()
+	}
 	// exp := model.ExploreModel{
 	// 	Cmd:    ExpPeerStatusCmd,
 	// 	PeerID: c.peerID,
@@ -394,10 +502,17 @@ func (c *Core) StopCore() {
 	// 	return
 	// }
 	time.Sleep(time.Second)
+	if c.pm != nil {
+		c.pm.Close()
+	}
 	c.stopIPFS()
 	if c.l != nil {
 		c.l.Shutdown()
 	}
+	c.closeTokenIndex()
+	if c.scrt != nil {
+		c.scrt.Close()
+	}
 }
 
 func (c *Core) CreateTempFolder() (string, error) {
@@ -449,6 +564,7 @@ func (c *Core) updateConfig() error {
 func (c *Core) AddWebReq(req *ensweb.Request) {
 	c.rlock.Lock()
 	defer c.rlock.Unlock()
+	ctx, cancel := context.WithCancel(context.Background())
 	c.webReq[req.ID] = &did.DIDChan{
 		ID:      req.ID,
 		InChan:  make(chan interface{}),
@@ -456,6 +572,8 @@ func (c *Core) AddWebReq(req *ensweb.Request) {
 		Finish:  make(chan bool),
 		Req:     req,
 		Timeout: 3 * time.Minute,
+		Ctx:     ctx,
+		Cancel:  cancel,
 	}
 }
 
@@ -488,9 +606,31 @@ func (c *Core) RemoveWebReq(reqID string) *ensweb.Request {
 		return nil
 	}
 	delete(c.webReq, reqID)
+	if req.Cancel != nil {
+		req.Cancel()
+	}
 	return req.Req
 }
 
+// CancelWebReq will cancel the request context, all the peer calls made
+// on behalf of the request are aborted
+func (c *Core) CancelWebReq(reqID string) {
+	dc := c.GetWebReq(reqID)
+	if dc != nil && dc.Cancel != nil {
+		c.log.Info("Cancelling the request", "id", reqID)
+		dc.Cancel()
+	}
+}
+
+// webReqContext returns the context bound to the request
+func (c *Core) webReqContext(reqID string) context.Context {
+	dc := c.GetWebReq(reqID)
+	if dc == nil || dc.Ctx == nil {
+		return context.Background()
+	}
+	return dc.Ctx
+}
+
 func (c *Core) SetupDID(reqID string, didStr string) (did.DIDCrypto, error) {
 	dt, err := c.w.GetDID(didStr)
 	c.log.Debug("dt is", "dt", dt)
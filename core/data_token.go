@@ -321,7 +321,7 @@ func (c *Core) commitDataToken(reqID string, did string, batchID string) *model.
 		SenderPeerID:  c.peerID,
 		ContractBlock: sc.GetBlock(),
 	}
-	td, pl, err := c.initiateConsensus(cr, sc, dc)
+	td, pl, err := c.initiateConsensus(c.webReqContext(reqID), cr, sc, dc)
 	if err != nil {
 		c.log.Error("Consensus failed", "err", err)
 		br.Message = "Consensus failed" + err.Error()
@@ -143,12 +143,26 @@ func (c *Core) RegisterCallBackURL(registerReq *model.RegisterCallBackUrlReq) *m
 	reply := &model.BasicResponse{
 		Status: false,
 	}
-	input := &wallet.CallBackUrl{
+	if registerReq.SmartContractToken == "" || registerReq.CallBackURL == "" {
+		reply.Message = "Smart contract token and call back url are required"
+		return reply
+	}
+	secret := registerReq.Secret
+	if secret == "" {
+		var err error
+		secret, err = newCallbackSecret()
+		if err != nil {
+			reply.Message = "Failed to generate call back secret"
+			return reply
+		}
+	}
+	input := &wallet.SCCallBack{
 		SmartContractHash: registerReq.SmartContractToken,
 		CallBackUrl:       registerReq.CallBackURL,
+		Secret:            secret,
 		CreatedAt:         time.Now(),
 	}
-	err := c.w.WriteCallBackUrlToDB(input)
+	err := c.w.AddSmartContractCallBack(input)
 	if err != nil {
 		reply.Message = "Failed to register call back url to DB"
 		return reply
@@ -156,5 +170,7 @@ func (c *Core) RegisterCallBackURL(registerReq *model.RegisterCallBackUrlReq) *m
 	c.log.Debug("Call back URL registered successfully")
 	reply.Status = true
 	reply.Message = "Call back URL registered successfully"
+	// secret is returned to verify the callback signatures
+	reply.Result = secret
 	return reply
 }
@@ -203,7 +203,7 @@ func (c *Core) registerDID(reqID string, did string) error {
 		Signature: sig,
 		Time:      t,
 	}
-	err = c.publishPeerMap(pm)
+	err = c.publishPeerMap(dc, pm)
 	if err != nil {
 		c.log.Error("Register DID, failed to publish peer did map", "err", err)
 		return err
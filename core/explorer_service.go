@@ -5,12 +5,13 @@ import (
 	"time"
 
 	"github.com/rubixchain/rubixgoplatform/core/model"
+	"github.com/rubixchain/rubixgoplatform/did"
 	"github.com/rubixchain/rubixgoplatform/wrapper/uuid"
 )
 
-func (c *Core) PublishExplorer(exp *model.ExploreModel) error {
+func (c *Core) PublishExplorer(dc did.DIDCrypto, exp *model.ExploreModel) error {
 	if c.ps != nil {
-		err := c.ps.Publish(ExplorerService, exp)
+		err := c.ps.Publish(ExplorerService, dc, exp)
 		if err != nil {
 			c.log.Error("Failed to publish message to explorer", "err", err)
 			return err
@@ -19,7 +20,7 @@ func (c *Core) PublishExplorer(exp *model.ExploreModel) error {
 	return nil
 }
 
-func (c *Core) exploreCallback(peerID string, topic string, data []byte) {
+func (c *Core) exploreCallback(peerID string, signer string, topic string, data []byte) {
 	c.lock.Lock()
 	sd, ok := c.sd[ExplorerService]
 	c.lock.Unlock()
@@ -58,6 +59,11 @@ func (c *Core) exploreCallback(peerID string, topic string, data []byte) {
 	case ExpDIDPeerMapCmd:
 		var didMap ExplorerNodeDIDMap
 		for _, did := range exp.DIDList {
+			// only the signer can map its DID to the peer
+			if did != signer {
+				c.log.Error("DID map is not published by the DID owner", "did", did, "peer", peerID)
+				continue
+			}
 			err := sd.db.FindNew(uuid.Nil, NodeDIDMapTable, "DID=?", &didMap, did)
 			if err != nil {
 				didMap.DID = did
@@ -2,7 +2,6 @@ package core
 
 import (
 	"bufio"
-	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -12,7 +11,7 @@ import (
 	"strings"
 	"time"
 
-	ipfsnode "github.com/ipfs/go-ipfs-api"
+	"github.com/rubixchain/rubixgoplatform/core/ipfsclient"
 	"github.com/rubixchain/rubixgoplatform/util"
 )
 
@@ -80,17 +79,18 @@ func (c *Core) initIPFS(ipfsdir string) error {
 		}
 		time.Sleep(1 * time.Second)
 		c.runIPFS()
-		c.ipfs = ipfsnode.NewLocalShell()
-		if c.ipfs == nil {
+		sc := ipfsclient.NewLocalShellClient()
+		if sc == nil {
 			c.log.Error("failed create ipfs shell")
 			return fmt.Errorf("failed create ipfs shell")
 		}
-		_, err = c.ipfs.BootstrapRmAll()
+		c.ipfs = sc
+		err = c.ipfs.BootstrapRmAll()
 		if err != nil {
 			c.log.Error("unable to remove bootstrap", "err", err)
 			return err
 		}
-		_, err = c.ipfs.BootstrapAdd(c.cfg.CfgData.BootStrap)
+		err = c.ipfs.BootstrapAdd(c.cfg.CfgData.BootStrap)
 		if err != nil {
 			c.log.Error("unable to add bootstrap", "err", err)
 			return err
@@ -121,17 +121,7 @@ func (c *Core) initIPFS(ipfsdir string) error {
 
 // configIPFS will configure IPFS
 func (c *Core) configIPFS() error {
-
-	req := c.ipfs.Request("config", "Experimental.Libp2pStreamMounting", "true")
-	resp, err := req.Option("bool", true).Send(context.Background())
-	if err != nil {
-		return err
-	}
-	defer resp.Close()
-	if resp.Error != nil {
-		return resp.Error
-	}
-	return nil
+	return c.ipfs.EnableStreamMounting()
 }
 
 // runIPFS will run the IPFS
@@ -198,20 +188,33 @@ func (c *Core) RunIPFS() error {
 
 	c.runIPFS()
 
-	c.ipfs = ipfsnode.NewLocalShell()
-
-	if c.ipfs == nil {
+	sc := ipfsclient.NewLocalShellClient()
+	if sc == nil {
 		c.log.Error("failed create ipfs shell")
 		return fmt.Errorf("failed create ipfs shell")
 	}
+	c.ipfs = sc
 
-	idoutput, err := c.ipfs.ID()
+	id, err := c.ipfs.ID()
 	if err != nil {
 		c.log.Error("unable to get peer id", "err", err)
 		return err
 	}
-	c.peerID = idoutput.ID
-	c.log.Info("Node PeerID : " + idoutput.ID)
+	c.peerID = id
+	c.log.Info("Node PeerID : " + id)
+	return nil
+}
+
+// UseIPFSClient will use the IPFS client instead of running the IPFS daemon,
+// used by the tests with the fake IPFS network
+func (c *Core) UseIPFSClient(ipfs ipfsclient.Client) error {
+	id, err := ipfs.ID()
+	if err != nil {
+		c.log.Error("unable to get peer id", "err", err)
+		return err
+	}
+	c.ipfs = ipfs
+	c.peerID = id
 	return nil
 }
 
@@ -250,7 +253,7 @@ func (c *Core) AddBootStrap(peers []string) error {
 	if err != nil {
 		return err
 	}
-	_, err = c.ipfs.BootstrapAdd(peers)
+	err = c.ipfs.BootstrapAdd(peers)
 	return err
 }
 
@@ -276,12 +279,12 @@ func (c *Core) RemoveBootStrap(peers []string) error {
 		if err != nil {
 			return err
 		}
-		_, err = c.ipfs.BootstrapRmAll()
+		err = c.ipfs.BootstrapRmAll()
 		if err != nil {
 			return err
 		}
 		if len(c.cfg.CfgData.BootStrap) != 0 {
-			_, err = c.ipfs.BootstrapAdd(c.cfg.CfgData.BootStrap)
+			err = c.ipfs.BootstrapAdd(c.cfg.CfgData.BootStrap)
 		}
 		return err
 	}
@@ -294,7 +297,7 @@ func (c *Core) RemoveAllBootStrap() error {
 	if err != nil {
 		return err
 	}
-	_, err = c.ipfs.BootstrapRmAll()
+	err = c.ipfs.BootstrapRmAll()
 	if err != nil {
 		return err
 	}
@@ -306,26 +309,15 @@ func (c *Core) GetAllBootStrap() []string {
 }
 
 func (c *Core) GetDHTddrs(cid string) ([]string, error) {
-	cmd := exec.Command(c.ipfsApp, "dht", "findprovs", cid)
-	stdout, err := cmd.StdoutPipe()
-	if err != nil {
-		c.log.Error("failed to open command stdout", "err", err)
-		return nil, err
-	}
-	err = cmd.Start()
+	pl, err := c.ipfs.FindProviders(cid)
 	if err != nil {
-		c.log.Error("failed to start command", "err", err)
+		c.log.Error("failed to find the providers", "err", err)
 		return nil, err
 	}
 	ids := make([]string, 0)
-	scanner := bufio.NewScanner(stdout)
-	for scanner.Scan() {
-		m := scanner.Text()
-		if strings.Contains(m, "Error") {
-			return nil, fmt.Errorf(m)
-		}
-		if !strings.HasPrefix(m, "Qm") {
-			ids = append(ids, m)
+	for _, id := range pl {
+		if !strings.HasPrefix(id, "Qm") {
+			ids = append(ids, id)
 		}
 	}
 	return ids, nil
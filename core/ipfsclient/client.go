@@ -0,0 +1,83 @@
+package ipfsclient
+
+import (
+	"context"
+	"io"
+)
+
+// Message is the pubsub message received from the IPFS node
+type Message struct {
+	From string
+	Data []byte
+}
+
+// Subscription is the pubsub topic subscription, Next blocks till the message
+// is received and returns error once the subscription is cancelled
+type Subscription interface {
+	Next() (*Message, error)
+	Cancel() error
+}
+
+// AddOptions are the options of the content add
+type AddOptions struct {
+	Pin      bool
+	OnlyHash bool
+}
+
+type AddOpt func(o *AddOptions)
+
+// Pin pins the added content, content is pinned by default
+func Pin(enabled bool) AddOpt {
+	return func(o *AddOptions) {
+		o.Pin = enabled
+	}
+}
+
+// OnlyHash only calculates the hash, content is not stored
+func OnlyHash(enabled bool) AddOpt {
+	return func(o *AddOptions) {
+		o.OnlyHash = enabled
+	}
+}
+
+func addOptions(opts ...AddOpt) *AddOptions {
+	o := &AddOptions{
+		Pin: true,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Client is the IPFS node calls used by the core, the shell client is used
+// by the node and the fake network is used by the tests.
+type Client interface {
+	// ID returns the peer ID of the node
+	ID() (string, error)
+	Add(r io.Reader, opts ...AddOpt) (string, error)
+	// AddDir adds the directory recursively with CID v1 & SHA3-256, used for
+	// the DID
+	AddDir(dir string) (string, error)
+	Cat(path string) (io.ReadCloser, error)
+	// Get writes the content to the outdir, a file is written inside the
+	// outdir if the outdir is an existing directory
+	Get(hash string, outdir string) error
+	Pin(hash string) error
+	Unpin(hash string) error
+	Provide(hash string) error
+	FindProviders(hash string) ([]string, error)
+	PubSubSubscribe(topic string) (Subscription, error)
+	PubSubPublish(topic string, data []byte) error
+	// P2PListen forwards the incoming streams of the protocol to the address
+	P2PListen(proto string, addr string) error
+	// P2PForward forwards the connections on the local address to the peer
+	P2PForward(proto string, addr string, peerID string) error
+	P2PClose(addr string) error
+	// P2PListeners returns the local addresses of the forwards
+	P2PListeners() ([]string, error)
+	SwarmConnect(ctx context.Context, addr string) error
+	BootstrapAdd(peers []string) error
+	BootstrapRmAll() error
+	EnableStreamMounting() error
+}
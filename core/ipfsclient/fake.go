@@ -0,0 +1,448 @@
+package ipfsclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base32"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/sha3"
+)
+
+const base58Alphabet string = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var cidEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// FakeNetwork is the in-process IPFS network, the content added by any of the
+// nodes is available to all the nodes and the p2p forwards are plain TCP
+// proxies to the listener of the target node.
+type FakeNetwork struct {
+	l       sync.Mutex
+	nodes   map[string]*FakeNode
+	objects map[string]*fakeObject
+	subs    map[string]map[*fakeSubscription]bool
+}
+
+// FakeNode is the IPFS node of the fake network
+type FakeNode struct {
+	net       *FakeNetwork
+	peerID    string
+	blocks    map[string]bool
+	pins      map[string]bool
+	listeners map[string]string
+	forwards  map[string]net.Listener
+	bootstrap []string
+}
+
+type fakeObject struct {
+	data  []byte
+	files map[string][]byte
+}
+
+type fakeSubscription struct {
+	net   *FakeNetwork
+	topic string
+	ch    chan *Message
+	done  chan struct{}
+	once  sync.Once
+}
+
+func NewFakeNetwork() *FakeNetwork {
+	return &FakeNetwork{
+		nodes:   make(map[string]*FakeNode),
+		objects: make(map[string]*fakeObject),
+		subs:    make(map[string]map[*fakeSubscription]bool),
+	}
+}
+
+// NewNode adds the node with the peer ID to the network
+func (fn *FakeNetwork) NewNode(peerID string) *FakeNode {
+	fn.l.Lock()
+	defer fn.l.Unlock()
+	n := &FakeNode{
+		net:       fn,
+		peerID:    peerID,
+		blocks:    make(map[string]bool),
+		pins:      make(map[string]bool),
+		listeners: make(map[string]string),
+		forwards:  make(map[string]net.Listener),
+	}
+	fn.nodes[peerID] = n
+	return n
+}
+
+func base58Encode(b []byte) string {
+	n := new(big.Int).SetBytes(b)
+	r := new(big.Int)
+	base := big.NewInt(58)
+	out := make([]byte, 0)
+	for n.Sign() > 0 {
+		n.DivMod(n, base, r)
+		out = append(out, base58Alphabet[r.Int64()])
+	}
+	for _, c := range b {
+		if c != 0 {
+			break
+		}
+		out = append(out, base58Alphabet[0])
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+// fileCID returns the CID v0 of the data
+func fileCID(data []byte) string {
+	h := sha256.Sum256(data)
+	return base58Encode(append([]byte{0x12, 0x20}, h[:]...))
+}
+
+// dirCID returns the CID v1 with SHA3-256 of the directory files
+func dirCID(fs map[string][]byte) string {
+	names := make([]string, 0, len(fs))
+	for name := range fs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	h := sha3.New256()
+	for _, name := range names {
+		h.Write([]byte(name))
+		h.Write([]byte{0})
+		h.Write(fs[name])
+	}
+	return "b" + cidEncoding.EncodeToString(append([]byte{0x01, 0x70, 0x16, 0x20}, h.Sum(nil)...))
+}
+
+// tcpAddr converts the multiaddr to the TCP address
+func tcpAddr(addr string) (string, error) {
+	ss := strings.Split(strings.Trim(addr, "/"), "/")
+	if len(ss) != 4 || ss[0] != "ip4" || ss[2] != "tcp" {
+		return "", fmt.Errorf("unsupported address %s", addr)
+	}
+	return ss[1] + ":" + ss[3], nil
+}
+
+func (n *FakeNode) store(cid string, obj *fakeObject, pin bool) {
+	n.net.l.Lock()
+	defer n.net.l.Unlock()
+	n.net.objects[cid] = obj
+	n.blocks[cid] = true
+	if pin {
+		n.pins[cid] = true
+	}
+}
+
+// fetch returns the object if any of the nodes has it, the object is cached
+// by the node
+func (n *FakeNode) fetch(hash string) (*fakeObject, error) {
+	hash = strings.TrimPrefix(hash, "/ipfs/")
+	n.net.l.Lock()
+	defer n.net.l.Unlock()
+	obj, ok := n.net.objects[hash]
+	if !ok {
+		return nil, fmt.Errorf("content %s not found", hash)
+	}
+	found := false
+	for _, pn := range n.net.nodes {
+		if pn.blocks[hash] {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("content %s not found", hash)
+	}
+	n.blocks[hash] = true
+	return obj, nil
+}
+
+func (n *FakeNode) ID() (string, error) {
+	return n.peerID, nil
+}
+
+func (n *FakeNode) Add(r io.Reader, opts ...AddOpt) (string, error) {
+	o := addOptions(opts...)
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	cid := fileCID(data)
+	if !o.OnlyHash {
+		n.store(cid, &fakeObject{data: data}, o.Pin)
+	}
+	return cid, nil
+}
+
+func (n *FakeNode) AddDir(dir string) (string, error) {
+	fs := make(map[string][]byte)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		fs[filepath.ToSlash(rel)] = data
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	cid := dirCID(fs)
+	n.store(cid, &fakeObject{files: fs}, true)
+	return cid, nil
+}
+
+func (n *FakeNode) Cat(path string) (io.ReadCloser, error) {
+	obj, err := n.fetch(path)
+	if err != nil {
+		return nil, err
+	}
+	if obj.files != nil {
+		return nil, fmt.Errorf("this dag node is a directory")
+	}
+	return ioutil.NopCloser(bytes.NewReader(obj.data)), nil
+}
+
+func (n *FakeNode) Get(hash string, outdir string) error {
+	obj, err := n.fetch(hash)
+	if err != nil {
+		return err
+	}
+	if obj.files == nil {
+		fp := outdir
+		st, err := os.Stat(outdir)
+		if err == nil && st.IsDir() {
+			fp = filepath.Join(outdir, hash)
+		}
+		return ioutil.WriteFile(fp, obj.data, 0644)
+	}
+	for name, data := range obj.files {
+		fp := filepath.Join(outdir, filepath.FromSlash(name))
+		err := os.MkdirAll(filepath.Dir(fp), 0755)
+		if err != nil {
+			return err
+		}
+		err = ioutil.WriteFile(fp, data, 0644)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *FakeNode) Pin(hash string) error {
+	_, err := n.fetch(hash)
+	if err != nil {
+		return err
+	}
+	n.net.l.Lock()
+	defer n.net.l.Unlock()
+	n.pins[hash] = true
+	return nil
+}
+
+func (n *FakeNode) Unpin(hash string) error {
+	n.net.l.Lock()
+	defer n.net.l.Unlock()
+	if !n.pins[hash] {
+		return fmt.Errorf("not pinned or pinned indirectly")
+	}
+	delete(n.pins, hash)
+	delete(n.blocks, hash)
+	return nil
+}
+
+func (n *FakeNode) Provide(hash string) error {
+	_, err := n.fetch(hash)
+	return err
+}
+
+func (n *FakeNode) FindProviders(hash string) ([]string, error) {
+	n.net.l.Lock()
+	defer n.net.l.Unlock()
+	ids := make([]string, 0)
+	for id, pn := range n.net.nodes {
+		if pn.blocks[hash] {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func (n *FakeNode) PubSubSubscribe(topic string) (Subscription, error) {
+	fs := &fakeSubscription{
+		net:   n.net,
+		topic: topic,
+		ch:    make(chan *Message, 100),
+		done:  make(chan struct{}),
+	}
+	n.net.l.Lock()
+	defer n.net.l.Unlock()
+	if n.net.subs[topic] == nil {
+		n.net.subs[topic] = make(map[*fakeSubscription]bool)
+	}
+	n.net.subs[topic][fs] = true
+	return fs, nil
+}
+
+func (n *FakeNode) PubSubPublish(topic string, data []byte) error {
+	n.net.l.Lock()
+	defer n.net.l.Unlock()
+	for fs := range n.net.subs[topic] {
+		m := &Message{From: n.peerID, Data: append([]byte{}, data...)}
+		select {
+		case fs.ch <- m:
+		case <-fs.done:
+		default:
+			return fmt.Errorf("subscription queue is full")
+		}
+	}
+	return nil
+}
+
+func (fs *fakeSubscription) Next() (*Message, error) {
+	select {
+	case m := <-fs.ch:
+		return m, nil
+	case <-fs.done:
+		return nil, fmt.Errorf("subscription cancelled")
+	}
+}
+
+func (fs *fakeSubscription) Cancel() error {
+	fs.once.Do(func() {
+		fs.net.l.Lock()
+		delete(fs.net.subs[fs.topic], fs)
+		fs.net.l.Unlock()
+		close(fs.done)
+	})
+	return nil
+}
+
+func (n *FakeNode) P2PListen(proto string, addr string) error {
+	ta, err := tcpAddr(addr)
+	if err != nil {
+		return err
+	}
+	n.net.l.Lock()
+	defer n.net.l.Unlock()
+	n.listeners[proto] = ta
+	return nil
+}
+
+func (n *FakeNode) P2PForward(proto string, addr string, peerID string) error {
+	la, err := tcpAddr(addr)
+	if err != nil {
+		return err
+	}
+	n.net.l.Lock()
+	_, ok := n.net.nodes[peerID]
+	n.net.l.Unlock()
+	if !ok {
+		return fmt.Errorf("peer %s not found", peerID)
+	}
+	ln, err := net.Listen("tcp", la)
+	if err != nil {
+		return err
+	}
+	n.net.l.Lock()
+	n.forwards[addr] = ln
+	n.net.l.Unlock()
+	go n.runForward(ln, proto, peerID)
+	return nil
+}
+
+func (n *FakeNode) runForward(ln net.Listener, proto string, peerID string) {
+	for {
+		c, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		n.net.l.Lock()
+		ta := n.net.nodes[peerID].listeners[proto]
+		n.net.l.Unlock()
+		if ta == "" {
+			c.Close()
+			continue
+		}
+		go func() {
+			defer c.Close()
+			tc, err := net.Dial("tcp", ta)
+			if err != nil {
+				return
+			}
+			defer tc.Close()
+			go io.Copy(tc, c)
+			io.Copy(c, tc)
+		}()
+	}
+}
+
+func (n *FakeNode) P2PClose(addr string) error {
+	n.net.l.Lock()
+	ln, ok := n.forwards[addr]
+	delete(n.forwards, addr)
+	n.net.l.Unlock()
+	if !ok {
+		return fmt.Errorf("forward %s not found", addr)
+	}
+	return ln.Close()
+}
+
+func (n *FakeNode) P2PListeners() ([]string, error) {
+	n.net.l.Lock()
+	defer n.net.l.Unlock()
+	addrs := make([]string, 0, len(n.forwards))
+	for addr := range n.forwards {
+		addrs = append(addrs, addr)
+	}
+	return addrs, nil
+}
+
+// SwarmConnect connects to the node of the last peer ID in the address
+func (n *FakeNode) SwarmConnect(ctx context.Context, addr string) error {
+	ss := strings.Split(addr, "/")
+	peerID := ss[len(ss)-1]
+	n.net.l.Lock()
+	defer n.net.l.Unlock()
+	if _, ok := n.net.nodes[peerID]; !ok {
+		return fmt.Errorf("failed to dial %s", peerID)
+	}
+	return nil
+}
+
+func (n *FakeNode) BootstrapAdd(peers []string) error {
+	n.net.l.Lock()
+	defer n.net.l.Unlock()
+	n.bootstrap = append(n.bootstrap, peers...)
+	return nil
+}
+
+func (n *FakeNode) BootstrapRmAll() error {
+	n.net.l.Lock()
+	defer n.net.l.Unlock()
+	n.bootstrap = nil
+	return nil
+}
+
+func (n *FakeNode) EnableStreamMounting() error {
+	return nil
+}
@@ -0,0 +1,126 @@
+package ipfsclient
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestFakeNetwork(t *testing.T) {
+	fn := NewFakeNetwork()
+	var n1, n2 Client = fn.NewNode("peer1"), fn.NewNode("peer2")
+	data := []byte("test content")
+	h, err := n1.Add(bytes.NewReader(data), OnlyHash(true))
+	if err != nil {
+		t.Fatal("Failed to calculate hash", err)
+	}
+	_, err = n2.Cat(h)
+	if err == nil {
+		t.Fatal("Only hash content is stored")
+	}
+	hash, err := n1.Add(bytes.NewReader(data))
+	if err != nil || hash != h {
+		t.Fatal("Failed to add content", err)
+	}
+	r, err := n2.Cat(hash)
+	if err != nil {
+		t.Fatal("Failed to get content from the peer", err)
+	}
+	rb, _ := ioutil.ReadAll(r)
+	r.Close()
+	if !bytes.Equal(rb, data) {
+		t.Fatal("Content mismatch")
+	}
+	ids, _ := n1.FindProviders(hash)
+	if len(ids) != 2 {
+		t.Fatal("Providers mismatch", ids)
+	}
+
+	dir, err := ioutil.TempDir("", "ipfsclient")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	src := filepath.Join(dir, "src")
+	os.MkdirAll(src, 0755)
+	ioutil.WriteFile(filepath.Join(src, "a.txt"), []byte("a"), 0644)
+	dh, err := n1.AddDir(src)
+	if err != nil {
+		t.Fatal("Failed to add directory", err)
+	}
+	out := filepath.Join(dir, "out")
+	err = n2.Get(dh, out)
+	if err != nil {
+		t.Fatal("Failed to get directory", err)
+	}
+	rb, err = ioutil.ReadFile(filepath.Join(out, "a.txt"))
+	if err != nil || string(rb) != "a" {
+		t.Fatal("Directory content mismatch", err)
+	}
+
+	s, _ := n2.PubSubSubscribe("topic")
+	err = n1.PubSubPublish("topic", data)
+	if err != nil {
+		t.Fatal("Failed to publish", err)
+	}
+	m, err := s.Next()
+	if err != nil || m.From != "peer1" || !bytes.Equal(m.Data, data) {
+		t.Fatal("Pubsub message mismatch", err)
+	}
+	s.Cancel()
+	_, err = s.Next()
+	if err == nil {
+		t.Fatal("Subscription is not cancelled")
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		c, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		c.Write([]byte("pong"))
+		c.Close()
+	}()
+	port := ln.Addr().(*net.TCPAddr).Port
+	err = n2.P2PListen("/x/test/1.0", "/ip4/127.0.0.1/tcp/"+strconv.Itoa(port))
+	if err != nil {
+		t.Fatal("Failed to listen", err)
+	}
+	fl, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fport := fl.Addr().(*net.TCPAddr).Port
+	fl.Close()
+	faddr := "/ip4/127.0.0.1/tcp/" + strconv.Itoa(fport)
+	err = n1.P2PForward("/x/test/1.0", faddr, "peer2")
+	if err != nil {
+		t.Fatal("Failed to forward", err)
+	}
+	c, err := net.Dial("tcp", "127.0.0.1:"+strconv.Itoa(fport))
+	if err != nil {
+		t.Fatal("Failed to connect forward", err)
+	}
+	rb, _ = ioutil.ReadAll(c)
+	c.Close()
+	if string(rb) != "pong" {
+		t.Fatal("Forward response mismatch", string(rb))
+	}
+	err = n1.P2PClose(faddr)
+	if err != nil {
+		t.Fatal("Failed to close forward", err)
+	}
+	ls, _ := n1.P2PListeners()
+	if len(ls) != 0 {
+		t.Fatal("Forward is not closed")
+	}
+}
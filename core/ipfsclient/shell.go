@@ -0,0 +1,256 @@
+package ipfsclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	ipfsnode "github.com/ipfs/go-ipfs-api"
+	files "github.com/ipfs/go-ipfs-files"
+)
+
+// DHT query event type of the provider
+const dhtProviderEvent int = 4
+
+// ShellClient is the client of the IPFS daemon
+type ShellClient struct {
+	s *ipfsnode.Shell
+}
+
+type shellSubscription struct {
+	s *ipfsnode.PubSubSubscription
+}
+
+type addObject struct {
+	Hash string
+}
+
+type dhtAddr struct {
+	Addrs []string `json:"Addrs"`
+	ID    string   `json:"ID"`
+}
+
+type dhtResponse struct {
+	Responses []dhtAddr `json:"Responses"`
+	Type      int       `json:"Type"`
+}
+
+type p2pListener struct {
+	Protocol      string
+	ListenAddress string
+	TargetAddress string
+}
+
+type p2pListeners struct {
+	Listeners []p2pListener
+}
+
+func NewShellClient(s *ipfsnode.Shell) *ShellClient {
+	return &ShellClient{s: s}
+}
+
+// NewLocalShellClient creates the client of the local IPFS daemon, returns nil
+// if the daemon API is not found
+func NewLocalShellClient() *ShellClient {
+	s := ipfsnode.NewLocalShell()
+	if s == nil {
+		return nil
+	}
+	return NewShellClient(s)
+}
+
+func (sc *ShellClient) request(cmd string, args ...string) *ipfsnode.RequestBuilder {
+	return sc.s.Request(cmd, args...)
+}
+
+func send(rb *ipfsnode.RequestBuilder) error {
+	resp, err := rb.Send(context.Background())
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+	if resp.Error != nil {
+		return resp.Error
+	}
+	return nil
+}
+
+func (sc *ShellClient) ID() (string, error) {
+	id, err := sc.s.ID()
+	if err != nil {
+		return "", err
+	}
+	return id.ID, nil
+}
+
+func (sc *ShellClient) Add(r io.Reader, opts ...AddOpt) (string, error) {
+	o := addOptions(opts...)
+	return sc.s.Add(r, ipfsnode.Pin(o.Pin), ipfsnode.OnlyHash(o.OnlyHash))
+}
+
+func (sc *ShellClient) AddDir(dir string) (string, error) {
+	stat, err := os.Lstat(dir)
+	if err != nil {
+		return "", err
+	}
+	sf, err := files.NewSerialFile(dir, false, stat)
+	if err != nil {
+		return "", err
+	}
+	defer sf.Close()
+	slf := files.NewSliceDirectory([]files.DirEntry{files.FileEntry(filepath.Base(dir), sf)})
+	defer slf.Close()
+	reader := files.NewMultiFileReader(slf, true)
+
+	resp, err := sc.request("add").
+		Option("recursive", true).
+		Option("cid-version", 1).
+		Option("hash", "sha3-256").
+		Body(reader).
+		Send(context.Background())
+	if err != nil {
+		return "", err
+	}
+	defer resp.Close()
+	if resp.Error != nil {
+		return "", resp.Error
+	}
+	defer resp.Output.Close()
+	dec := json.NewDecoder(resp.Output)
+	var final string
+	for {
+		var out addObject
+		err = dec.Decode(&out)
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return "", err
+		}
+		final = out.Hash
+	}
+	if final == "" {
+		return "", errors.New("no results received")
+	}
+	return final, nil
+}
+
+func (sc *ShellClient) Cat(path string) (io.ReadCloser, error) {
+	return sc.s.Cat(path)
+}
+
+func (sc *ShellClient) Get(hash string, outdir string) error {
+	return sc.s.Get(hash, outdir)
+}
+
+func (sc *ShellClient) Pin(hash string) error {
+	return sc.s.Pin(hash)
+}
+
+func (sc *ShellClient) Unpin(hash string) error {
+	return sc.s.Unpin(hash)
+}
+
+func (sc *ShellClient) Provide(hash string) error {
+	return send(sc.request("dht/provide", hash))
+}
+
+func (sc *ShellClient) FindProviders(hash string) ([]string, error) {
+	resp, err := sc.request("dht/findprovs", hash).Send(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Close()
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	ids := make([]string, 0)
+	dec := json.NewDecoder(resp.Output)
+	for {
+		var dr dhtResponse
+		err = dec.Decode(&dr)
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		if dr.Type != dhtProviderEvent {
+			continue
+		}
+		for _, r := range dr.Responses {
+			ids = append(ids, r.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (sc *ShellClient) PubSubSubscribe(topic string) (Subscription, error) {
+	s, err := sc.s.PubSubSubscribe(topic)
+	if err != nil {
+		return nil, err
+	}
+	return &shellSubscription{s: s}, nil
+}
+
+func (sc *ShellClient) PubSubPublish(topic string, data []byte) error {
+	return sc.s.PubSubPublish(topic, string(data))
+}
+
+func (ss *shellSubscription) Next() (*Message, error) {
+	m, err := ss.s.Next()
+	if err != nil {
+		return nil, err
+	}
+	return &Message{From: m.From.String(), Data: m.Data}, nil
+}
+
+func (ss *shellSubscription) Cancel() error {
+	return ss.s.Cancel()
+}
+
+func (sc *ShellClient) P2PListen(proto string, addr string) error {
+	return send(sc.request("p2p/listen", proto, addr))
+}
+
+func (sc *ShellClient) P2PForward(proto string, addr string, peerID string) error {
+	return send(sc.request("p2p/forward", proto, addr, "/p2p/"+peerID))
+}
+
+func (sc *ShellClient) P2PClose(addr string) error {
+	return send(sc.request("p2p/close").Option("listen-address", addr))
+}
+
+func (sc *ShellClient) P2PListeners() ([]string, error) {
+	var ls p2pListeners
+	err := sc.request("p2p/ls").Exec(context.Background(), &ls)
+	if err != nil {
+		return nil, err
+	}
+	addrs := make([]string, 0, len(ls.Listeners))
+	for _, l := range ls.Listeners {
+		addrs = append(addrs, l.ListenAddress)
+	}
+	return addrs, nil
+}
+
+func (sc *ShellClient) SwarmConnect(ctx context.Context, addr string) error {
+	return sc.s.SwarmConnect(ctx, addr)
+}
+
+func (sc *ShellClient) BootstrapAdd(peers []string) error {
+	_, err := sc.s.BootstrapAdd(peers)
+	return err
+}
+
+func (sc *ShellClient) BootstrapRmAll() error {
+	_, err := sc.s.BootstrapRmAll()
+	return err
+}
+
+func (sc *ShellClient) EnableStreamMounting() error {
+	return send(sc.request("config", "Experimental.Libp2pStreamMounting", "true").Option("bool", true))
+}
@@ -1,11 +1,10 @@
 package ipfsport
 
 import (
-	"context"
 	"fmt"
 	"time"
 
-	ipfsnode "github.com/ipfs/go-ipfs-api"
+	"github.com/rubixchain/rubixgoplatform/core/ipfsclient"
 	srvcfg "github.com/rubixchain/rubixgoplatform/wrapper/config"
 	"github.com/rubixchain/rubixgoplatform/wrapper/ensweb"
 	"github.com/rubixchain/rubixgoplatform/wrapper/logger"
@@ -14,11 +13,11 @@ import (
 type Listener struct {
 	ensweb.Server
 	cfg  *Config
-	ipfs *ipfsnode.Shell
+	ipfs ipfsclient.Client
 	log  logger.Logger
 }
 
-func NewListener(cfg *Config, log logger.Logger, ipfs *ipfsnode.Shell) (*Listener, error) {
+func NewListener(cfg *Config, log logger.Logger, ipfs ipfsclient.Client) (*Listener, error) {
 	l := &Listener{
 		cfg:  cfg,
 		log:  log,
@@ -46,15 +45,7 @@ func NewListener(cfg *Config, log logger.Logger, ipfs *ipfsnode.Shell) (*Listene
 func (l *Listener) listenIPFSPort() error {
 	proto := "/x/" + l.cfg.AppName + "/1.0"
 	addr := "/ip4/127.0.0.1/tcp/" + fmt.Sprintf("%d", l.cfg.Port)
-	resp, err := l.ipfs.Request("p2p/listen", proto, addr).Send(context.Background())
-	if err != nil {
-		return err
-	}
-	defer resp.Close()
-	if resp.Error != nil {
-		return resp.Error
-	}
-	return nil
+	return l.ipfs.P2PListen(proto, addr)
 }
 
 func (l *Listener) ExitFunc() error {
@@ -8,7 +8,7 @@ import (
 	"sync"
 	"time"
 
-	ipfsnode "github.com/ipfs/go-ipfs-api"
+	"github.com/rubixchain/rubixgoplatform/core/ipfsclient"
 	srvcfg "github.com/rubixchain/rubixgoplatform/wrapper/config"
 	"github.com/rubixchain/rubixgoplatform/wrapper/ensweb"
 	"github.com/rubixchain/rubixgoplatform/wrapper/helper/jsonutil"
@@ -17,37 +17,49 @@ import (
 
 // Peer handle for all peer connection
 type PeerManager struct {
-	peerID    string
-	lock      sync.Mutex
-	ps        []bool
-	appName   string
-	ipfs      *ipfsnode.Shell
-	log       logger.Logger
-	startPort uint16
-	lport     uint16
-	bootStrap []string
+	peerID          string
+	lock            sync.Mutex
+	ps              []bool
+	appName         string
+	ipfs            ipfsclient.Client
+	log             logger.Logger
+	startPort       uint16
+	lport           uint16
+	bootStrap       []string
+	fwds            map[string]*forward
+	maxPeerForwards int
+	stop            chan struct{}
+	closeOnce       sync.Once
 }
 
+// Peer is the connection to the peer app, the remote connections share the
+// pooled forward which is released on Close
 type Peer struct {
 	ensweb.Client
 	port   uint16
 	local  bool
 	log    logger.Logger
 	pm     *PeerManager
+	fw     *forward
+	closed sync.Once
 	peerID string
 	did    string
 }
 
-func NewPeerManager(startPort uint16, lport uint16, maxNumPort uint16, ipfs *ipfsnode.Shell, log logger.Logger, bootStrap []string, peerID string) *PeerManager {
+func NewPeerManager(startPort uint16, lport uint16, maxNumPort uint16, ipfs ipfsclient.Client, log logger.Logger, bootStrap []string, peerID string) *PeerManager {
 	p := &PeerManager{
-		peerID:    peerID,
-		ipfs:      ipfs,
-		log:       log.Named("PeerManager"),
-		ps:        make([]bool, maxNumPort),
-		startPort: startPort,
-		lport:     lport,
-		bootStrap: bootStrap,
+		peerID:          peerID,
+		ipfs:            ipfs,
+		log:             log.Named("PeerManager"),
+		ps:              make([]bool, maxNumPort),
+		startPort:       startPort,
+		lport:           lport,
+		bootStrap:       bootStrap,
+		fwds:            make(map[string]*forward),
+		maxPeerForwards: DefaultPeerForwards,
+		stop:            make(chan struct{}),
 	}
+	go p.runEviction()
 	for _, bs := range p.bootStrap {
 		_, bsID := path.Split(bs)
 		err := p.ipfs.SwarmConnect(context.Background(), "/ipfs/"+bsID)
@@ -128,44 +140,26 @@ func (pm *PeerManager) OpenPeerConn(peerID string, did string, appname string) (
 		}
 		return p, nil
 	} else {
-		if !pm.SwarmConnect(peerID) {
-			pm.log.Error("Failed to connect swarm peer", "peerID", peerID)
-			return nil, fmt.Errorf("failed to connect swarm peer")
-		}
-		portNum := pm.getPeerPort()
-		if portNum == 0 {
-			return nil, fmt.Errorf("all ports are busy")
+		fw, err := pm.getForward(peerID, appname)
+		if err != nil {
+			return nil, err
 		}
 		scfg := &srvcfg.Config{
 			ServerAddress: "localhost",
-			ServerPort:    fmt.Sprintf("%d", portNum),
+			ServerPort:    fmt.Sprintf("%d", fw.port),
 		}
 		p := &Peer{
-			port:   portNum,
+			port:   fw.port,
 			pm:     pm,
+			fw:     fw,
 			log:    pm.log.Named(peerID),
 			peerID: peerID,
 			did:    did,
 		}
-		proto := "/x/" + appname + "/1.0"
-		addr := "/ip4/127.0.0.1/tcp/" + fmt.Sprintf("%d", portNum)
-		peer := "/p2p/" + peerID
-		resp, err := pm.ipfs.Request("p2p/forward", proto, addr, peer).Send(context.Background())
-		if err != nil {
-			pm.log.Error("failed make forward request")
-			pm.releasePeerPort(portNum)
-			return nil, err
-		}
-		defer resp.Close()
-		if resp.Error != nil {
-			pm.log.Error("error in forward request")
-			pm.releasePeerPort(portNum)
-			return nil, resp.Error
-		}
 		p.Client, err = ensweb.NewClient(scfg, p.log)
 		if err != nil {
 			pm.log.Error("failed to create ensweb clent", "err", err)
-			pm.releasePeerPort(portNum)
+			pm.releaseForward(fw)
 			return nil, err
 		}
 		return p, nil
@@ -173,11 +167,17 @@ func (pm *PeerManager) OpenPeerConn(peerID string, did string, appname string) (
 }
 
 func (p *Peer) SendJSONRequest(method string, path string, querry map[string]string, req interface{}, resp interface{}, did bool, timeout ...time.Duration) error {
+	return p.SendJSONRequestWithContext(context.Background(), method, path, querry, req, resp, did, timeout...)
+}
+
+// SendJSONRequestWithContext will send the request to the peer, the request is aborted once the context is done
+func (p *Peer) SendJSONRequestWithContext(ctx context.Context, method string, path string, querry map[string]string, req interface{}, resp interface{}, did bool, timeout ...time.Duration) error {
 	httpReq, err := p.JSONRequest(method, path, req)
-	httpReq.Close = true
 	if err != nil {
 		return err
 	}
+	httpReq = httpReq.WithContext(ctx)
+	httpReq.Close = true
 	if did {
 		q := httpReq.URL.Query()
 		q.Add("did", p.did)
@@ -191,6 +191,10 @@ func (p *Peer) SendJSONRequest(method string, path string, querry map[string]str
 	httpResp, err := p.Do(httpReq, timeout...)
 	if err != nil {
 		p.log.Error("failed to receive reply", "err", err)
+		// reopen the forward on the next connection unless the request is aborted
+		if p.fw != nil && ctx.Err() == nil {
+			p.pm.markForwardBad(p.fw)
+		}
 		return err
 	}
 	defer httpResp.Body.Close()
@@ -210,21 +214,13 @@ func (p *Peer) IsLocal() bool {
 	return p.local
 }
 
+// Close releases the forward to the pool, the forward is closed by the pool
+// once it is idle
 func (p *Peer) Close() error {
-	if !p.local {
-		defer p.pm.releasePeerPort(p.port)
-		addr := "/ip4/127.0.0.1/tcp/" + fmt.Sprintf("%d", p.port)
-		req := p.pm.ipfs.Request("p2p/close")
-		resp, err := req.Option("listen-address", addr).Send(context.Background())
-		if err != nil {
-			p.log.Error("failed to close ipfs port", "err", err)
-			return err
-		}
-		defer resp.Close()
-		if resp.Error != nil {
-			p.log.Error("failed to close ipfs port", "err", resp.Error)
-			return resp.Error
-		}
+	if !p.local && p.fw != nil {
+		p.closed.Do(func() {
+			p.pm.releaseForward(p.fw)
+		})
 	}
 	return nil
 }
@@ -0,0 +1,245 @@
+package ipfsport
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	ForwardIdleTimeout   time.Duration = 2 * time.Minute
+	ForwardCheckInterval time.Duration = 30 * time.Second
+	DefaultPeerForwards  int           = 4
+)
+
+// forward is the pooled p2p forward to the peer app, the forward is shared by
+// all the connections to the peer app and closed once it is idle
+type forward struct {
+	key      string
+	peerID   string
+	port     uint16
+	refs     int
+	bad      bool
+	lastUsed time.Time
+	checked  time.Time
+}
+
+func forwardKey(peerID string, appname string) string {
+	return peerID + "/" + appname
+}
+
+func forwardAddr(port uint16) string {
+	return "/ip4/127.0.0.1/tcp/" + fmt.Sprintf("%d", port)
+}
+
+// SetMaxPeerForwards sets the maximum number of forwards to a peer across
+// the apps
+func (pm *PeerManager) SetMaxPeerForwards(n int) {
+	pm.lock.Lock()
+	defer pm.lock.Unlock()
+	if n <= 0 {
+		n = DefaultPeerForwards
+	}
+	pm.maxPeerForwards = n
+}
+
+// getForward returns the live forward of the peer app from the pool, a new
+// forward is opened if there is none
+func (pm *PeerManager) getForward(peerID string, appname string) (*forward, error) {
+	key := forwardKey(peerID, appname)
+	pm.lock.Lock()
+	fw := pm.fwds[key]
+	check := fw != nil && !fw.bad && time.Since(fw.checked) > ForwardCheckInterval
+	pm.lock.Unlock()
+	if check {
+		alive := pm.isForwardAlive(fw)
+		pm.lock.Lock()
+		if alive {
+			fw.checked = time.Now()
+		} else {
+			pm.log.Debug("Forward is not alive, reopening", "peerID", peerID)
+			fw.bad = true
+		}
+		pm.lock.Unlock()
+	}
+	pm.lock.Lock()
+	fw = pm.fwds[key]
+	if fw != nil && !fw.bad {
+		fw.refs++
+		fw.lastUsed = time.Now()
+		pm.lock.Unlock()
+		return fw, nil
+	}
+	cl := make([]*forward, 0)
+	if fw != nil {
+		// detach the bad forward, it is closed once the connections are released
+		delete(pm.fwds, key)
+		if fw.refs == 0 {
+			cl = append(cl, fw)
+		}
+	}
+	n := 0
+	var idle *forward
+	for _, f := range pm.fwds {
+		if f.peerID != peerID {
+			continue
+		}
+		n++
+		if f.refs == 0 && (idle == nil || f.lastUsed.Before(idle.lastUsed)) {
+			idle = f
+		}
+	}
+	if n >= pm.maxPeerForwards {
+		if idle == nil {
+			pm.lock.Unlock()
+			pm.closeForwards(cl)
+			return nil, fmt.Errorf("too many forwards to the peer")
+		}
+		delete(pm.fwds, idle.key)
+		cl = append(cl, idle)
+	}
+	pm.lock.Unlock()
+	pm.closeForwards(cl)
+	fw, err := pm.openForward(peerID, appname)
+	if err != nil {
+		return nil, err
+	}
+	pm.lock.Lock()
+	ex := pm.fwds[key]
+	if ex != nil && !ex.bad {
+		// opened by the other connection meanwhile
+		ex.refs++
+		ex.lastUsed = time.Now()
+		pm.lock.Unlock()
+		pm.closeForward(fw)
+		return ex, nil
+	}
+	pm.fwds[key] = fw
+	pm.lock.Unlock()
+	return fw, nil
+}
+
+func (pm *PeerManager) openForward(peerID string, appname string) (*forward, error) {
+	if !pm.SwarmConnect(peerID) {
+		pm.log.Error("Failed to connect swarm peer", "peerID", peerID)
+		return nil, fmt.Errorf("failed to connect swarm peer")
+	}
+	portNum := pm.getPeerPort()
+	if portNum == 0 {
+		// free the idle forwards of the other peers
+		pm.evictForwards(true)
+		portNum = pm.getPeerPort()
+		if portNum == 0 {
+			return nil, fmt.Errorf("all ports are busy")
+		}
+	}
+	proto := "/x/" + appname + "/1.0"
+	err := pm.ipfs.P2PForward(proto, forwardAddr(portNum), peerID)
+	if err != nil {
+		pm.log.Error("failed make forward request", "err", err)
+		pm.releasePeerPort(portNum)
+		return nil, err
+	}
+	return &forward{
+		key:      forwardKey(peerID, appname),
+		peerID:   peerID,
+		port:     portNum,
+		refs:     1,
+		lastUsed: time.Now(),
+		checked:  time.Now(),
+	}, nil
+}
+
+func (pm *PeerManager) isForwardAlive(fw *forward) bool {
+	ls, err := pm.ipfs.P2PListeners()
+	if err != nil {
+		pm.log.Error("failed to list the forwards", "err", err)
+		return false
+	}
+	addr := forwardAddr(fw.port)
+	for _, l := range ls {
+		if l == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// releaseForward releases the connection reference of the forward
+func (pm *PeerManager) releaseForward(fw *forward) {
+	pm.lock.Lock()
+	fw.refs--
+	fw.lastUsed = time.Now()
+	unused := fw.refs == 0 && pm.fwds[fw.key] != fw
+	pm.lock.Unlock()
+	if unused {
+		pm.closeForward(fw)
+	}
+}
+
+// markForwardBad marks the forward to be reopened by the next connection
+func (pm *PeerManager) markForwardBad(fw *forward) {
+	pm.lock.Lock()
+	defer pm.lock.Unlock()
+	fw.bad = true
+}
+
+func (pm *PeerManager) closeForward(fw *forward) error {
+	defer pm.releasePeerPort(fw.port)
+	err := pm.ipfs.P2PClose(forwardAddr(fw.port))
+	if err != nil {
+		pm.log.Error("failed to close ipfs port", "err", err)
+	}
+	return err
+}
+
+func (pm *PeerManager) closeForwards(fws []*forward) {
+	for _, fw := range fws {
+		pm.closeForward(fw)
+	}
+}
+
+// evictForwards closes the unused forwards which are idle or bad, all the
+// unused forwards are closed if forced
+func (pm *PeerManager) evictForwards(force bool) {
+	pm.lock.Lock()
+	cl := make([]*forward, 0)
+	for k, fw := range pm.fwds {
+		if fw.refs > 0 {
+			continue
+		}
+		if force || fw.bad || time.Since(fw.lastUsed) > ForwardIdleTimeout {
+			delete(pm.fwds, k)
+			cl = append(cl, fw)
+		}
+	}
+	pm.lock.Unlock()
+	pm.closeForwards(cl)
+}
+
+func (pm *PeerManager) runEviction() {
+	t := time.NewTicker(ForwardCheckInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-pm.stop:
+			return
+		case <-t.C:
+			pm.evictForwards(false)
+		}
+	}
+}
+
+// Close closes all the pooled forwards
+func (pm *PeerManager) Close() {
+	pm.closeOnce.Do(func() {
+		close(pm.stop)
+	})
+	pm.lock.Lock()
+	cl := make([]*forward, 0, len(pm.fwds))
+	for k, fw := range pm.fwds {
+		delete(pm.fwds, k)
+		cl = append(cl, fw)
+	}
+	pm.lock.Unlock()
+	pm.closeForwards(cl)
+}
@@ -0,0 +1,81 @@
+package ipfsport
+
+import (
+	"testing"
+
+	"github.com/rubixchain/rubixgoplatform/core/ipfsclient"
+	"github.com/rubixchain/rubixgoplatform/wrapper/logger"
+)
+
+type countingNode struct {
+	*ipfsclient.FakeNode
+	opened int
+}
+
+func (cn *countingNode) P2PForward(proto string, addr string, peerID string) error {
+	cn.opened++
+	return cn.FakeNode.P2PForward(proto, addr, peerID)
+}
+
+func (cn *countingNode) forwards() int {
+	ls, _ := cn.P2PListeners()
+	return len(ls)
+}
+
+func TestForwardPool(t *testing.T) {
+	log := logger.New(&logger.LoggerOptions{Name: "test", Color: []logger.ColorOption{logger.ColorOff}})
+	fn := ipfsclient.NewFakeNetwork()
+	fn.NewNode("peer1")
+	f := &countingNode{FakeNode: fn.NewNode("self")}
+	pm := NewPeerManager(30000, 20000, 10, f, log, nil, "self")
+	defer pm.Close()
+	pm.SetMaxPeerForwards(2)
+	p1, err := pm.OpenPeerConn("peer1", "did1", "app")
+	if err != nil {
+		t.Fatal("Failed to open peer connection", err)
+	}
+	p2, err := pm.OpenPeerConn("peer1", "did2", "app")
+	if err != nil {
+		t.Fatal("Failed to open peer connection", err)
+	}
+	if p1.port != p2.port || f.opened != 1 {
+		t.Fatal("Forward is not reused")
+	}
+	p1.Close()
+	p1.Close()
+	p2.Close()
+	if f.forwards() != 1 {
+		t.Fatal("Idle forward closed before the timeout")
+	}
+	// bad forward is reopened
+	p3, _ := pm.OpenPeerConn("peer1", "did1", "app")
+	pm.markForwardBad(p3.fw)
+	p4, err := pm.OpenPeerConn("peer1", "did1", "app")
+	if err != nil || p4.port == p3.port || f.opened != 2 {
+		t.Fatal("Bad forward is reused")
+	}
+	p3.Close()
+	if f.forwards() != 1 {
+		t.Fatal("Bad forward is not closed")
+	}
+	// forwards per peer are capped
+	p5, err := pm.OpenPeerConn("peer1", "did1", "app2")
+	if err != nil {
+		t.Fatal("Failed to open peer connection", err)
+	}
+	_, err = pm.OpenPeerConn("peer1", "did1", "app3")
+	if err == nil {
+		t.Fatal("Forwards to the peer are not capped")
+	}
+	p5.Close()
+	p6, err := pm.OpenPeerConn("peer1", "did1", "app3")
+	if err != nil {
+		t.Fatal("Idle forward is not evicted for the new app", err)
+	}
+	p6.Close()
+	p4.Close()
+	pm.evictForwards(true)
+	if f.forwards() != 0 || len(pm.fwds) != 0 {
+		t.Fatal("Forwards are not evicted")
+	}
+}
@@ -372,7 +372,7 @@ func (c *Core) migrateNode(reqID string, m *MigrateRequest, didDir string) error
 						}
 						tb := bytes.NewReader(tk)
 						tid, err := c.ipfs.Add(tb)
-						//tid, err := c.ipfs.Add(tb, ipfsnode.Pin(false), ipfsnode.OnlyHash(true))
+						//tid, err := c.ipfs.Add(tb, ipfsclient.Pin(false), ipfsclient.OnlyHash(true))
 						if err != nil {
 							c.log.Error("Failed to migrate, failed to add token file", "err", err)
 							return fmt.Errorf("failed to migrate, failed to add token file")
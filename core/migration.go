@@ -0,0 +1,40 @@
+package core
+
+import (
+	"github.com/rubixchain/rubixgoplatform/core/model"
+)
+
+// GetMigrations returns the applied & the pending wallet migrations
+func (c *Core) GetMigrations() []model.Migration {
+	ml := make([]model.Migration, 0)
+	for _, sv := range c.w.GetAppliedMigrations() {
+		ml = append(ml, model.Migration{
+			Version:     sv.Version,
+			Name:        sv.Name,
+			Applied:     true,
+			AppliedTime: sv.AppliedTime,
+		})
+	}
+	for _, m := range c.w.GetPendingMigrations() {
+		ml = append(ml, model.Migration{
+			Version: m.Version,
+			Name:    m.Name,
+		})
+	}
+	return ml
+}
+
+// RunMigrations applies the pending wallet migrations
+func (c *Core) RunMigrations() ([]model.Migration, error) {
+	ml := make([]model.Migration, 0)
+	svs, err := c.w.RunMigrations()
+	for _, sv := range svs {
+		ml = append(ml, model.Migration{
+			Version:     sv.Version,
+			Name:        sv.Name,
+			Applied:     true,
+			AppliedTime: sv.AppliedTime,
+		})
+	}
+	return ml, err
+}
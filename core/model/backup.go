@@ -0,0 +1,8 @@
+package model
+
+// BackupRequest is the node backup request, the backup is written into the
+// path on the node and encrypted if the password is given
+type BackupRequest struct {
+	Path     string `json:"path"`
+	Password string `json:"password"`
+}
@@ -30,4 +30,6 @@ type SCTDataReply struct {
 type RegisterCallBackUrlReq struct {
 	SmartContractToken string
 	CallBackURL        string
+	// Secret is used to sign the callbacks, generated if not provided
+	Secret string
 }
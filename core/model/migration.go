@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// Migration is the wallet migration step
+type Migration struct {
+	Version     int       `json:"version"`
+	Name        string    `json:"name"`
+	Applied     bool      `json:"applied"`
+	AppliedTime time.Time `json:"applied_time"`
+}
+
+// MigrationResponse used as model for the migration API responses
+type MigrationResponse struct {
+	Status  bool        `json:"status"`
+	Message string      `json:"message"`
+	Result  []Migration `json:"result"`
+}
@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type ExploreModel struct {
 	Cmd           string   `json:"cmd"`
 	PeerID        string   `json:"peer_id"`
@@ -8,3 +10,23 @@ type ExploreModel struct {
 	TransactionID string   `json:"tid"`
 	Message       string   `json:"message"`
 }
+
+// TopicMetrics is the message counters of the pubsub topic
+type TopicMetrics struct {
+	Topic        string    `json:"topic"`
+	Subscribed   bool      `json:"subscribed"`
+	Received     uint64    `json:"received"`
+	Delivered    uint64    `json:"delivered"`
+	Dropped      uint64    `json:"dropped"`
+	Published    uint64    `json:"published"`
+	Errors       uint64    `json:"errors"`
+	Resubscribes uint64    `json:"resubscribes"`
+	LastMessage  time.Time `json:"last_message"`
+}
+
+// PubSubMetricsResponse used as model for the pubsub metrics API response
+type PubSubMetricsResponse struct {
+	Status  bool           `json:"status"`
+	Message string         `json:"message"`
+	Result  []TopicMetrics `json:"result"`
+}
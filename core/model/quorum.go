@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 const (
 	AlphaType int = iota
 	BetaType
@@ -31,3 +33,44 @@ type QuorumSetup struct {
 	Password        string `json:"password"`
 	PrivKeyPassword string `json:"priv_password"`
 }
+
+// PledgeLease is the tokens offered by the quorum to the initiator for
+// pledging, the tokens are released back if the initiator does not complete
+// the pledge before the lease expires
+type PledgeLease struct {
+	Token        string    `gorm:"column:token;primaryKey" json:"token"`
+	ReqID        string    `gorm:"column:req_id" json:"req_id"`
+	QuorumDID    string    `gorm:"column:quorum_did" json:"quorum_did"`
+	InitiatorID  string    `gorm:"column:initiator_id" json:"initiator_id"`
+	TokenValue   float64   `gorm:"column:token_value" json:"token_value"`
+	CreationTime time.Time `gorm:"column:creation_time" json:"creation_time"`
+	Expiry       time.Time `gorm:"column:expiry" json:"expiry"`
+}
+
+// PledgeLeaseResponse used as model for the pledge lease API responses
+type PledgeLeaseResponse struct {
+	Status  bool          `json:"status"`
+	Message string        `json:"message"`
+	Result  []PledgeLease `json:"result"`
+}
+
+// QuorumHealth is the observed behaviour of the public quorum, latency is in
+// milliseconds
+type QuorumHealth struct {
+	DID                 string    `json:"did"`
+	LastSeen            time.Time `json:"last_seen"`
+	LastFailure         time.Time `json:"last_failure"`
+	SuccessCount        int       `json:"success_count"`
+	FailureCount        int       `json:"failure_count"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	MedianLatency       int64     `json:"median_latency"`
+	Healthy             bool      `json:"healthy"`
+	Score               float64   `json:"score"`
+}
+
+// QuorumHealthResponse used as model for the quorum health API response
+type QuorumHealthResponse struct {
+	Status  bool           `json:"status"`
+	Message string         `json:"message"`
+	Result  []QuorumHealth `json:"result"`
+}
@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type NewContractEvent struct {
 	SmartContractToken     string `json:"smartContractToken"`
 	Did                    string `json:"did"`
@@ -10,3 +12,22 @@ type NewContractEvent struct {
 type NewSubscription struct {
 	SmartContractToken string `json:"smartContractToken"`
 }
+
+// SCSubscription is the smart contract subscribed by the node, active is false
+// if the subscription is not resumed yet
+type SCSubscription struct {
+	SmartContractToken string    `json:"smartContractToken"`
+	PeerID             string    `json:"peer_id"`
+	DID                string    `json:"did"`
+	LastBlockID        string    `json:"last_block_id"`
+	Active             bool      `json:"active"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// SCSubscriptionResponse used as model for the smart contract subscriptions API response
+type SCSubscriptionResponse struct {
+	Status  bool             `json:"status"`
+	Message string           `json:"message"`
+	Result  []SCSubscription `json:"result"`
+}
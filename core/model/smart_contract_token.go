@@ -1,5 +1,10 @@
 package model
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type DeploySmartContractRequest struct {
 	SmartContractToken string  `json:"smartContractToken"`
 	DeployerAddress    string  `json:"deployerAddr"`
@@ -15,3 +20,107 @@ type ExecuteSmartContractRequest struct {
 	Comment            string `json:"comment"`
 	SmartContractData  string `json:"smartContractData"`
 }
+
+// SmartContractExecution is the smart contract data of the executed block,
+// the fuel, the state hash & the events are verified by the quorums
+type SmartContractExecution struct {
+	Data      string   `json:"data"`
+	StateHash string   `json:"stateHash"`
+	Fuel      uint64   `json:"fuel"`
+	Time      int64    `json:"time,omitempty"`
+	Events    []string `json:"events,omitempty"`
+}
+
+type SCHistoryRequest struct {
+	Token string `json:"token"`
+}
+
+// SCStateRequest gets the state as of the block number or the unix time, the
+// latest state is returned if both are not set
+type SCStateRequest struct {
+	Token   string `json:"token"`
+	BlockNo uint64 `json:"blockNo"`
+	Time    int64  `json:"time"`
+}
+
+// SCStateDiffRequest gets the difference between the states as of the blocks,
+// the from block 0 is the deployed state and the to block 0 is the latest state
+type SCStateDiffRequest struct {
+	Token     string `json:"token"`
+	FromBlock uint64 `json:"fromBlock"`
+	ToBlock   uint64 `json:"toBlock"`
+}
+
+// SCExecutionInfo is the indexed execution of the smart contract
+type SCExecutionInfo struct {
+	Token       string    `json:"token"`
+	BlockNo     uint64    `json:"blockNo"`
+	BlockID     string    `json:"blockId"`
+	ExecutorDID string    `json:"executorDid"`
+	Data        string    `json:"data"`
+	StateHash   string    `json:"stateHash"`
+	Fuel        uint64    `json:"fuel"`
+	Time        time.Time `json:"time"`
+}
+
+type SCHistoryResponse struct {
+	BasicResponse
+	History []SCExecutionInfo `json:"history"`
+}
+
+type SCStateResponse struct {
+	BasicResponse
+	Execution *SCExecutionInfo `json:"execution"`
+	State     string           `json:"state"`
+}
+
+// SCStateChange is the change of the top level key of the JSON state
+type SCStateChange struct {
+	Key  string          `json:"key"`
+	Op   string          `json:"op"`
+	From json.RawMessage `json:"from,omitempty"`
+	To   json.RawMessage `json:"to,omitempty"`
+}
+
+// SCStateDiff is the difference between the states of two blocks, the key
+// changes are listed only if both the states are JSON objects
+type SCStateDiff struct {
+	FromBlock     uint64          `json:"fromBlock"`
+	ToBlock       uint64          `json:"toBlock"`
+	FromStateHash string          `json:"fromStateHash"`
+	ToStateHash   string          `json:"toStateHash"`
+	Changed       bool            `json:"changed"`
+	Changes       []SCStateChange `json:"changes,omitempty"`
+}
+
+type SCStateDiffResponse struct {
+	BasicResponse
+	Diff *SCStateDiff `json:"diff"`
+}
+
+// CallbackDelivery is the delivery of the smart contract update to the
+// callback URL
+type CallbackDelivery struct {
+	ID           string    `json:"id"`
+	Token        string    `json:"token"`
+	URL          string    `json:"url"`
+	Payload      string    `json:"payload"`
+	Status       string    `json:"status"`
+	Attempts     int       `json:"attempts"`
+	LastError    string    `json:"last_error"`
+	NextAttempt  time.Time `json:"next_attempt"`
+	CreationTime time.Time `json:"creation_time"`
+	UpdateTime   time.Time `json:"update_time"`
+}
+
+// CallbackDeliveryResponse used as model for the callback deliveries API response
+type CallbackDeliveryResponse struct {
+	Status  bool               `json:"status"`
+	Message string             `json:"message"`
+	Result  []CallbackDelivery `json:"result"`
+}
+
+// ReplayCallbackRequest is the input to replay the callback delivery
+type ReplayCallbackRequest struct {
+	ID string `json:"id"`
+}
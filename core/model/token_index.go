@@ -0,0 +1,16 @@
+package model
+
+// TokenIndexStatus is the build status of the token number index
+type TokenIndexStatus struct {
+	Indexed       int   `json:"indexed"`
+	Total         int   `json:"total"`
+	Building      bool  `json:"building"`
+	IndexedLevels []int `json:"indexed_levels"`
+}
+
+// TokenIndexStatusResponse used as model for the token index status API response
+type TokenIndexStatusResponse struct {
+	Status  bool             `json:"status"`
+	Message string           `json:"message"`
+	Result  TokenIndexStatus `json:"result"`
+}
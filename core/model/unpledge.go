@@ -0,0 +1,62 @@
+package model
+
+import "time"
+
+// UnpledgeTokenStatus is the unpledge state of the token
+type UnpledgeTokenStatus struct {
+	Token        string    `json:"token"`
+	Status       string    `json:"status"`
+	Reason       string    `json:"reason"`
+	Attempts     int       `json:"attempts"`
+	Hashes       int64     `json:"hashes"`
+	CreationTime time.Time `json:"creation_time"`
+	UpdateTime   time.Time `json:"update_time"`
+}
+
+// UnpledgeQueueStatus is the status of the unpledge queue, ETA is in seconds
+// and zero if it can not be estimated yet
+type UnpledgeQueueStatus struct {
+	Workers  int                   `json:"workers"`
+	Queued   int                   `json:"queued"`
+	Working  int                   `json:"working"`
+	Failed   int                   `json:"failed"`
+	HashRate float64               `json:"hash_rate"`
+	ETA      int64                 `json:"eta"`
+	Tokens   []UnpledgeTokenStatus `json:"tokens"`
+}
+
+// UnpledgeStatusResponse used as model for the unpledge status API response
+type UnpledgeStatusResponse struct {
+	Status  bool                `json:"status"`
+	Message string              `json:"message"`
+	Result  UnpledgeQueueStatus `json:"result"`
+}
+
+// UnpledgeTokenRequest is the input to retry or cancel the token unpledge
+type UnpledgeTokenRequest struct {
+	Token string `json:"token"`
+}
+
+// UnpledgeProofRequest is the input to verify the unpledge proof, proof is
+// fetched using the unpledge id of the token chain if it is not provided
+type UnpledgeProofRequest struct {
+	Token string `json:"token"`
+	Proof []byte `json:"proof"`
+}
+
+// UnpledgeProofResult is the verdict of the unpledge proof verification
+type UnpledgeProofResult struct {
+	Token   string `json:"token"`
+	ProofID string `json:"proof_id"`
+	Compact bool   `json:"compact"`
+	Records int    `json:"records"`
+	Valid   bool   `json:"valid"`
+	Reason  string `json:"reason"`
+}
+
+// UnpledgeProofResponse used as model for the unpledge proof verification response
+type UnpledgeProofResponse struct {
+	Status  bool                `json:"status"`
+	Message string              `json:"message"`
+	Result  UnpledgeProofResult `json:"result"`
+}
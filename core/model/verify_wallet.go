@@ -0,0 +1,81 @@
+package model
+
+// VerifyWalletRequest is the input of the wallet verification
+type VerifyWalletRequest struct {
+	DID           string `json:"did"`
+	SkipSignature bool   `json:"skip_signature"`
+	Repair        bool   `json:"repair"`
+}
+
+// WalletIssue is the inconsistency found on the token, repair status is
+// valid only if the issue is repairable
+type WalletIssue struct {
+	Token        string `json:"token"`
+	DID          string `json:"did"`
+	Status       int    `json:"status"`
+	BlockID      string `json:"block_id"`
+	Issue        string `json:"issue"`
+	Message      string `json:"message"`
+	Repairable   bool   `json:"repairable"`
+	RepairStatus int    `json:"repair_status"`
+	Repaired     bool   `json:"repaired"`
+}
+
+// WalletReport is the result of the wallet verification
+type WalletReport struct {
+	TokensChecked int           `json:"tokens_checked"`
+	BlocksChecked int           `json:"blocks_checked"`
+	Issues        []WalletIssue `json:"issues"`
+}
+
+// VerifyWalletResponse used as model for the verify wallet API response
+type VerifyWalletResponse struct {
+	Status  bool         `json:"status"`
+	Message string       `json:"message"`
+	Result  WalletReport `json:"result"`
+}
+
+// VerifyTokenChainRequest is the input of the token chain verification, token
+// type is one of rbt, part, nft, data & sc
+type VerifyTokenChainRequest struct {
+	Token     string `json:"token"`
+	TokenType string `json:"token_type"`
+}
+
+// SignatureResult is the signature verification result of the DID
+type SignatureResult struct {
+	DID   string `json:"did"`
+	Valid bool   `json:"valid"`
+}
+
+// BlockResult is the verification result of the token chain block
+type BlockResult struct {
+	BlockNumber      uint64            `json:"block_number"`
+	BlockID          string            `json:"block_id"`
+	TransType        string            `json:"trans_type"`
+	Owner            string            `json:"owner"`
+	Signatures       []SignatureResult `json:"signatures"`
+	QuorumSignatures []SignatureResult `json:"quorum_signatures"`
+	PledgeDetails    int               `json:"pledge_details"`
+	Valid            bool              `json:"valid"`
+	Errors           []string          `json:"errors"`
+}
+
+// TokenChainResult is the verification result of the token chain
+type TokenChainResult struct {
+	Token       string        `json:"token"`
+	TokenType   int           `json:"token_type"`
+	TokenLevel  int           `json:"token_level"`
+	TokenNumber int           `json:"token_number"`
+	ParentToken string        `json:"parent_token"`
+	Valid       bool          `json:"valid"`
+	Errors      []string      `json:"errors"`
+	Blocks      []BlockResult `json:"blocks"`
+}
+
+// VerifyTokenChainResponse used as model for the verify token chain API response
+type VerifyTokenChainResponse struct {
+	Status  bool             `json:"status"`
+	Message string           `json:"message"`
+	Result  TokenChainResult `json:"result"`
+}
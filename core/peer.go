@@ -7,6 +7,7 @@ import (
 
 	"github.com/rubixchain/rubixgoplatform/core/ipfsport"
 	"github.com/rubixchain/rubixgoplatform/core/model"
+	"github.com/rubixchain/rubixgoplatform/did"
 	"github.com/rubixchain/rubixgoplatform/util"
 	"github.com/rubixchain/rubixgoplatform/wrapper/ensweb"
 )
@@ -28,9 +29,9 @@ func (c *Core) peerSetup() error {
 	return c.ps.SubscribeTopic(PeerService, c.peerCallback)
 }
 
-func (c *Core) publishPeerMap(pm *PeerMap) error {
+func (c *Core) publishPeerMap(dc did.DIDCrypto, pm *PeerMap) error {
 	if c.ps != nil {
-		err := c.ps.Publish(PeerService, pm)
+		err := c.ps.Publish(PeerService, dc, pm)
 		if err != nil {
 			c.log.Error("Failed to publish peer map message", "err", err)
 			return err
@@ -39,7 +40,16 @@ func (c *Core) publishPeerMap(pm *PeerMap) error {
 	return nil
 }
 
-func (c *Core) peerCallback(peerID string, topic string, data []byte) {
+// verifyPubSub verifies the pubsub envelope signature of the DID
+func (c *Core) verifyPubSub(didStr string, hash []byte, sig []byte) (bool, error) {
+	dc, err := c.SetupForienDID(didStr)
+	if err != nil {
+		return false, err
+	}
+	return dc.PvtVerify(hash, sig)
+}
+
+func (c *Core) peerCallback(peerID string, signer string, topic string, data []byte) {
 	var m PeerMap
 	err := json.Unmarshal(data, &m)
 	c.log.Debug("Peer DID Update")
@@ -47,6 +57,10 @@ func (c *Core) peerCallback(peerID string, topic string, data []byte) {
 		c.log.Error("failed to parse explorer data", "err", err)
 		return
 	}
+	if m.DID != signer || m.PeerID != peerID {
+		c.log.Error("Peer DID map is not published by the DID owner", "did", m.DID, "peer", peerID)
+		return
+	}
 	h := util.CalculateHashString(m.PeerID+m.DID+m.Time, "SHA3-256")
 	dc, err := c.SetupForienDID(m.DID)
 	if err != nil {
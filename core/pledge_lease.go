@@ -0,0 +1,157 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rubixchain/rubixgoplatform/core/model"
+	"github.com/rubixchain/rubixgoplatform/core/wallet"
+)
+
+const (
+	PledgeLeaseStorage string = "pledgelease"
+)
+
+const (
+	DefaultPledgeLeaseTimeout time.Duration = DefaultConsensusTimeout
+	PledgeLeaseSweepInterval  time.Duration = time.Minute
+)
+
+func (c *Core) initPledgeLease() error {
+	c.pledging = make(map[string]bool)
+	return c.s.Init(PledgeLeaseStorage, &model.PledgeLease{}, true)
+}
+
+// pledgeLeaseTimeout returns the configured lease duration of the pledge offers
+func (c *Core) pledgeLeaseTimeout() time.Duration {
+	return c.stepTimeout(c.cfg.CfgData.ConsensusConfig.PledgeLeaseTimeout, DefaultPledgeLeaseTimeout)
+}
+
+// initiatorPeerID returns the peer which initiated the consensus request
+func initiatorPeerID(cr *ConensusRequest) string {
+	switch cr.Mode {
+	case SmartContractDeployMode:
+		return cr.DeployerPeerID
+	case SmartContractExecuteMode:
+		return cr.ExecuterPeerID
+	default:
+		return cr.SenderPeerID
+	}
+}
+
+func (c *Core) addPledgeLease(pr *PledgeRequest, did string, wt []wallet.Token) {
+	c.leaseLock.Lock()
+	defer c.leaseLock.Unlock()
+	st := time.Now()
+	for _, t := range wt {
+		pl := model.PledgeLease{
+			Token:        t.TokenID,
+			ReqID:        pr.ReqID,
+			QuorumDID:    did,
+			InitiatorID:  pr.InitiatorID,
+			TokenValue:   t.TokenValue,
+			CreationTime: st,
+			Expiry:       st.Add(c.pledgeLeaseTimeout()),
+		}
+		// token could have a stale lease from the earlier offer
+		c.s.Delete(PledgeLeaseStorage, &model.PledgeLease{}, "token=?", t.TokenID)
+		err := c.s.Write(PledgeLeaseStorage, &pl)
+		if err != nil {
+			c.log.Error("Failed to write pledge lease", "token", t.TokenID, "err", err)
+		}
+	}
+}
+
+// claimPledgeLease claims the leases of the tokens being pledged, the sweeper
+// does not release the claimed tokens. Claim fails if any of the leases is
+// already released, the token could be offered to another initiator.
+func (c *Core) claimPledgeLease(tokens []string) error {
+	c.leaseLock.Lock()
+	defer c.leaseLock.Unlock()
+	for _, t := range tokens {
+		var pl model.PledgeLease
+		err := c.s.Read(PledgeLeaseStorage, &pl, "token=?", t)
+		if err != nil || pl.Token == "" {
+			return fmt.Errorf("pledge lease of the token %s is released", t)
+		}
+		if c.pledging[t] {
+			return fmt.Errorf("token %s is already being pledged", t)
+		}
+	}
+	for _, t := range tokens {
+		c.pledging[t] = true
+	}
+	return nil
+}
+
+// unclaimPledgeLease drops the claim, the leases of the failed pledge are
+// released by the sweeper once they expire
+func (c *Core) unclaimPledgeLease(tokens []string) {
+	c.leaseLock.Lock()
+	defer c.leaseLock.Unlock()
+	for _, t := range tokens {
+		delete(c.pledging, t)
+	}
+}
+
+// removePledgeLease will remove the lease once the tokens are pledged
+func (c *Core) removePledgeLease(tokens []string) {
+	c.leaseLock.Lock()
+	defer c.leaseLock.Unlock()
+	for _, t := range tokens {
+		err := c.s.Delete(PledgeLeaseStorage, &model.PledgeLease{}, "token=?", t)
+		if err != nil {
+			c.log.Debug("Failed to remove pledge lease", "token", t, "err", err)
+		}
+	}
+}
+
+// GetPledgeLeases returns all the active pledge leases
+func (c *Core) GetPledgeLeases() []model.PledgeLease {
+	c.leaseLock.Lock()
+	defer c.leaseLock.Unlock()
+	var pls []model.PledgeLease
+	err := c.s.Read(PledgeLeaseStorage, &pls, "token!=?", "")
+	if err != nil {
+		return make([]model.PledgeLease, 0)
+	}
+	return pls
+}
+
+// releaseExpiredPledgeLeases will free the locked tokens of the expired leases
+func (c *Core) releaseExpiredPledgeLeases() {
+	c.leaseLock.Lock()
+	defer c.leaseLock.Unlock()
+	var pls []model.PledgeLease
+	err := c.s.Read(PledgeLeaseStorage, &pls, "expiry<?", time.Now())
+	if err != nil || len(pls) == 0 {
+		return
+	}
+	for _, pl := range pls {
+		// token being pledged is released only if the pledge fails
+		if c.pledging[pl.Token] {
+			continue
+		}
+		// token is freed only if it is still locked, pledged tokens are untouched
+		err = c.w.ReleaseToken(pl.Token)
+		if err != nil {
+			c.log.Error("Failed to release the leased token", "token", pl.Token, "err", err)
+			continue
+		}
+		err = c.s.Delete(PledgeLeaseStorage, &model.PledgeLease{}, "token=?", pl.Token)
+		if err != nil {
+			c.log.Error("Failed to remove pledge lease", "token", pl.Token, "err", err)
+			continue
+		}
+		c.log.Info("Pledge lease expired, token released", "token", pl.Token, "req_id", pl.ReqID, "initiator", pl.InitiatorID)
+	}
+}
+
+func (c *Core) pledgeLeaseSweeper() {
+	t := time.NewTicker(PledgeLeaseSweepInterval)
+	defer t.Stop()
+	for {
+		c.releaseExpiredPledgeLeases()
+		<-t.C
+	}
+}
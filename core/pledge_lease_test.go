@@ -0,0 +1,60 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rubixchain/rubixgoplatform/core/model"
+	"github.com/rubixchain/rubixgoplatform/core/storage"
+	"github.com/rubixchain/rubixgoplatform/core/wallet"
+	"github.com/rubixchain/rubixgoplatform/wrapper/logger"
+)
+
+func TestPledgeLeaseClaim(t *testing.T) {
+	log := logger.New(&logger.LoggerOptions{Name: "test", Color: []logger.ColorOption{logger.ColorOff}})
+	s, err := storage.NewStorageMemory()
+	if err != nil {
+		t.Fatal("Failed to create memory storage", err)
+	}
+	defer s.Close()
+	w, err := wallet.InitMemoryWallet(s, log)
+	if err != nil {
+		t.Fatal("Failed to init memory wallet", err)
+	}
+	c := &Core{s: s, w: w, log: log}
+	err = c.initPledgeLease()
+	if err != nil {
+		t.Fatal("Failed to init pledge lease", err)
+	}
+	err = w.CreateToken(&wallet.Token{TokenID: "token1", DID: "did", TokenValue: 1, TokenStatus: wallet.TokenIsLocked})
+	if err != nil {
+		t.Fatal("Failed to create token", err)
+	}
+	pl := model.PledgeLease{Token: "token1", Expiry: time.Now().Add(-time.Minute)}
+	err = s.Write(PledgeLeaseStorage, &pl)
+	if err != nil {
+		t.Fatal("Failed to write pledge lease", err)
+	}
+	err = c.claimPledgeLease([]string{"token1"})
+	if err != nil {
+		t.Fatal("Failed to claim pledge lease", err)
+	}
+	if c.claimPledgeLease([]string{"token1"}) == nil {
+		t.Fatal("Pledge lease is claimed twice")
+	}
+	// expired lease of the token being pledged is not released
+	c.releaseExpiredPledgeLeases()
+	tk, err := w.ReadToken("token1")
+	if err != nil || tk.TokenStatus != wallet.TokenIsLocked || len(c.GetPledgeLeases()) != 1 {
+		t.Fatal("Token being pledged is released", err)
+	}
+	c.unclaimPledgeLease([]string{"token1"})
+	c.releaseExpiredPledgeLeases()
+	tk, err = w.ReadToken("token1")
+	if err != nil || tk.TokenStatus != wallet.TokenIsFree || len(c.GetPledgeLeases()) != 0 {
+		t.Fatal("Expired pledge lease is not released", err)
+	}
+	if c.claimPledgeLease([]string{"token1"}) == nil {
+		t.Fatal("Released pledge lease is claimed")
+	}
+}
@@ -0,0 +1,215 @@
+package pubsub
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rubixchain/rubixgoplatform/util"
+)
+
+const (
+	// MaxMessageAge is the allowed clock skew & delay of the message, older
+	// messages are dropped as the nonce cache does not hold them
+	MaxMessageAge    time.Duration = 5 * time.Minute
+	NonceCacheSize   int           = 10000
+	NoncePerDID      int           = 1000
+	nonceHashingType string        = "SHA3-256"
+)
+
+// DID is the CIDv1 (dag-pb, sha3-256) of the DID directory in base32
+const (
+	didPrefix string = "bafybmi"
+	didLength int    = 59
+)
+
+// Signer is the DID crypto used to sign the envelope
+type Signer interface {
+	GetDID() string
+	PvtSign(hash []byte) ([]byte, error)
+}
+
+// VerifyFunc verifies the envelope signature of the DID
+type VerifyFunc func(did string, hash []byte, sig []byte) (bool, error)
+
+// Envelope is the signed pubsub message, the signature covers the topic, DID,
+// time, nonce & data
+type Envelope struct {
+	DID       string          `json:"did"`
+	Time      int64           `json:"time"`
+	Nonce     string          `json:"nonce"`
+	Data      json.RawMessage `json:"data"`
+	Signature []byte          `json:"signature"`
+}
+
+func (e *Envelope) hash(topic string) string {
+	return util.CalculateHashString(topic+e.DID+strconv.FormatInt(e.Time, 10)+e.Nonce+string(e.Data), nonceHashingType)
+}
+
+// NewEnvelope creates the envelope signed by the DID
+func NewEnvelope(topic string, dc Signer, model interface{}) (*Envelope, error) {
+	b, err := json.Marshal(model)
+	if err != nil {
+		return nil, err
+	}
+	e := &Envelope{
+		DID:   dc.GetDID(),
+		Time:  time.Now().UnixMilli(),
+		Nonce: util.GetRandString(),
+		Data:  b,
+	}
+	e.Signature, err = dc.PvtSign([]byte(e.hash(topic)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign the message, %s", err.Error())
+	}
+	return e, nil
+}
+
+// isValidDID checks the DID format, the DID is used as the path and the IPFS
+// CID while fetching the DID documents
+func isValidDID(did string) bool {
+	if len(did) != didLength || !strings.HasPrefix(did, didPrefix) {
+		return false
+	}
+	for _, c := range did {
+		if !(c >= 'a' && c <= 'z') && !(c >= '2' && c <= '7') {
+			return false
+		}
+	}
+	return true
+}
+
+// check does the checks which does not need the DID documents
+func (e *Envelope) check() error {
+	if e.DID == "" || e.Nonce == "" || len(e.Signature) == 0 {
+		return fmt.Errorf("message is not signed")
+	}
+	if !isValidDID(e.DID) {
+		return fmt.Errorf("invalid DID")
+	}
+	d := time.Since(time.UnixMilli(e.Time))
+	if d > MaxMessageAge || d < -MaxMessageAge {
+		return fmt.Errorf("message time is out of range")
+	}
+	return nil
+}
+
+// Verify checks the message age & the DID signature
+func (e *Envelope) Verify(topic string, vf VerifyFunc) error {
+	err := e.check()
+	if err != nil {
+		return err
+	}
+	ok, err := vf(e.DID, []byte(e.hash(topic)), e.Signature)
+	if err != nil {
+		return fmt.Errorf("failed to verify the signature, %s", err.Error())
+	}
+	if !ok {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+// nonceCache holds the nonces seen within the message age, the nonce is
+// kept till the message can not be accepted anymore. The messages of the DID
+// are dropped once its share is full. When the cache is full the oldest
+// nonce of the DID holding the most nonces is evicted, the messages of that
+// DID which are not newer than the evicted one are dropped from then on, so
+// the evicted nonce can not be replayed.
+type nonceCache struct {
+	l     sync.Mutex
+	size  int
+	did   int
+	seen  map[string]time.Time
+	count map[string]int
+	floor map[string]int64
+	order []nonceEntry
+}
+
+type nonceEntry struct {
+	did  string
+	key  string
+	time int64
+}
+
+func newNonceCache(size int, did int) *nonceCache {
+	return &nonceCache{
+		size:  size,
+		did:   did,
+		seen:  make(map[string]time.Time),
+		count: make(map[string]int),
+		floor: make(map[string]int64),
+		order: make([]nonceEntry, 0),
+	}
+}
+
+// add records the nonce of the DID with the message time in milliseconds,
+// returns the error if the nonce is already seen or the DID share is full
+func (nc *nonceCache) add(did string, nonce string, mt int64) error {
+	nc.l.Lock()
+	defer nc.l.Unlock()
+	// message time is allowed to be ahead by the message age, so the
+	// nonce is held for twice the age
+	for len(nc.order) > 0 && time.Since(nc.seen[nc.order[0].key]) > 2*MaxMessageAge {
+		nc.remove(0)
+	}
+	// messages older than the message age are dropped by the time check
+	for d, ft := range nc.floor {
+		if time.Since(time.UnixMilli(ft)) > MaxMessageAge {
+			delete(nc.floor, d)
+		}
+	}
+	key := did + "." + nonce
+	if _, ok := nc.seen[key]; ok {
+		return fmt.Errorf("replayed message")
+	}
+	if ft, ok := nc.floor[did]; ok && mt <= ft {
+		return fmt.Errorf("message is older than the evicted nonces of the DID")
+	}
+	if nc.count[did] >= nc.did {
+		return fmt.Errorf("too many messages from the DID")
+	}
+	if len(nc.seen) >= nc.size {
+		nc.evict()
+	}
+	nc.seen[key] = time.Now()
+	nc.count[did]++
+	nc.order = append(nc.order, nonceEntry{did: did, key: key, time: mt})
+	return nil
+}
+
+// evict removes the oldest nonce of the DID holding the most nonces
+func (nc *nonceCache) evict() {
+	top := ""
+	for d, n := range nc.count {
+		if n > nc.count[top] || (n == nc.count[top] && d < top) {
+			top = d
+		}
+	}
+	for i, ne := range nc.order {
+		if ne.did == top {
+			if ne.time > nc.floor[top] {
+				nc.floor[top] = ne.time
+			}
+			nc.remove(i)
+			return
+		}
+	}
+}
+
+func (nc *nonceCache) remove(i int) {
+	ne := nc.order[i]
+	delete(nc.seen, ne.key)
+	nc.count[ne.did]--
+	if nc.count[ne.did] == 0 {
+		delete(nc.count, ne.did)
+	}
+	if i == 0 {
+		nc.order = nc.order[1:]
+		return
+	}
+	nc.order = append(nc.order[:i], nc.order[i+1:]...)
+}
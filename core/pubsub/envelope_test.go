@@ -0,0 +1,114 @@
+package pubsub
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rubixchain/rubixgoplatform/util"
+)
+
+const (
+	testDID  string = "bafybmicf4nnsy6bfuyojosn4tq76i6buheepfhylc6f3ra3sm4c7s7cuma"
+	otherDID string = "bafybmig3qzwpjksxeyxk4vck7l7qs3f42rmwhw7ow3lmxwlvfnxesufova"
+	thirdDID string = "bafybmiujzdegxdncf32epf3dhodzdocis2jhtlgmxgedn73u55xtplpft7"
+)
+
+type testSigner struct {
+	did string
+}
+
+func (ts *testSigner) GetDID() string {
+	return ts.did
+}
+
+func (ts *testSigner) PvtSign(hash []byte) ([]byte, error) {
+	return util.CalculateHash(append([]byte(ts.did), hash...), "SHA3-256"), nil
+}
+
+func testVerify(did string, hash []byte, sig []byte) (bool, error) {
+	return bytes.Equal(util.CalculateHash(append([]byte(did), hash...), "SHA3-256"), sig), nil
+}
+
+func TestEnvelope(t *testing.T) {
+	ts := &testSigner{did: testDID}
+	e, err := NewEnvelope("topic", ts, map[string]string{"did": ts.did})
+	if err != nil {
+		t.Fatal("Failed to create envelope", err)
+	}
+	err = e.Verify("topic", testVerify)
+	if err != nil {
+		t.Fatal("Failed to verify envelope", err)
+	}
+	if e.Verify("other", testVerify) == nil {
+		t.Fatal("Envelope verified on the other topic")
+	}
+	te := *e
+	te.Data = []byte(`{"did":"` + otherDID + `"}`)
+	if te.Verify("topic", testVerify) == nil {
+		t.Fatal("Tampered envelope verified")
+	}
+	te = *e
+	te.DID = otherDID
+	if te.Verify("topic", testVerify) == nil {
+		t.Fatal("Envelope verified for the other DID")
+	}
+	oe, err := NewEnvelope("topic", ts, "old")
+	if err != nil {
+		t.Fatal("Failed to create envelope", err)
+	}
+	oe.Time = time.Now().Add(-2 * MaxMessageAge).UnixMilli()
+	oe.Signature, _ = ts.PvtSign([]byte(oe.hash("topic")))
+	if oe.Verify("topic", testVerify) == nil {
+		t.Fatal("Old envelope verified")
+	}
+}
+
+func TestNonceCache(t *testing.T) {
+	nc := newNonceCache(4, 3)
+	mt := time.Now().UnixMilli()
+	if nc.add(testDID, "n1", mt) != nil || nc.add(testDID, "n1", mt) == nil {
+		t.Fatal("Replayed nonce accepted")
+	}
+	if nc.add(otherDID, "n1", mt) != nil {
+		t.Fatal("Nonce of the other DID rejected")
+	}
+	nc.add(testDID, "n2", mt+1)
+	nc.add(testDID, "n3", mt+2)
+	if nc.add(testDID, "n4", mt+3) == nil {
+		t.Fatal("DID is not bounded")
+	}
+	// full cache evicts the oldest nonce of the DID holding the most nonces
+	if nc.add(thirdDID, "n1", mt) != nil || len(nc.seen) != 4 {
+		t.Fatal("Full cache rejects the other DID")
+	}
+	if nc.count[testDID] != 2 || nc.count[otherDID] != 1 {
+		t.Fatal("Nonce of the wrong DID evicted", nc.count)
+	}
+	// evicted nonce can not be replayed
+	if nc.add(testDID, "n1", mt) == nil {
+		t.Fatal("Replayed evicted nonce accepted")
+	}
+	if nc.add(testDID, "n4", mt+3) != nil {
+		t.Fatal("Newer message of the evicted DID rejected")
+	}
+	for k := range nc.seen {
+		nc.seen[k] = time.Now().Add(-3 * MaxMessageAge)
+	}
+	nc.floor[testDID] = time.Now().Add(-2 * MaxMessageAge).UnixMilli()
+	if nc.add(testDID, "n5", mt) != nil || len(nc.seen) != 1 || len(nc.floor) != 0 {
+		t.Fatal("Expired nonces are not dropped")
+	}
+}
+
+func TestDIDFormat(t *testing.T) {
+	if !isValidDID(testDID) {
+		t.Fatal("Valid DID rejected")
+	}
+	for _, did := range []string{"", "bafybmitestdid", "../../" + testDID[6:], "bafybmi" + strings.Repeat("A", 52)} {
+		if isValidDID(did) {
+			t.Fatal("Invalid DID accepted", did)
+		}
+	}
+}
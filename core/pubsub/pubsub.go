@@ -3,59 +3,315 @@ package pubsub
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
+	"sync"
+	"time"
 
-	ipfsnode "github.com/ipfs/go-ipfs-api"
 	"github.com/rubixchain/rubixgoplatform/wrapper/logger"
 )
 
-type PubSubCallback func(peerID string, topic string, data []byte)
+// PubSubCallback is called with the verified message, did is the signer of
+// the message envelope
+type PubSubCallback func(peerID string, did string, topic string, data []byte)
+
+// Resubscribe backoff on the transport errors
+const (
+	MinBackoff time.Duration = 100 * time.Millisecond
+	MaxBackoff time.Duration = 30 * time.Second
+)
+
+// Signature verification may fetch the DID documents, it runs outside the
+// receive loop and the messages are dropped while all the verifiers are busy.
+// DIDs failed to verify are not looked up again till the expiry.
+const (
+	MaxVerifiers    int           = 16
+	FailedDIDExpiry time.Duration = 10 * time.Minute
+)
+
+// TopicMetrics is the message counters of the topic
+type TopicMetrics struct {
+	Topic        string
+	Subscribed   bool
+	Received     uint64
+	Delivered    uint64
+	Dropped      uint64
+	Published    uint64
+	Errors       uint64
+	Resubscribes uint64
+	LastMessage  time.Time
+}
+
+type topicSub struct {
+	cb   PubSubCallback
+	s    Subscription
+	stop chan struct{}
+}
 
 type PubSub struct {
-	ipfs *ipfsnode.Shell
-	log  logger.Logger
-	sub  map[string]PubSubCallback
+	t       Transport
+	log     logger.Logger
+	l       sync.Mutex
+	sub     map[string]*topicSub
+	metrics map[string]*TopicMetrics
+	verify  VerifyFunc
+	nc      *nonceCache
+	vsem    chan struct{}
+	failed  map[string]time.Time
 }
 
-func NewPubSub(ipfs *ipfsnode.Shell, log logger.Logger) (*PubSub, error) {
-	return &PubSub{ipfs: ipfs, log: log, sub: make(map[string]PubSubCallback)}, nil
+func NewPubSub(t Transport, verify VerifyFunc, log logger.Logger) (*PubSub, error) {
+	ps := &PubSub{
+		t:       t,
+		log:     log.Named("pubsub"),
+		sub:     make(map[string]*topicSub),
+		metrics: make(map[string]*TopicMetrics),
+		verify:  verify,
+		nc:      newNonceCache(NonceCacheSize, NoncePerDID),
+		vsem:    make(chan struct{}, MaxVerifiers),
+		failed:  make(map[string]time.Time),
+	}
+	return ps, nil
+}
+
+// topicMetrics returns the metrics of the topic, caller must hold the lock
+func (ps *PubSub) topicMetrics(topic string) *TopicMetrics {
+	tm, ok := ps.metrics[topic]
+	if !ok {
+		tm = &TopicMetrics{Topic: topic}
+		ps.metrics[topic] = tm
+	}
+	return tm
+}
+
+func (ps *PubSub) updateMetrics(topic string, f func(tm *TopicMetrics)) {
+	ps.l.Lock()
+	defer ps.l.Unlock()
+	f(ps.topicMetrics(topic))
 }
 
 func (ps *PubSub) SubscribeTopic(topic string, cb PubSubCallback) error {
-	f := ps.sub[topic]
-	if f != nil {
+	ps.l.Lock()
+	defer ps.l.Unlock()
+	_, ok := ps.sub[topic]
+	if ok {
 		ps.log.Error("topic already subscribed")
 		return fmt.Errorf("topic already subscribed")
 	}
-	ps.sub[topic] = cb
-	p, err := ps.ipfs.PubSubSubscribe(topic)
+	s, err := ps.t.Subscribe(topic)
 	if err != nil {
 		ps.log.Error("topic failed to subscribe", "err", err)
 		return err
 	}
-	go ps.receivePub(topic, p)
+	ts := &topicSub{
+		cb:   cb,
+		s:    s,
+		stop: make(chan struct{}),
+	}
+	ps.sub[topic] = ts
+	ps.topicMetrics(topic).Subscribed = true
+	go ps.receivePub(topic, ts)
 	return nil
 }
-func (ps *PubSub) receivePub(topic string, p *ipfsnode.PubSubSubscription) {
+
+// Unsubscribe cancels the topic subscription
+func (ps *PubSub) Unsubscribe(topic string) error {
+	ps.l.Lock()
+	defer ps.l.Unlock()
+	ts, ok := ps.sub[topic]
+	if !ok {
+		return fmt.Errorf("topic not subscribed")
+	}
+	delete(ps.sub, topic)
+	ps.topicMetrics(topic).Subscribed = false
+	close(ts.stop)
+	return ts.s.Cancel()
+}
+
+// IsSubscribed checks whether the topic is subscribed
+func (ps *PubSub) IsSubscribed(topic string) bool {
+	ps.l.Lock()
+	defer ps.l.Unlock()
+	_, ok := ps.sub[topic]
+	return ok
+}
+
+// GetMetrics returns the metrics of all the topics
+func (ps *PubSub) GetMetrics() []TopicMetrics {
+	ps.l.Lock()
+	defer ps.l.Unlock()
+	tms := make([]TopicMetrics, 0, len(ps.metrics))
+	for _, tm := range ps.metrics {
+		tms = append(tms, *tm)
+	}
+	sort.Slice(tms, func(i, j int) bool {
+		return tms[i].Topic < tms[j].Topic
+	})
+	return tms
+}
+
+func (ps *PubSub) isStopped(ts *topicSub) bool {
+	select {
+	case <-ts.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// resubscribe replaces the failed subscription, returns false if the topic is
+// unsubscribed meanwhile
+func (ps *PubSub) resubscribe(topic string, ts *topicSub, backoff time.Duration) (Subscription, bool) {
+	select {
+	case <-ts.stop:
+		return nil, false
+	case <-time.After(backoff):
+	}
+	ps.l.Lock()
+	s := ts.s
+	ps.l.Unlock()
+	s.Cancel()
+	ns, err := ps.t.Subscribe(topic)
+	if err != nil {
+		ps.log.Error("Failed to resubscribe the topic", "topic", topic, "err", err)
+		return s, !ps.isStopped(ts)
+	}
+	ps.l.Lock()
+	defer ps.l.Unlock()
+	if ps.isStopped(ts) {
+		ns.Cancel()
+		return nil, false
+	}
+	ts.s = ns
+	ps.topicMetrics(topic).Resubscribes++
+	return ns, true
+}
+
+func (ps *PubSub) receivePub(topic string, ts *topicSub) {
+	backoff := MinBackoff
+	ps.l.Lock()
+	s := ts.s
+	ps.l.Unlock()
 	for {
-		m, err := p.Next()
+		m, err := s.Next()
+		if err != nil {
+			if ps.isStopped(ts) {
+				return
+			}
+			ps.updateMetrics(topic, func(tm *TopicMetrics) { tm.Errors++ })
+			ps.log.Error("Failed to read the message, resubscribing", "topic", topic, "backoff", backoff, "err", err)
+			var ok bool
+			s, ok = ps.resubscribe(topic, ts, backoff)
+			if !ok {
+				return
+			}
+			backoff *= 2
+			if backoff > MaxBackoff {
+				backoff = MaxBackoff
+			}
+			continue
+		}
+		backoff = MinBackoff
+		e, err := ps.parse(m.Data)
 		if err != nil {
-			//ps.log.Error("failed to read message", "err", err)
-			// if strings.Contains(err.Error(), "An existing connection was forcibly closed by the remote host") {
-			// 	break
-			// }
+			ps.drop(topic, m.From, err)
 			continue
 		}
-		cb := ps.sub[topic]
-		if cb != nil {
-			go cb(m.From.String(), topic, m.Data)
+		select {
+		case ps.vsem <- struct{}{}:
+			go func(from string) {
+				err := ps.open(topic, e)
+				<-ps.vsem
+				if err != nil {
+					ps.drop(topic, from, err)
+					return
+				}
+				ps.updateMetrics(topic, func(tm *TopicMetrics) {
+					tm.Received++
+					tm.Delivered++
+					tm.LastMessage = time.Now()
+				})
+				ts.cb(from, e.DID, topic, e.Data)
+			}(m.From)
+		default:
+			ps.drop(topic, m.From, fmt.Errorf("all the verifiers are busy"))
 		}
 	}
 }
 
-func (ps *PubSub) Publish(topic string, model interface{}) error {
-	b, err := json.Marshal(model)
+func (ps *PubSub) drop(topic string, peerID string, err error) {
+	ps.log.Debug("Dropping pubsub message", "topic", topic, "peer", peerID, "err", err)
+	ps.updateMetrics(topic, func(tm *TopicMetrics) {
+		tm.Received++
+		tm.Dropped++
+	})
+}
+
+// parse reads the envelope and does the checks which does not need the DID
+// documents, it runs in the receive loop
+func (ps *PubSub) parse(data []byte) (*Envelope, error) {
+	var e Envelope
+	err := json.Unmarshal(data, &e)
+	if err != nil {
+		return nil, fmt.Errorf("invalid message envelope")
+	}
+	err = e.check()
+	if err != nil {
+		return nil, err
+	}
+	ps.l.Lock()
+	ft, ok := ps.failed[e.DID]
+	ps.l.Unlock()
+	if ok && time.Since(ft) < FailedDIDExpiry {
+		return nil, fmt.Errorf("DID failed to verify recently")
+	}
+	return &e, nil
+}
+
+// open verifies the envelope and drops the replayed messages
+func (ps *PubSub) open(topic string, e *Envelope) error {
+	ok, err := ps.verify(e.DID, []byte(e.hash(topic)), e.Signature)
+	if err != nil {
+		ps.addFailedDID(e.DID)
+		return fmt.Errorf("failed to verify the signature, %s", err.Error())
+	}
+	if !ok {
+		return fmt.Errorf("invalid signature")
+	}
+	return ps.nc.add(e.DID, e.Nonce, e.Time)
+}
+
+// addFailedDID records the DID failed to verify, the expired records are
+// dropped once the records are full
+func (ps *PubSub) addFailedDID(did string) {
+	ps.l.Lock()
+	defer ps.l.Unlock()
+	if len(ps.failed) >= NonceCacheSize {
+		for d, ft := range ps.failed {
+			if time.Since(ft) >= FailedDIDExpiry {
+				delete(ps.failed, d)
+			}
+		}
+		if len(ps.failed) >= NonceCacheSize {
+			return
+		}
+	}
+	ps.failed[did] = time.Now()
+}
+
+// Publish publishes the model in the envelope signed by the DID
+func (ps *PubSub) Publish(topic string, dc Signer, model interface{}) error {
+	e, err := NewEnvelope(topic, dc, model)
 	if err != nil {
 		return err
 	}
-	return ps.ipfs.PubSubPublish(topic, string(b))
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	err = ps.t.Publish(topic, b)
+	if err != nil {
+		return err
+	}
+	ps.updateMetrics(topic, func(tm *TopicMetrics) { tm.Published++ })
+	return nil
 }
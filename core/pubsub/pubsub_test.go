@@ -0,0 +1,121 @@
+package pubsub
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rubixchain/rubixgoplatform/wrapper/logger"
+)
+
+func waitMetrics(ps *PubSub, topic string, f func(tm TopicMetrics) bool) bool {
+	for i := 0; i < 100; i++ {
+		for _, tm := range ps.GetMetrics() {
+			if tm.Topic == topic && f(tm) {
+				return true
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
+
+func TestPubSub(t *testing.T) {
+	log := logger.New(&logger.LoggerOptions{Name: "test", Color: []logger.ColorOption{logger.ColorOff}})
+	hub := NewMemHub()
+	ps1, _ := NewPubSub(hub.Transport("peer1"), testVerify, log)
+	ps2, _ := NewPubSub(hub.Transport("peer2"), testVerify, log)
+	ch := make(chan string, 10)
+	err := ps2.SubscribeTopic("topic", func(peerID string, did string, topic string, data []byte) {
+		ch <- peerID + "." + did
+	})
+	if err != nil {
+		t.Fatal("Failed to subscribe", err)
+	}
+	if ps2.SubscribeTopic("topic", nil) == nil {
+		t.Fatal("Topic subscribed twice")
+	}
+	ts := &testSigner{did: testDID}
+	err = ps1.Publish("topic", ts, "hello")
+	if err != nil {
+		t.Fatal("Failed to publish", err)
+	}
+	select {
+	case addr := <-ch:
+		if addr != "peer1."+testDID {
+			t.Fatal("Invalid sender", addr)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Message not delivered")
+	}
+	// replay & unsigned messages are dropped
+	e, _ := NewEnvelope("topic", ts, "replay")
+	b, _ := json.Marshal(e)
+	hub.Transport("peer3").Publish("topic", b)
+	hub.Transport("peer3").Publish("topic", b)
+	hub.Transport("peer3").Publish("topic", []byte(`"unsigned"`))
+	if !waitMetrics(ps2, "topic", func(tm TopicMetrics) bool { return tm.Received == 4 && tm.Delivered == 2 && tm.Dropped == 2 }) {
+		t.Fatal("Invalid metrics", ps2.GetMetrics())
+	}
+	<-ch
+	// transport failure is recovered by resubscribing
+	ps2.l.Lock()
+	ps2.sub["topic"].s.Cancel()
+	ps2.l.Unlock()
+	if !waitMetrics(ps2, "topic", func(tm TopicMetrics) bool { return tm.Resubscribes == 1 }) {
+		t.Fatal("Topic not resubscribed", ps2.GetMetrics())
+	}
+	ps1.Publish("topic", ts, "after")
+	select {
+	case <-ch:
+	case <-time.After(time.Second):
+		t.Fatal("Message not delivered after resubscribe")
+	}
+	err = ps2.Unsubscribe("topic")
+	if err != nil {
+		t.Fatal("Failed to unsubscribe", err)
+	}
+	if ps2.IsSubscribed("topic") || ps2.Unsubscribe("topic") == nil {
+		t.Fatal("Topic still subscribed")
+	}
+	ps1.Publish("topic", ts, "unsubscribed")
+	select {
+	case <-ch:
+		t.Fatal("Message delivered after unsubscribe")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestFailedDID(t *testing.T) {
+	log := logger.New(&logger.LoggerOptions{Name: "test", Color: []logger.ColorOption{logger.ColorOff}})
+	hub := NewMemHub()
+	var calls int32
+	vf := func(did string, hash []byte, sig []byte) (bool, error) {
+		atomic.AddInt32(&calls, 1)
+		return false, fmt.Errorf("failed to fetch the DID")
+	}
+	ps, _ := NewPubSub(hub.Transport("peer2"), vf, log)
+	err := ps.SubscribeTopic("topic", func(peerID string, did string, topic string, data []byte) {})
+	if err != nil {
+		t.Fatal("Failed to subscribe", err)
+	}
+	ts := &testSigner{did: testDID}
+	hub.Transport("peer1").Publish("topic", nil)
+	e, _ := NewEnvelope("topic", ts, "first")
+	b, _ := json.Marshal(e)
+	hub.Transport("peer1").Publish("topic", b)
+	if !waitMetrics(ps, "topic", func(tm TopicMetrics) bool { return tm.Dropped == 2 }) {
+		t.Fatal("Invalid metrics", ps.GetMetrics())
+	}
+	e, _ = NewEnvelope("topic", ts, "second")
+	b, _ = json.Marshal(e)
+	hub.Transport("peer1").Publish("topic", b)
+	if !waitMetrics(ps, "topic", func(tm TopicMetrics) bool { return tm.Dropped == 3 }) {
+		t.Fatal("Invalid metrics", ps.GetMetrics())
+	}
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Fatal("Failed DID is looked up again", calls)
+	}
+}
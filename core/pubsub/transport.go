@@ -0,0 +1,143 @@
+package pubsub
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/rubixchain/rubixgoplatform/core/ipfsclient"
+)
+
+// Message is the raw message received from the transport
+type Message struct {
+	From string
+	Data []byte
+}
+
+// Subscription is the topic subscription of the transport, Next blocks till
+// the message is received and returns error once the subscription is cancelled
+type Subscription interface {
+	Next() (*Message, error)
+	Cancel() error
+}
+
+// Transport is the pubsub message transport
+type Transport interface {
+	Subscribe(topic string) (Subscription, error)
+	Publish(topic string, data []byte) error
+}
+
+type ipfsTransport struct {
+	ipfs ipfsclient.Client
+}
+
+type ipfsSubscription struct {
+	s ipfsclient.Subscription
+}
+
+// NewIPFSTransport creates the transport using the IPFS pubsub
+func NewIPFSTransport(ipfs ipfsclient.Client) Transport {
+	return &ipfsTransport{ipfs: ipfs}
+}
+
+func (it *ipfsTransport) Subscribe(topic string) (Subscription, error) {
+	s, err := it.ipfs.PubSubSubscribe(topic)
+	if err != nil {
+		return nil, err
+	}
+	return &ipfsSubscription{s: s}, nil
+}
+
+func (it *ipfsTransport) Publish(topic string, data []byte) error {
+	return it.ipfs.PubSubPublish(topic, data)
+}
+
+func (is *ipfsSubscription) Next() (*Message, error) {
+	m, err := is.s.Next()
+	if err != nil {
+		return nil, err
+	}
+	return &Message{From: m.From, Data: m.Data}, nil
+}
+
+func (is *ipfsSubscription) Cancel() error {
+	return is.s.Cancel()
+}
+
+// MemHub connects the in-process transports, messages published on any of the
+// transports are delivered to all the subscriptions of the topic
+type MemHub struct {
+	l    sync.Mutex
+	subs map[string]map[*memSubscription]bool
+}
+
+type memTransport struct {
+	hub    *MemHub
+	peerID string
+}
+
+type memSubscription struct {
+	hub   *MemHub
+	topic string
+	ch    chan *Message
+	done  chan struct{}
+	once  sync.Once
+}
+
+func NewMemHub() *MemHub {
+	return &MemHub{subs: make(map[string]map[*memSubscription]bool)}
+}
+
+// Transport creates the in-process transport of the peer
+func (mh *MemHub) Transport(peerID string) Transport {
+	return &memTransport{hub: mh, peerID: peerID}
+}
+
+func (mt *memTransport) Subscribe(topic string) (Subscription, error) {
+	ms := &memSubscription{
+		hub:   mt.hub,
+		topic: topic,
+		ch:    make(chan *Message, 100),
+		done:  make(chan struct{}),
+	}
+	mt.hub.l.Lock()
+	defer mt.hub.l.Unlock()
+	if mt.hub.subs[topic] == nil {
+		mt.hub.subs[topic] = make(map[*memSubscription]bool)
+	}
+	mt.hub.subs[topic][ms] = true
+	return ms, nil
+}
+
+func (mt *memTransport) Publish(topic string, data []byte) error {
+	mt.hub.l.Lock()
+	defer mt.hub.l.Unlock()
+	for ms := range mt.hub.subs[topic] {
+		m := &Message{From: mt.peerID, Data: append([]byte{}, data...)}
+		select {
+		case ms.ch <- m:
+		case <-ms.done:
+		default:
+			return fmt.Errorf("subscription queue is full")
+		}
+	}
+	return nil
+}
+
+func (ms *memSubscription) Next() (*Message, error) {
+	select {
+	case m := <-ms.ch:
+		return m, nil
+	case <-ms.done:
+		return nil, fmt.Errorf("subscription cancelled")
+	}
+}
+
+func (ms *memSubscription) Cancel() error {
+	ms.once.Do(func() {
+		ms.hub.l.Lock()
+		delete(ms.hub.subs[ms.topic], ms)
+		ms.hub.l.Unlock()
+		close(ms.done)
+	})
+	return nil
+}
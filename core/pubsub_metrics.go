@@ -0,0 +1,25 @@
+package core
+
+import "github.com/rubixchain/rubixgoplatform/core/model"
+
+// GetPubSubMetrics returns the message counters of the pubsub topics
+func (c *Core) GetPubSubMetrics() []model.TopicMetrics {
+	tms := make([]model.TopicMetrics, 0)
+	if c.ps == nil {
+		return tms
+	}
+	for _, tm := range c.ps.GetMetrics() {
+		tms = append(tms, model.TopicMetrics{
+			Topic:        tm.Topic,
+			Subscribed:   tm.Subscribed,
+			Received:     tm.Received,
+			Delivered:    tm.Delivered,
+			Dropped:      tm.Dropped,
+			Published:    tm.Published,
+			Errors:       tm.Errors,
+			Resubscribes: tm.Resubscribes,
+			LastMessage:  tm.LastMessage,
+		})
+	}
+	return tms
+}
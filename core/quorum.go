@@ -1,7 +1,7 @@
 package core
 
 import (
-	"fmt"
+	"sync"
 
 	"github.com/rubixchain/rubixgoplatform/core/storage"
 	"github.com/rubixchain/rubixgoplatform/core/wallet"
@@ -21,6 +21,7 @@ type QuorumManager struct {
 	ql  []string
 	s   storage.Storage
 	log logger.Logger
+	hl  sync.Mutex
 }
 
 type QuorumData struct {
@@ -38,6 +39,11 @@ func NewQuorumManager(s storage.Storage, log logger.Logger) (*QuorumManager, err
 		qm.log.Error("Failed to init quorum storage", "err", err)
 		return nil, err
 	}
+	err = qm.s.Init(QuorumHealthStorage, &QuorumHealth{}, true)
+	if err != nil {
+		qm.log.Error("Failed to init quorum health storage", "err", err)
+		return nil, err
+	}
 	var qd []QuorumData
 	err = qm.s.Read(QuorumStorage, &qd, "type=?", QuorumTypeTwo)
 	if err == nil {
@@ -49,45 +55,39 @@ func NewQuorumManager(s storage.Storage, log logger.Logger) (*QuorumManager, err
 	return qm, nil
 }
 
-// GetQuorum will get the configured or available quorum
-func (qm *QuorumManager) GetQuorum(t int, lastChar string) []string {
+// GetQuorum will get the configured or available quorum, the sample is
+// returned only for the public quorums
+func (qm *QuorumManager) GetQuorum(t int, tid string) ([]string, *QuorumSample) {
 	//QuorumTypeOne is to select quorums from the public pool of quorums instead of a private subnet.
 	//Once a new node is created, it will create a DID. Using the command "registerdid", the peerID and DID will be
 	//published in the network, and all the nodes listening to the subscription will have the DID added on the DIDPeerTable
 	//A new variable quorumList is created, which will contain all the nodes which has DID with same last character as Transaction ID.
 	//It would throw an error if it cannot find any relevant data of if the number of nodes is less than 5.
-	//Then a separate array of type String called quorumAddrList, which will simply contain the address of nodes i.e. PeerID.DID
-	//"quorumAddrList" is returned and checking the availability of nodes would be done in initiateConsensus function in quorum_initiator.go.
+	//The candidates are ordered by a pseudo random sample seeded by the transaction ID so that every node derives
+	//the same order, the leading ones of that order are returned as PeerID.DID, a few unhealthy quorums can be skipped
+	//as long as there are enough candidates left. Quorums verify the selection using verifyQuorumSample.
 	switch t {
 	case QuorumTypeOne:
+		if tid == "" {
+			qm.log.Error("Transaction ID is required to select quorums")
+			return nil, nil
+		}
+		lastChar := string(tid[len(tid)-1])
 		var quorumList []wallet.DIDPeerMap
 		err := qm.s.Read(wallet.DIDPeerStorage, &quorumList, "did_last_char=?", lastChar)
-		fmt.Println(quorumList)
-
 		if err != nil {
 			qm.log.Error("Quorums not present")
-			return nil
+			return nil, nil
 		}
-		if len(quorumList) < 5 {
+		if len(quorumList) < MinQuorumRequired {
 			qm.log.Error("Not enough quorums present")
-			return nil
-		}
-		var quorumAddrList []string
-		fmt.Println(quorumAddrList)
-		quorumCount := 0
-		for _, q := range quorumList {
-			addr := string(q.PeerID + "." + q.DID)
-			quorumAddrList = append(quorumAddrList, addr)
-			quorumCount = quorumCount + 1
-			if quorumCount == 7 {
-				break
-			}
+			return nil, nil
 		}
-		return quorumAddrList
+		return qm.selectQuorums(tid, quorumList)
 	case QuorumTypeTwo:
-		return qm.ql
+		return qm.ql, nil
 	}
-	return nil
+	return nil, nil
 }
 
 func (qm *QuorumManager) AddQuorum(qds []QuorumData) error {
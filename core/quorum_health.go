@@ -0,0 +1,154 @@
+package core
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rubixchain/rubixgoplatform/core/model"
+)
+
+const (
+	QuorumHealthStorage string = "quorumhealth"
+)
+
+const (
+	QuorumSelectCount      int = 7
+	MaxQuorumFailures      int = 3
+	MaxQuorumLatencySample int = 16
+)
+
+// QuorumProbation is the time after the last failure from which the
+// unhealthy quorum is picked again, the next failure extends the probation
+const (
+	QuorumProbation time.Duration = 10 * time.Minute
+)
+
+// QuorumHealth keeps the observed behaviour of a public quorum
+type QuorumHealth struct {
+	DID                 string    `gorm:"column:did;primaryKey" json:"did"`
+	LastSeen            time.Time `gorm:"column:last_seen" json:"last_seen"`
+	LastFailure         time.Time `gorm:"column:last_failure" json:"last_failure"`
+	SuccessCount        int       `gorm:"column:success_count" json:"success_count"`
+	FailureCount        int       `gorm:"column:failure_count" json:"failure_count"`
+	ConsecutiveFailures int       `gorm:"column:consecutive_failures" json:"consecutive_failures"`
+	LatencySamples      string    `gorm:"column:latency_samples" json:"-"`
+	MedianLatency       int64     `gorm:"column:median_latency" json:"median_latency"`
+}
+
+// isHealthy reports whether the quorum can be picked without falling back,
+// the failing quorum is healthy again once its probation is over
+func (qh *QuorumHealth) isHealthy() bool {
+	return qh.ConsecutiveFailures < MaxQuorumFailures || time.Since(qh.LastFailure) > QuorumProbation
+}
+
+// score ranks the quorum, higher is better. Success ratio is smoothed so
+// that unknown quorums start neutral and slow quorums are penalised.
+func (qh *QuorumHealth) score() float64 {
+	ratio := float64(qh.SuccessCount+1) / float64(qh.SuccessCount+qh.FailureCount+2)
+	return ratio / (1 + float64(qh.MedianLatency)/float64(time.Minute.Milliseconds()))
+}
+
+func (qh *QuorumHealth) addLatency(latency time.Duration) {
+	samples := make([]int64, 0)
+	if qh.LatencySamples != "" {
+		for _, s := range strings.Split(qh.LatencySamples, ",") {
+			v, err := strconv.ParseInt(s, 10, 64)
+			if err == nil {
+				samples = append(samples, v)
+			}
+		}
+	}
+	samples = append(samples, latency.Milliseconds())
+	if len(samples) > MaxQuorumLatencySample {
+		samples = samples[len(samples)-MaxQuorumLatencySample:]
+	}
+	str := make([]string, 0)
+	for _, v := range samples {
+		str = append(str, strconv.FormatInt(v, 10))
+	}
+	qh.LatencySamples = strings.Join(str, ",")
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	qh.MedianLatency = samples[len(samples)/2]
+}
+
+func (qm *QuorumManager) getQuorumHealth(did string) *QuorumHealth {
+	var qh QuorumHealth
+	err := qm.s.Read(QuorumHealthStorage, &qh, "did=?", did)
+	if err != nil {
+		return nil
+	}
+	return &qh
+}
+
+// RecordQuorumHealth will update the health record of the quorum after a
+// consensus attempt, nothing is recorded if the initiator context is done as
+// the failure is not caused by the quorum
+func (qm *QuorumManager) RecordQuorumHealth(ctx context.Context, did string, status bool, latency time.Duration) {
+	if ctx.Err() != nil {
+		return
+	}
+	qm.hl.Lock()
+	defer qm.hl.Unlock()
+	qh := qm.getQuorumHealth(did)
+	exist := qh != nil
+	if !exist {
+		qh = &QuorumHealth{
+			DID: did,
+		}
+	}
+	if status {
+		qh.SuccessCount++
+		qh.ConsecutiveFailures = 0
+		qh.LastSeen = time.Now()
+		qh.addLatency(latency)
+	} else {
+		qh.FailureCount++
+		qh.ConsecutiveFailures++
+		qh.LastFailure = time.Now()
+	}
+	var err error
+	if exist {
+		err = qm.s.Update(QuorumHealthStorage, qh, "did=?", did)
+	} else {
+		err = qm.s.Write(QuorumHealthStorage, qh)
+	}
+	if err != nil {
+		qm.log.Error("Failed to update quorum health", "did", did, "err", err)
+	}
+}
+
+// GetAllQuorumHealth returns the health records of all known quorums
+func (qm *QuorumManager) GetAllQuorumHealth() ([]QuorumHealth, error) {
+	var qhs []QuorumHealth
+	err := qm.s.Read(QuorumHealthStorage, &qhs, "did!=?", "")
+	if err != nil {
+		return nil, err
+	}
+	return qhs, nil
+}
+
+// GetQuorumHealth returns the health records of all known quorums
+func (c *Core) GetQuorumHealth() []model.QuorumHealth {
+	ml := make([]model.QuorumHealth, 0)
+	qhs, err := c.qm.GetAllQuorumHealth()
+	if err != nil {
+		return ml
+	}
+	for _, qh := range qhs {
+		ml = append(ml, model.QuorumHealth{
+			DID:                 qh.DID,
+			LastSeen:            qh.LastSeen,
+			LastFailure:         qh.LastFailure,
+			SuccessCount:        qh.SuccessCount,
+			FailureCount:        qh.FailureCount,
+			ConsecutiveFailures: qh.ConsecutiveFailures,
+			MedianLatency:       qh.MedianLatency,
+			Healthy:             qh.isHealthy(),
+			Score:               qh.score(),
+		})
+	}
+	return ml
+}
@@ -0,0 +1,87 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rubixchain/rubixgoplatform/core/storage"
+	"github.com/rubixchain/rubixgoplatform/core/wallet"
+	"github.com/rubixchain/rubixgoplatform/wrapper/logger"
+)
+
+func TestQuorumHealth(t *testing.T) {
+	qh := &QuorumHealth{DID: "did"}
+	for i := 1; i <= MaxQuorumLatencySample+4; i++ {
+		qh.addLatency(time.Duration(i) * time.Millisecond)
+	}
+	if qh.MedianLatency != int64(MaxQuorumLatencySample/2+5) {
+		t.Fatal("Median latency mismatch", qh.MedianLatency)
+	}
+	fast := &QuorumHealth{SuccessCount: 10}
+	slow := &QuorumHealth{SuccessCount: 10, MedianLatency: time.Minute.Milliseconds()}
+	failing := &QuorumHealth{SuccessCount: 2, FailureCount: 8}
+	unknown := &QuorumHealth{}
+	if !(fast.score() > slow.score() && fast.score() > failing.score() && unknown.score() > failing.score()) {
+		t.Fatal("Quorum score order mismatch")
+	}
+
+	s, err := storage.NewStorageMemory()
+	if err != nil {
+		t.Fatal("Failed to create memory storage", err)
+	}
+	defer s.Close()
+	qm, err := NewQuorumManager(s, logger.New(&logger.LoggerOptions{Name: "test", Color: []logger.ColorOption{logger.ColorOff}}))
+	if err != nil {
+		t.Fatal("Failed to create quorum manager", err)
+	}
+	ql := make([]wallet.DIDPeerMap, 0)
+	for i := 0; i < QuorumSelectCount+2; i++ {
+		ql = append(ql, wallet.DIDPeerMap{DID: fmt.Sprintf("did%d", i), PeerID: "peer"})
+	}
+	tid := "8f3b9f1c0d7e4a2b6c5d4e3f2a1b0c9d8e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b"
+	sl := sampleQuorums(tid, ql)
+	ctx := context.Background()
+	// the leading candidate is unhealthy
+	for i := 0; i < MaxQuorumFailures; i++ {
+		qm.RecordQuorumHealth(ctx, sl[0].DID, false, 0)
+	}
+	qm.RecordQuorumHealth(ctx, sl[1].DID, true, time.Millisecond)
+	// failures of the cancelled consensus are not recorded
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	qm.RecordQuorumHealth(cctx, sl[2].DID, false, 0)
+	if qm.getQuorumHealth(sl[2].DID) != nil {
+		t.Fatal("Failure of the cancelled consensus is recorded")
+	}
+	addrs, qs := qm.selectQuorums(tid, ql)
+	if len(addrs) != QuorumSelectCount || len(qs.Skipped) != 1 || qs.Skipped[0] != sl[0].DID {
+		t.Fatal("Unhealthy quorum is not skipped", addrs, qs.Skipped)
+	}
+	for i, a := range addrs {
+		if a != "peer."+sl[i+1].DID {
+			t.Fatal("Quorum selection mismatch", addrs)
+		}
+	}
+	// unhealthy quorum is picked again after the probation
+	qh = qm.getQuorumHealth(sl[0].DID)
+	qh.LastFailure = time.Now().Add(-QuorumProbation - time.Minute)
+	err = s.Update(QuorumHealthStorage, qh, "did=?", qh.DID)
+	if err != nil {
+		t.Fatal("Failed to update quorum health", err)
+	}
+	addrs, qs = qm.selectQuorums(tid, ql)
+	if len(qs.Skipped) != 0 || addrs[0] != "peer."+sl[0].DID {
+		t.Fatal("Quorum is not healthy after the probation", addrs, qs.Skipped)
+	}
+	qm.RecordQuorumHealth(ctx, sl[0].DID, false, 0)
+	if qm.getQuorumHealth(sl[0].DID).isHealthy() {
+		t.Fatal("Failure after the probation is not recorded")
+	}
+	// unhealthy quorums are not skipped below the minimum count
+	addrs, qs = qm.selectQuorums(tid, sl[:MinQuorumRequired])
+	if len(addrs) != MinQuorumRequired || len(qs.Skipped) != 0 {
+		t.Fatal("Fallback quorum mismatch", addrs)
+	}
+}
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -14,12 +15,21 @@ import (
 	wallet "github.com/rubixchain/rubixgoplatform/core/wallet"
 	"github.com/rubixchain/rubixgoplatform/did"
 	"github.com/rubixchain/rubixgoplatform/util"
+	"github.com/rubixchain/rubixgoplatform/wrapper/ensweb"
 )
 
 const (
 	MinQuorumRequired    int = 5
 	MinConsensusRequired int = 5
 )
+
+const (
+	DefaultConsensusTimeout   time.Duration = 20 * time.Minute
+	DefaultCreditCheckTimeout time.Duration = 10 * time.Minute
+	DefaultPledgeTimeout      time.Duration = 5 * time.Minute
+	DefaultSignatureTimeout   time.Duration = ensweb.DefaultTimeout
+	DefaultReceiverTimeout    time.Duration = ensweb.DefaultTimeout
+)
 const (
 	RBTTransferMode int = iota
 	NFTTransferMode
@@ -35,16 +45,17 @@ const (
 )
 
 type ConensusRequest struct {
-	ReqID              string   `json:"req_id"`
-	Type               int      `json:"type"`
-	Mode               int      `json:"mode"`
-	SenderPeerID       string   `json:"sender_peerd_id"`
-	ReceiverPeerID     string   `json:"receiver_peerd_id"`
-	ContractBlock      []byte   `json:"contract_block"`
-	QuorumList         []string `json:"quorum_list"`
-	DeployerPeerID     string   `json:"deployer_peerd_id"`
-	SmartContractToken string   `json:"smart_contract_token"`
-	ExecuterPeerID     string   `json:"executor_peer_id"`
+	ReqID              string        `json:"req_id"`
+	Type               int           `json:"type"`
+	Mode               int           `json:"mode"`
+	SenderPeerID       string        `json:"sender_peerd_id"`
+	ReceiverPeerID     string        `json:"receiver_peerd_id"`
+	ContractBlock      []byte        `json:"contract_block"`
+	QuorumList         []string      `json:"quorum_list"`
+	QuorumSample       *QuorumSample `json:"quorum_sample,omitempty"`
+	DeployerPeerID     string        `json:"deployer_peerd_id"`
+	SmartContractToken string        `json:"smart_contract_token"`
+	ExecuterPeerID     string        `json:"executor_peer_id"`
 }
 
 type ConensusReply struct {
@@ -63,10 +74,13 @@ type ConsensusResult struct {
 }
 
 type ConsensusStatus struct {
-	Credit     CreditScore
-	PledgeLock sync.Mutex
-	P          map[string]*ipfsport.Peer
-	Result     ConsensusResult
+	Credit      CreditScore
+	PledgeLock  sync.Mutex
+	P           map[string]*ipfsport.Peer
+	Result      ConsensusResult
+	QuorumCount int
+	Done        chan struct{}
+	finished    bool
 }
 
 type PledgeDetails struct {
@@ -80,6 +94,8 @@ type PledgeDetails struct {
 
 type PledgeRequest struct {
 	TokensRequired float64 `json:"tokens_required"`
+	ReqID          string  `json:"req_id"`
+	InitiatorID    string  `json:"initiator_id"`
 }
 
 type SignatureRequest struct {
@@ -147,6 +163,7 @@ func (c *Core) QuroumSetup() {
 	c.l.AddRoute(APIUpdatePledgeToken, "POST", c.updatePledgeToken)
 	c.l.AddRoute(APISignatureRequest, "POST", c.signatureRequest)
 	c.l.AddRoute(APISendReceiverToken, "POST", c.updateReceiverToken)
+	go c.pledgeLeaseSweeper()
 	if c.arbitaryMode {
 		c.l.AddRoute(APIMapDIDArbitration, "POST", c.mapDIDArbitration)
 		c.l.AddRoute(APICheckDIDArbitration, "GET", c.chekDIDArbitration)
@@ -181,7 +198,8 @@ func (c *Core) SetupQuorum(didStr string, pwd string, pvtKeyPwd string) error {
 }
 
 func (c *Core) GetAllQuorum() []string {
-	return c.qm.GetQuorum(QuorumTypeTwo, "")
+	ql, _ := c.qm.GetQuorum(QuorumTypeTwo, "")
+	return ql
 }
 
 func (c *Core) AddQuorum(ql []QuorumData) error {
@@ -210,6 +228,9 @@ func (c *Core) sendQuorumCredit(cr *ConensusRequest) {
 		var resp model.BasicResponse
 		err := p.SendJSONRequest("POST", APIQuorumCredit, nil, &cs.Credit, &resp, true)
 		p.Close()
+		c.qlock.Lock()
+		delete(cs.P, v.DID)
+		c.qlock.Unlock()
 		if err != nil {
 			c.log.Error("Failed to send quorum credits", "err", err)
 			continue
@@ -224,7 +245,11 @@ func (c *Core) sendQuorumCredit(cr *ConensusRequest) {
 	// c.qlock.Unlock()
 }
 
-func (c *Core) initiateConsensus(cr *ConensusRequest, sc *contract.Contract, dc did.DIDCrypto) (*wallet.TransactionDetails, map[string]map[string]float64, error) {
+func (c *Core) initiateConsensus(ctx context.Context, cr *ConensusRequest, sc *contract.Contract, dc did.DIDCrypto) (*wallet.TransactionDetails, map[string]map[string]float64, error) {
+	// the overall deadline bounds every step, cancelling the request context aborts the consensus
+	ctx, cancel := context.WithTimeout(ctx, c.consensusTimeout())
+	defer cancel()
+	cst := time.Now()
 	cs := ConsensusStatus{
 		Credit: CreditScore{
 			Credit: make([]CreditSignature, 0),
@@ -235,6 +260,7 @@ func (c *Core) initiateConsensus(cr *ConensusRequest, sc *contract.Contract, dc
 			SuccessCount: 0,
 			FailedCount:  0,
 		},
+		Done: make(chan struct{}),
 	}
 	reqPledgeTokens := float64(0)
 	// TODO:: Need to correct for part tokens
@@ -264,24 +290,30 @@ func (c *Core) initiateConsensus(cr *ConensusRequest, sc *contract.Contract, dc
 		PledgedTokenChainBlock: make(map[string]interface{}),
 		TokenList:              make([]string, 0),
 	}
-	//getting last character from TID
 	tid := util.HexToStr(util.CalculateHash(sc.GetBlock(), "SHA3-256"))
-	lastCharTID := string(tid[len(tid)-1])
 
-	ql := c.qm.GetQuorum(cr.Type, lastCharTID) //passing lastCharTID as a parameter. Made changes in GetQuorum function to take 2 arguments
+	// public quorums are sampled from the TID, so pass it to derive the selection
+	ql, qs := c.qm.GetQuorum(cr.Type, tid)
 	if ql == nil || len(ql) < MinQuorumRequired {
 		c.log.Error("Failed to get required quorums")
 		return nil, nil, fmt.Errorf("failed to get required quorums")
 	}
+	cs.QuorumCount = len(ql)
 	c.qlock.Lock()
 	c.quorumRequest[cr.ReqID] = &cs
 	c.pd[cr.ReqID] = &pd
 	c.qlock.Unlock()
 	cr.QuorumList = ql
+	cr.QuorumSample = qs
 	defer func() {
 		c.qlock.Lock()
 		delete(c.quorumRequest, cr.ReqID)
 		delete(c.pd, cr.ReqID)
+		// release the quorum connections which are not closed by the credit step
+		for k, p := range cs.P {
+			p.Close()
+			delete(cs.P, k)
+		}
 		c.qlock.Unlock()
 	}()
 
@@ -291,41 +323,19 @@ func (c *Core) initiateConsensus(cr *ConensusRequest, sc *contract.Contract, dc
 		//checks the consensus. For type 1 quorums, along with connecting to the quorums, we are checking the balance of the quorum DID
 		//as well. Each quorums should pledge equal amount of tokens and hence, it should have a total of (Transacting RBTs/5) tokens
 		//available for pledging.
-		go c.connectQuorum(cr, a, AlphaQuorumType)
-	}
-	loop := true
-	var err error
-	err = nil
-	for {
-		time.Sleep(time.Second)
-		c.qlock.Lock()
-		cs, ok := c.quorumRequest[cr.ReqID]
-		if !ok {
-			loop = false
-			err = fmt.Errorf("invalid request")
-		} else {
-			if cs.Result.SuccessCount >= MinConsensusRequired {
-				loop = false
-			} else if cs.Result.RunningCount == 0 {
-				loop = false
-				err = fmt.Errorf("consensus failed")
-				c.log.Error("Consensus failed")
-			}
-		}
-		c.qlock.Unlock()
-		if !loop {
-			break
-		}
+		go c.connectQuorum(ctx, cr, a, AlphaQuorumType)
 	}
+	err := c.waitConsensus(ctx, &cs)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	nb, err := c.pledgeQuorumToken(cr, sc, tid, dc)
+	nb, err := c.pledgeQuorumToken(ctx, cr, sc, tid, dc)
 	if err != nil {
 		c.log.Error("Failed to pledge token", "err", err)
 		return nil, nil, err
 	}
+	c.updateTransferJournal(cr.ReqID, TransferPledged, tid, nb, false)
 	c.sendQuorumCredit(cr)
 	ti := sc.GetTransTokenInfo()
 	c.qlock.Lock()
@@ -352,6 +362,8 @@ func (c *Core) initiateConsensus(cr *ConensusRequest, sc *contract.Contract, dc
 			return nil, nil, err
 		}
 		defer rp.Close()
+		// receiver may apply the block even if the request fails from here
+		c.updateTransferJournal(cr.ReqID, TransferSending, "", nil, rp.IsLocal())
 		sr := SendTokenRequest{
 			Address:         cr.SenderPeerID + "." + sc.GetSenderDID(),
 			TokenInfo:       ti,
@@ -359,7 +371,10 @@ func (c *Core) initiateConsensus(cr *ConensusRequest, sc *contract.Contract, dc
 			QuorumList:      cr.QuorumList,
 		}
 		var br model.BasicResponse
-		err = rp.SendJSONRequest("POST", APISendReceiverToken, nil, &sr, &br, true)
+		rt := c.stepTimeout(c.cfg.CfgData.ConsensusConfig.ReceiverTimeout, DefaultReceiverTimeout)
+		rctx, rcancel := context.WithTimeout(ctx, rt)
+		err = rp.SendJSONRequestWithContext(rctx, "POST", APISendReceiverToken, nil, &sr, &br, true, rt)
+		rcancel()
 		if err != nil {
 			c.log.Error("Unable to send tokens to receiver", "err", err)
 			return nil, nil, err
@@ -368,22 +383,12 @@ func (c *Core) initiateConsensus(cr *ConensusRequest, sc *contract.Contract, dc
 			c.log.Error("Unable to send tokens to receiver", "msg", br.Message)
 			return nil, nil, fmt.Errorf("unable to send tokens to receiver, " + br.Message)
 		}
-		err = c.w.TokensTransferred(sc.GetSenderDID(), ti, nb, rp.IsLocal())
-		if err != nil {
-			c.log.Error("Failed to transfer tokens", "err", err)
-			return nil, nil, err
-		}
-		for _, t := range ti {
-			c.w.UnPin(t.Token, wallet.PrevSenderRole, sc.GetSenderDID())
-		}
-		//call ipfs repo gc after unpinnning
-		c.ipfsRepoGc()
+		c.updateTransferJournal(cr.ReqID, TransferSent, "", nil, rp.IsLocal())
 		nbid, err := nb.GetBlockID(ti[0].Token)
 		if err != nil {
 			c.log.Error("Failed to get block id", "err", err)
 			return nil, nil, err
 		}
-
 		td := wallet.TransactionDetails{
 			TransactionID:   tid,
 			TransactionType: nb.GetTransType(),
@@ -394,7 +399,21 @@ func (c *Core) initiateConsensus(cr *ConensusRequest, sc *contract.Contract, dc
 			Comment:         sc.GetComment(),
 			DateTime:        time.Now(),
 			Status:          true,
+			Amount:          sc.GetTotalRBTs(),
+			TotalTime:       float64(time.Since(cst).Milliseconds()),
 		}
+		// token status & the transaction history are updated together
+		err = c.w.TokensTransferred(sc.GetSenderDID(), ti, nb, rp.IsLocal(), &td)
+		if err != nil {
+			c.log.Error("Failed to transfer tokens", "err", err)
+			return nil, nil, err
+		}
+		c.updateTransferJournal(cr.ReqID, TransferCommitted, "", nil, rp.IsLocal())
+		for _, t := range ti {
+			c.w.UnPin(t.Token, wallet.PrevSenderRole, sc.GetSenderDID())
+		}
+		//call ipfs repo gc after unpinnning
+		c.ipfsRepoGc()
 		return &td, pl, nil
 	} else if cr.Mode == DTCommitMode {
 		err = c.w.CreateTokenBlock(nb)
@@ -456,7 +475,7 @@ func (c *Core) initiateConsensus(cr *ConensusRequest, sc *contract.Contract, dc
 			SmartContractBlockHash: newBlockId,
 		}
 
-		err = c.publishNewEvent(&newEvent)
+		err = c.publishNewEvent(dc, &newEvent)
 		if err != nil {
 			c.log.Error("Failed to publish smart contract deployed info")
 		}
@@ -501,7 +520,7 @@ func (c *Core) initiateConsensus(cr *ConensusRequest, sc *contract.Contract, dc
 			SmartContractBlockHash: newBlockId,
 		}
 
-		err = c.publishNewEvent(&newEvent)
+		err = c.publishNewEvent(dc, &newEvent)
 		if err != nil {
 			c.log.Error("Failed to publish smart contract Executed info")
 		}
@@ -520,6 +539,56 @@ func (c *Core) initiateConsensus(cr *ConensusRequest, sc *contract.Contract, dc
 	}
 }
 
+// consensusTimeout returns the configured overall consensus deadline
+func (c *Core) consensusTimeout() time.Duration {
+	if c.cfg.CfgData.ConsensusConfig.Timeout > 0 {
+		return time.Duration(c.cfg.CfgData.ConsensusConfig.Timeout) * time.Second
+	}
+	return DefaultConsensusTimeout
+}
+
+// stepTimeout returns the configured deadline of a consensus step
+func (c *Core) stepTimeout(v int, def time.Duration) time.Duration {
+	if v > 0 {
+		return time.Duration(v) * time.Second
+	}
+	return def
+}
+
+// waitConsensus blocks until finishConsensus signals the outcome, the deadline
+// expires or the request is cancelled
+func (c *Core) waitConsensus(ctx context.Context, cs *ConsensusStatus) error {
+	select {
+	case <-cs.Done:
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			c.log.Error("Consensus timed out", "timeout", c.consensusTimeout())
+			return fmt.Errorf("consensus timed out")
+		}
+		c.log.Error("Consensus cancelled")
+		return fmt.Errorf("consensus cancelled")
+	}
+	c.qlock.Lock()
+	defer c.qlock.Unlock()
+	if cs.Result.SuccessCount < MinConsensusRequired {
+		c.log.Error("Consensus failed", "success", cs.Result.SuccessCount, "failed", cs.Result.FailedCount)
+		return fmt.Errorf("consensus failed")
+	}
+	return nil
+}
+
+// checkConsensusDone will signal the initiator once the consensus either
+// reached the required count or can no longer reach it, caller must hold qlock
+func (cs *ConsensusStatus) checkConsensusDone() {
+	if cs.finished {
+		return
+	}
+	if cs.Result.SuccessCount >= MinConsensusRequired || cs.Result.FailedCount > cs.QuorumCount-MinConsensusRequired {
+		cs.finished = true
+		close(cs.Done)
+	}
+}
+
 func (c *Core) startConsensus(id string, qt int) {
 	c.qlock.Lock()
 	defer c.qlock.Unlock()
@@ -567,6 +636,7 @@ func (c *Core) finishConsensus(id string, qt int, p *ipfsport.Peer, status bool,
 				p.Close()
 			}
 		}
+		cs.checkConsensusDone()
 	default:
 		if p != nil {
 			p.Close()
@@ -574,38 +644,48 @@ func (c *Core) finishConsensus(id string, qt int, p *ipfsport.Peer, status bool,
 	}
 }
 
-func (c *Core) connectQuorum(cr *ConensusRequest, addr string, qt int) {
+func (c *Core) connectQuorum(ctx context.Context, cr *ConensusRequest, addr string, qt int) {
 	c.startConsensus(cr.ReqID, qt)
+	st := time.Now()
+	_, qdid, _ := util.ParseAddress(addr)
 	var p *ipfsport.Peer
 	var err error
 	p, err = c.getPeer(addr)
 	if err != nil {
 		c.log.Error("Failed to get peer connection", "err", err)
+		c.qm.RecordQuorumHealth(ctx, qdid, false, 0)
 		c.finishConsensus(cr.ReqID, qt, nil, false, "", nil, nil)
 		return
 	}
-	err = c.initPledgeQuorumToken(cr, p, qt)
+	err = c.initPledgeQuorumToken(ctx, cr, p, qt)
 	if err != nil {
 		c.log.Error("Failed to pledge token", "err", err)
+		c.qm.RecordQuorumHealth(ctx, qdid, false, 0)
 		c.finishConsensus(cr.ReqID, qt, p, false, "", nil, nil)
 		return
 	}
 	var cresp ConensusReply
-	err = p.SendJSONRequest("POST", APIQuorumConsensus, nil, cr, &cresp, true, 10*time.Minute)
+	ct := c.stepTimeout(c.cfg.CfgData.ConsensusConfig.CreditCheckTimeout, DefaultCreditCheckTimeout)
+	cctx, ccancel := context.WithTimeout(ctx, ct)
+	err = p.SendJSONRequestWithContext(cctx, "POST", APIQuorumConsensus, nil, cr, &cresp, true, ct)
+	ccancel()
 	if err != nil {
 		c.log.Error("Failed to get consensus", "err", err)
+		c.qm.RecordQuorumHealth(ctx, qdid, false, 0)
 		c.finishConsensus(cr.ReqID, qt, p, false, "", nil, nil)
 		return
 	}
 	if !cresp.Status {
 		c.log.Error("Faile to get consensus", "msg", cresp.Message)
+		c.qm.RecordQuorumHealth(ctx, qdid, false, 0)
 		c.finishConsensus(cr.ReqID, qt, p, false, "", nil, nil)
 		return
 	}
+	c.qm.RecordQuorumHealth(ctx, qdid, true, time.Since(st))
 	c.finishConsensus(cr.ReqID, qt, p, true, cresp.Hash, cresp.ShareSig, cresp.PrivSig)
 }
 
-func (c *Core) pledgeQuorumToken(cr *ConensusRequest, sc *contract.Contract, tid string, dc did.DIDCrypto) (*block.Block, error) {
+func (c *Core) pledgeQuorumToken(ctx context.Context, cr *ConensusRequest, sc *contract.Contract, tid string, dc did.DIDCrypto) (*block.Block, error) {
 	c.qlock.Lock()
 	pd, ok1 := c.pd[cr.ReqID]
 	cs, ok2 := c.quorumRequest[cr.ReqID]
@@ -765,6 +845,7 @@ func (c *Core) pledgeQuorumToken(cr *ConensusRequest, sc *contract.Contract, tid
 		c.log.Error("Failed to get new block")
 		return nil, fmt.Errorf("failed to get new block")
 	}
+	st := c.stepTimeout(c.cfg.CfgData.ConsensusConfig.SignatureTimeout, DefaultSignatureTimeout)
 	for k := range pd.PledgedTokens {
 		p, ok := cs.P[k]
 		if !ok {
@@ -775,7 +856,9 @@ func (c *Core) pledgeQuorumToken(cr *ConensusRequest, sc *contract.Contract, tid
 			TokenChainBlock: blk,
 		}
 		var srep SignatureReply
-		err := p.SendJSONRequest("POST", APISignatureRequest, nil, &sr, &srep, true)
+		sctx, scancel := context.WithTimeout(ctx, st)
+		err := p.SendJSONRequestWithContext(sctx, "POST", APISignatureRequest, nil, &sr, &srep, true, st)
+		scancel()
 		if err != nil {
 			c.log.Error("Failed to get signature from the quorum", "err", err)
 			return nil, fmt.Errorf("failed to get signature from the quorum")
@@ -790,6 +873,12 @@ func (c *Core) pledgeQuorumToken(cr *ConensusRequest, sc *contract.Contract, tid
 			return nil, fmt.Errorf("failed to update signature to block")
 		}
 	}
+	// quorums may hold the block once the first pledge is sent, record it before
+	err := c.updateTransferJournal(cr.ReqID, TransferPledging, tid, nb, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record the pledging transfer")
+	}
+	pt := c.stepTimeout(c.cfg.CfgData.ConsensusConfig.PledgeTimeout, DefaultPledgeTimeout)
 	for k, v := range pd.PledgedTokens {
 		p, ok := cs.P[k]
 		if !ok {
@@ -806,7 +895,9 @@ func (c *Core) pledgeQuorumToken(cr *ConensusRequest, sc *contract.Contract, tid
 			PledgedTokens:   v,
 			TokenChainBlock: nb.GetBlock(),
 		}
-		err := p.SendJSONRequest("POST", APIUpdatePledgeToken, nil, &ur, &br, true)
+		pctx, pcancel := context.WithTimeout(ctx, pt)
+		err := p.SendJSONRequestWithContext(pctx, "POST", APIUpdatePledgeToken, nil, &ur, &br, true, pt)
+		pcancel()
 		if err != nil {
 			c.log.Error("Failed to update pledge token status", "err", err)
 			return nil, fmt.Errorf("failed to update pledge token status")
@@ -819,13 +910,15 @@ func (c *Core) pledgeQuorumToken(cr *ConensusRequest, sc *contract.Contract, tid
 	return nb, nil
 }
 
-func (c *Core) initPledgeQuorumToken(cr *ConensusRequest, p *ipfsport.Peer, qt int) error {
+func (c *Core) initPledgeQuorumToken(ctx context.Context, cr *ConensusRequest, p *ipfsport.Peer, qt int) error {
+	pt := c.stepTimeout(c.cfg.CfgData.ConsensusConfig.PledgeTimeout, DefaultPledgeTimeout)
+	ctx, cancel := context.WithTimeout(ctx, pt)
+	defer cancel()
 	if qt == AlphaQuorumType {
 		c.qlock.Lock()
 		cs, ok := c.quorumRequest[cr.ReqID]
 		c.qlock.Unlock()
 		if !ok {
-			c.qlock.Unlock()
 			err := fmt.Errorf("invalid request")
 			return err
 		}
@@ -845,13 +938,15 @@ func (c *Core) initPledgeQuorumToken(cr *ConensusRequest, p *ipfsport.Peer, qt i
 		if pd.RemPledgeTokens != 0 {
 			pr := PledgeRequest{
 				TokensRequired: pledgeTokensPerQuorum, // Request the determined number of tokens per quorum
+				ReqID:          cr.ReqID,
+				InitiatorID:    initiatorPeerID(cr),
 			}
 			// l := len(pd.PledgedTokens)
 			// for i := pd.NumPledgedTokens; i < l; i++ {
 			// 	pr.Tokens = append(pr.Tokens, cr.WholeTokens[i])
 			// }
 			var prs PledgeReply
-			err := p.SendJSONRequest("POST", APIReqPledgeToken, nil, &pr, &prs, true)
+			err := p.SendJSONRequestWithContext(ctx, "POST", APIReqPledgeToken, nil, &pr, &prs, true, pt)
 			if err != nil {
 				c.log.Error("Invalid response for pledge request", "err", err)
 				err := fmt.Errorf("invalid pledge request")
@@ -879,10 +974,9 @@ func (c *Core) initPledgeQuorumToken(cr *ConensusRequest, p *ipfsport.Peer, qt i
 		}
 		cs.PledgeLock.Unlock()
 	}
-	count := 0
+	t := time.NewTicker(time.Second)
+	defer t.Stop()
 	for {
-		time.Sleep(time.Second)
-		count++
 		c.qlock.Lock()
 		pd, ok := c.pd[cr.ReqID]
 		c.qlock.Unlock()
@@ -890,11 +984,13 @@ func (c *Core) initPledgeQuorumToken(cr *ConensusRequest, p *ipfsport.Peer, qt i
 			err := fmt.Errorf("invalid pledge request")
 			return err
 		}
-
 		if pd.RemPledgeTokens == 0 {
 			return nil
-		} else if count == 300 {
-			c.log.Error("Unable to pledge token")
+		}
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			c.log.Error("Unable to pledge token", "err", ctx.Err())
 			err := fmt.Errorf("unable to pledge token")
 			return err
 		}
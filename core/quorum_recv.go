@@ -12,11 +12,12 @@ import (
 	"sync"
 	"time"
 
-	ipfsnode "github.com/ipfs/go-ipfs-api"
 	"github.com/rubixchain/rubixgoplatform/block"
 	"github.com/rubixchain/rubixgoplatform/contract"
+	"github.com/rubixchain/rubixgoplatform/core/ipfsclient"
 	"github.com/rubixchain/rubixgoplatform/core/model"
 	"github.com/rubixchain/rubixgoplatform/core/service"
+	"github.com/rubixchain/rubixgoplatform/core/unpledge"
 	"github.com/rubixchain/rubixgoplatform/core/wallet"
 	didcrypto "github.com/rubixchain/rubixgoplatform/did"
 	"github.com/rubixchain/rubixgoplatform/token"
@@ -215,16 +216,21 @@ func (c *Core) quorumRBTConsensus(req *ensweb.Request, did string, qdc didcrypto
 				crep.Message = "Invalid file,err " + err.Error()
 				return c.l.RenderJSON(req, &crep, http.StatusOK)
 			}
-			pcs := util.BytesToString(pcb)
+			pcs, err := unpledge.ParseProof(pcb)
+			if err != nil {
+				c.log.Error("Invalid proof file", "err", err)
+				crep.Message = "Invalid proof file, err " + err.Error()
+				return c.l.RenderJSON(req, &crep, http.StatusOK)
+			}
 
 			senderAddr := cr.SenderPeerID + "." + sc.GetSenderDID()
-			rdid, tid, err := c.getProofverificationDetails(wt[i].Token, senderAddr)
+			rdid, tid, err := c.getProofverificationDetails(wt[i].Token, wt[i].TokenType, senderAddr)
 			if err != nil {
 				c.log.Error("Failed to get pledged for token reciveer did", "err", err)
 				crep.Message = "Failed to get pledged for token reciveer did"
 				return c.l.RenderJSON(req, &crep, http.StatusOK)
 			}
-			pv, err := c.up.ProofVerification(wt[i].Token, pcs, rdid, tid)
+			pv, err := c.up.ProofVerification(wt[i].Token, wt[i].TokenType, pcs, rdid, tid)
 			if err != nil {
 				c.log.Error("Proof Verification Failed due to error ", err)
 				crep.Message = "Proof Verification Failed due to error " + err.Error()
@@ -425,6 +431,13 @@ func (c *Core) quorumSmartContractConsensus(req *ensweb.Request, did string, qdc
 			go c.checkTokenState(t, did, i, tokenStateCheckResult, &wg, conensusRequest.QuorumList, ti.TokenType)
 		}
 		wg.Wait()
+		//4. execute the contract again & check the reported execution
+		err = c.verifySCExecution(consensusContract)
+		if err != nil {
+			c.log.Error("Smart contract execution verification failed", "err", err)
+			consensusReply.Message = "Smart contract execution verification failed, " + err.Error()
+			return c.l.RenderJSON(req, &consensusReply, http.StatusOK)
+		}
 	}
 	for i := range tokenStateCheckResult {
 		if tokenStateCheckResult[i].Error != nil {
@@ -482,6 +495,22 @@ func (c *Core) quorumConensus(req *ensweb.Request) *ensweb.Result {
 		crep.Message = "Quorum is not setup"
 		return c.l.RenderJSON(req, &crep, http.StatusOK)
 	}
+	// public quorums must have been sampled from the transaction ID
+	if cr.Type == QuorumTypeOne {
+		sc := contract.InitContract(cr.ContractBlock, nil)
+		if sc == nil {
+			c.log.Error("Failed to verify quorum selection, invalid contract")
+			crep.Message = "Failed to verify quorum selection, invalid contract"
+			return c.l.RenderJSON(req, &crep, http.StatusOK)
+		}
+		tid := util.HexToStr(util.CalculateHash(sc.GetBlock(), "SHA3-256"))
+		err = c.qm.verifyQuorumSample(tid, did, cr.QuorumList, cr.QuorumSample)
+		if err != nil {
+			c.log.Error("Quorum selection verification failed", "err", err)
+			crep.Message = "Quorum selection verification failed, " + err.Error()
+			return c.l.RenderJSON(req, &crep, http.StatusOK)
+		}
+	}
 	switch cr.Mode {
 	case RBTTransferMode:
 		c.log.Debug("RBT consensus started")
@@ -530,6 +559,8 @@ func (c *Core) reqPledgeToken(req *ensweb.Request) *ensweb.Result {
 		crep.Message = "No tokens left to pledge"
 		return c.l.RenderJSON(req, &crep, http.StatusOK)
 	}
+	// tokens stay locked for the initiator only till the lease expires
+	c.addPledgeLease(&pr, did, wt)
 	presp := PledgeReply{
 		BasicResponse: model.BasicResponse{
 			Status:  true,
@@ -686,12 +717,6 @@ func (c *Core) updateReceiverToken(req *ensweb.Request) *ensweb.Result {
 		c.log.Debug("Token", tokenStateCheckResult[i].Token, "Message", tokenStateCheckResult[i].Message)
 	}
 
-	err = c.w.TokensReceived(did, sr.TokenInfo, b)
-	if err != nil {
-		c.log.Error("Failed to update token status", "err", err)
-		crep.Message = "Failed to update token status"
-		return c.l.RenderJSON(req, &crep, http.StatusOK)
-	}
 	sc := contract.InitContract(b.GetSmartContract(), nil)
 	if sc == nil {
 		c.log.Error("Failed to update token status, missing smart contract")
@@ -716,7 +741,12 @@ func (c *Core) updateReceiverToken(req *ensweb.Request) *ensweb.Result {
 		DateTime:        time.Now(),
 		Status:          true,
 	}
-	c.w.AddTransactionHistory(td)
+	err = c.w.TokensReceived(did, sr.TokenInfo, b, td)
+	if err != nil {
+		c.log.Error("Failed to update token status", "err", err)
+		crep.Message = "Failed to update token status"
+		return c.l.RenderJSON(req, &crep, http.StatusOK)
+	}
 	crep.Status = true
 	crep.Message = "Token received successfully"
 	return c.l.RenderJSON(req, &crep, http.StatusOK)
@@ -778,6 +808,14 @@ func (c *Core) updatePledgeToken(req *ensweb.Request) *ensweb.Result {
 		crep.Message = "Failed to setup quorum crypto"
 		return c.l.RenderJSON(req, &crep, http.StatusOK)
 	}
+	// sweeper must not release the tokens while they are being pledged
+	err = c.claimPledgeLease(ur.PledgedTokens)
+	if err != nil {
+		c.log.Error("Failed to claim pledge lease", "err", err)
+		crep.Message = "Failed to claim pledge lease, " + err.Error()
+		return c.l.RenderJSON(req, &crep, http.StatusOK)
+	}
+	defer c.unclaimPledgeLease(ur.PledgedTokens)
 	b := block.InitBlock(ur.TokenChainBlock, nil)
 	tks := b.GetTransTokens()
 	refID := ""
@@ -861,6 +899,7 @@ func (c *Core) updatePledgeToken(req *ensweb.Request) *ensweb.Result {
 		}
 	}
 
+	c.removePledgeLease(ur.PledgedTokens)
 	for _, t := range ur.PledgedTokens {
 		c.up.AddUnPledge(t)
 	}
@@ -1092,7 +1131,7 @@ func (c *Core) tokenArbitration(req *ensweb.Request) *ensweb.Result {
 		}
 		str := token.GetTokenString(tl, tn)
 		tbr := bytes.NewBuffer([]byte(str))
-		thash, err := c.ipfs.Add(tbr, ipfsnode.Pin(false), ipfsnode.OnlyHash(true))
+		thash, err := c.ipfs.Add(tbr, ipfsclient.Pin(false), ipfsclient.OnlyHash(true))
 		if err != nil {
 			c.log.Error("Failed to do token abitration, failed to get ipfs hash", "err", err)
 			srep.Message = "Failed to do token abitration, failed to get ipfs hash"
@@ -1162,10 +1201,13 @@ func (c *Core) tokenArbitration(req *ensweb.Request) *ensweb.Result {
 	return c.l.RenderJSON(req, &srep, http.StatusOK)
 }
 
-func (c *Core) getProofverificationDetails(tokenID string, senderAddr string) (string, string, error) {
+func (c *Core) getProofverificationDetails(tokenID string, tt int, senderAddr string) (string, string, error) {
 	var receiverDID, txnId string
-	tt := token.RBTTokenType
 	blk := c.w.GetLatestTokenBlock(tokenID, tt)
+	if blk == nil {
+		c.log.Error("Failed to get the latest token block. Unable to verify proof file")
+		return "", "", fmt.Errorf("failed to get the latest token block")
+	}
 
 	pbid, err := blk.GetPrevBlockID(tokenID)
 	if err != nil {
@@ -0,0 +1,183 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/rubixchain/rubixgoplatform/core/wallet"
+	"github.com/rubixchain/rubixgoplatform/util"
+)
+
+// MaxSkippedQuorums is the number of candidates ahead of the selection which
+// the initiator can pass over as unreachable, every skipped DID is named in
+// the consensus request
+const (
+	MaxSkippedQuorums int = 3
+)
+
+// QuorumSample carries the public quorum selection of the transaction, the
+// candidates are the DIDs the sample is computed over and the skipped ones
+// are the candidates the initiator passed over as unreachable
+type QuorumSample struct {
+	Candidates []string `json:"candidates"`
+	Skipped    []string `json:"skipped"`
+}
+
+// quorumSampleKey derives the sampling key of the DID for the transaction,
+// any node can recompute it from the transaction ID alone
+func quorumSampleKey(tid string, did string) string {
+	return util.CalculateHashString(tid+did, "SHA3-256")
+}
+
+// sampleLess reports whether the DID a is ahead of the DID b in the sample
+func sampleLess(ka string, a string, kb string, b string) bool {
+	if ka == kb {
+		return a < b
+	}
+	return ka < kb
+}
+
+// sampleQuorums returns the candidates in the reproducible pseudo random order
+// seeded by the transaction ID, independent of the DB insertion order
+func sampleQuorums(tid string, ql []wallet.DIDPeerMap) []wallet.DIDPeerMap {
+	keys := make(map[string]string)
+	for _, q := range ql {
+		keys[q.DID] = quorumSampleKey(tid, q.DID)
+	}
+	sl := make([]wallet.DIDPeerMap, len(ql))
+	copy(sl, ql)
+	sort.Slice(sl, func(i, j int) bool {
+		return sampleLess(keys[sl[i].DID], sl[i].DID, keys[sl[j].DID], sl[j].DID)
+	})
+	return sl
+}
+
+// selectQuorums picks the leading candidates of the transaction sample.
+// Unhealthy candidates are skipped while the skip limit allows it and
+// enough candidates are left to reach the minimum quorum count, the skipped
+// DIDs are reported in the sample so that quorums can verify the selection.
+func (qm *QuorumManager) selectQuorums(tid string, ql []wallet.DIDPeerMap) ([]string, *QuorumSample) {
+	qs := &QuorumSample{
+		Candidates: make([]string, 0, len(ql)),
+		Skipped:    make([]string, 0),
+	}
+	for _, q := range ql {
+		qs.Candidates = append(qs.Candidates, q.DID)
+	}
+	sl := sampleQuorums(tid, ql)
+	addrs := make([]string, 0)
+	for i, q := range sl {
+		if len(addrs) == QuorumSelectCount {
+			break
+		}
+		qh := qm.getQuorumHealth(q.DID)
+		if qh != nil && !qh.isHealthy() && len(qs.Skipped) < MaxSkippedQuorums && len(sl)-i-1 >= MinQuorumRequired-len(addrs) {
+			qs.Skipped = append(qs.Skipped, q.DID)
+			continue
+		}
+		addrs = append(addrs, q.PeerID+"."+q.DID)
+	}
+	if len(qs.Skipped) > 0 {
+		qm.log.Info("Skipped unhealthy quorums", "skipped", qs.Skipped)
+	}
+	return addrs, qs
+}
+
+// verifyQuorumSample checks that the public quorum list is the exact leading
+// part of the transaction sample computed over the candidates of the request,
+// only the named skipped candidates may be passed over. Every candidate must
+// match the TID last character, the selected quorums must be known locally
+// (except the verifying quorum itself) and no locally known DID ahead of the
+// selection may be left out of the candidates, so the initiator can neither
+// hand pick the quorums nor drop the ones it does not like.
+func (qm *QuorumManager) verifyQuorumSample(tid string, self string, ql []string, qs *QuorumSample) error {
+	if len(ql) < MinQuorumRequired || len(ql) > QuorumSelectCount {
+		return fmt.Errorf("invalid quorum count %d", len(ql))
+	}
+	if qs == nil {
+		return fmt.Errorf("quorum sample is missing")
+	}
+	if len(qs.Skipped) > MaxSkippedQuorums {
+		return fmt.Errorf("too many skipped quorums %d", len(qs.Skipped))
+	}
+	lastChar := string(tid[len(tid)-1])
+	var known []wallet.DIDPeerMap
+	err := qm.s.Read(wallet.DIDPeerStorage, &known, "did_last_char=?", lastChar)
+	if err != nil {
+		known = make([]wallet.DIDPeerMap, 0)
+	}
+	eligible := make(map[string]bool)
+	for _, q := range known {
+		eligible[q.DID] = true
+	}
+	// our own DID may not be in the DID peer table
+	if self != "" && string(self[len(self)-1]) == lastChar {
+		eligible[self] = true
+	}
+	candidates := make(map[string]bool)
+	cl := make([]wallet.DIDPeerMap, 0, len(qs.Candidates))
+	for _, did := range qs.Candidates {
+		if did == "" || string(did[len(did)-1]) != lastChar {
+			return fmt.Errorf("quorum %s is not eligible for the transaction", did)
+		}
+		if candidates[did] {
+			return fmt.Errorf("duplicate candidate %s", did)
+		}
+		candidates[did] = true
+		cl = append(cl, wallet.DIDPeerMap{DID: did})
+	}
+	listed := make(map[string]bool)
+	for _, addr := range ql {
+		_, did, ok := util.ParseAddress(addr)
+		if !ok || did == "" {
+			return fmt.Errorf("invalid quorum address %s", addr)
+		}
+		if !candidates[did] {
+			return fmt.Errorf("quorum %s is not a candidate", did)
+		}
+		if listed[did] {
+			return fmt.Errorf("duplicate quorum %s", did)
+		}
+		if !eligible[did] {
+			return fmt.Errorf("quorum %s is not known", did)
+		}
+		listed[did] = true
+	}
+	skipped := make(map[string]bool)
+	for _, did := range qs.Skipped {
+		if !candidates[did] || listed[did] || skipped[did] {
+			return fmt.Errorf("invalid skipped quorum %s", did)
+		}
+		skipped[did] = true
+	}
+	// walk the sample, the selection must be its leading part apart from the skipped DIDs
+	n := 0
+	last := ""
+	for _, q := range sampleQuorums(tid, cl) {
+		if n == len(ql) {
+			if skipped[q.DID] {
+				return fmt.Errorf("skipped quorum %s is not ahead of the selection", q.DID)
+			}
+			if len(ql) < QuorumSelectCount {
+				return fmt.Errorf("quorum %s is left out of the selection", q.DID)
+			}
+			continue
+		}
+		if skipped[q.DID] {
+			continue
+		}
+		if !listed[q.DID] {
+			return fmt.Errorf("quorum %s is not part of the transaction sample", q.DID)
+		}
+		last = q.DID
+		n++
+	}
+	// locally known DIDs which would be ahead of the selection must be candidates
+	lk := quorumSampleKey(tid, last)
+	for did := range eligible {
+		if !candidates[did] && sampleLess(quorumSampleKey(tid, did), did, lk, last) {
+			return fmt.Errorf("quorum %s is missing from the candidates", did)
+		}
+	}
+	return nil
+}
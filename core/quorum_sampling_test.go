@@ -0,0 +1,136 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/rubixchain/rubixgoplatform/core/storage"
+	"github.com/rubixchain/rubixgoplatform/core/wallet"
+	"github.com/rubixchain/rubixgoplatform/wrapper/logger"
+)
+
+func TestSampleQuorums(t *testing.T) {
+	tid := "8f3b9f1c0d7e4a2b6c5d4e3f2a1b0c9d8e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b"
+	ql := make([]wallet.DIDPeerMap, 0)
+	for i := 0; i < 20; i++ {
+		ql = append(ql, wallet.DIDPeerMap{DID: fmt.Sprintf("bafybmiquorum%02db", i), PeerID: fmt.Sprintf("peer%d", i)})
+	}
+	rl := make([]wallet.DIDPeerMap, 0)
+	for i := len(ql) - 1; i >= 0; i-- {
+		rl = append(rl, ql[i])
+	}
+	s1 := sampleQuorums(tid, ql)
+	s2 := sampleQuorums(tid, rl)
+	if len(s1) != len(ql) {
+		t.Fatal("Sample lost candidates")
+	}
+	for i := range s1 {
+		if s1[i].DID != s2[i].DID {
+			t.Fatal("Sample depends on the insertion order")
+		}
+	}
+	s3 := sampleQuorums(tid[:len(tid)-1]+"c", ql)
+	same := true
+	for i := range s1 {
+		if s1[i].DID != s3[i].DID {
+			same = false
+			break
+		}
+	}
+	if same {
+		t.Fatal("Sample does not depend on the transaction ID")
+	}
+}
+
+func TestVerifyQuorumSample(t *testing.T) {
+	tid := "8f3b9f1c0d7e4a2b6c5d4e3f2a1b0c9d8e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b"
+	s, err := storage.NewStorageMemory()
+	if err != nil {
+		t.Fatal("Failed to create memory storage", err)
+	}
+	defer s.Close()
+	err = s.Init(wallet.DIDPeerStorage, &wallet.DIDPeerMap{}, true)
+	if err != nil {
+		t.Fatal("Failed to init DID peer storage", err)
+	}
+	qm, err := NewQuorumManager(s, logger.New(&logger.LoggerOptions{Name: "test", Color: []logger.ColorOption{logger.ColorOff}}))
+	if err != nil {
+		t.Fatal("Failed to create quorum manager", err)
+	}
+	self := "bafybmiquorumselfb"
+	ql := []wallet.DIDPeerMap{{DID: self, PeerID: "peerself"}}
+	for i := 0; i < QuorumSelectCount+3; i++ {
+		q := wallet.DIDPeerMap{DID: fmt.Sprintf("bafybmiquorum%02db", i), PeerID: fmt.Sprintf("peer%d", i), DIDLastChar: "b"}
+		err = s.Write(wallet.DIDPeerStorage, &q)
+		if err != nil {
+			t.Fatal("Failed to write DID peer", err)
+		}
+		ql = append(ql, q)
+	}
+	sl := sampleQuorums(tid, ql)
+	addrs := func(ql []wallet.DIDPeerMap) []string {
+		al := make([]string, 0)
+		for _, q := range ql {
+			al = append(al, q.PeerID+"."+q.DID)
+		}
+		return al
+	}
+	candidates := func(ql []wallet.DIDPeerMap) []string {
+		dl := make([]string, 0)
+		for _, q := range ql {
+			dl = append(dl, q.DID)
+		}
+		return dl
+	}
+	qs := &QuorumSample{Candidates: candidates(ql)}
+	err = qm.verifyQuorumSample(tid, self, addrs(sl[:QuorumSelectCount]), qs)
+	if err != nil {
+		t.Fatal("Valid quorum sample is rejected", err)
+	}
+	// hand picked quorum outside the leading part of the sample
+	hl := append(append([]wallet.DIDPeerMap{}, sl[:QuorumSelectCount-1]...), sl[QuorumSelectCount+1])
+	err = qm.verifyQuorumSample(tid, self, addrs(hl), qs)
+	if err == nil {
+		t.Fatal("Hand picked quorum is accepted")
+	}
+	// fewer quorums than the available candidates
+	err = qm.verifyQuorumSample(tid, self, addrs(sl[:MinQuorumRequired]), qs)
+	if err == nil {
+		t.Fatal("Short quorum list is accepted")
+	}
+	// skipped quorums must be named and ahead of the selection
+	sqs := &QuorumSample{Candidates: qs.Candidates, Skipped: []string{sl[1].DID}}
+	skl := append([]wallet.DIDPeerMap{sl[0]}, sl[2:QuorumSelectCount+1]...)
+	err = qm.verifyQuorumSample(tid, self, addrs(skl), sqs)
+	if err != nil {
+		t.Fatal("Named skipped quorum is rejected", err)
+	}
+	sqs = &QuorumSample{Candidates: qs.Candidates, Skipped: []string{sl[QuorumSelectCount+1].DID}}
+	err = qm.verifyQuorumSample(tid, self, addrs(sl[:QuorumSelectCount]), sqs)
+	if err == nil {
+		t.Fatal("Skipped quorum behind the selection is accepted")
+	}
+	sqs = &QuorumSample{Candidates: qs.Candidates, Skipped: candidates(sl[:MaxSkippedQuorums+1])}
+	err = qm.verifyQuorumSample(tid, self, addrs(sl[MaxSkippedQuorums+1:MaxSkippedQuorums+1+QuorumSelectCount]), sqs)
+	if err == nil {
+		t.Fatal("Too many skipped quorums are accepted")
+	}
+	// known DID ahead of the selection left out of the candidates
+	oqs := &QuorumSample{Candidates: candidates(append(append([]wallet.DIDPeerMap{}, sl[:1]...), sl[2:]...))}
+	ol := append([]wallet.DIDPeerMap{sl[0]}, sl[2:QuorumSelectCount+1]...)
+	err = qm.verifyQuorumSample(tid, self, addrs(ol), oqs)
+	if err == nil {
+		t.Fatal("Omitted candidate is accepted")
+	}
+	// DID unknown to the verifier is rejected even if it leads the sample
+	uqs := &QuorumSample{Candidates: append(candidates(ql), "bafybmiquorumunknownb")}
+	ul := sampleQuorums(tid, append(append([]wallet.DIDPeerMap{}, ql...), wallet.DIDPeerMap{DID: "bafybmiquorumunknownb", PeerID: "peerx"}))
+	err = qm.verifyQuorumSample(tid, self, addrs(ul[:QuorumSelectCount]), uqs)
+	if err == nil {
+		t.Fatal("Unknown quorum is accepted")
+	}
+	err = qm.verifyQuorumSample(tid, self, addrs(sl[:QuorumSelectCount]), nil)
+	if err == nil {
+		t.Fatal("Missing quorum sample is accepted")
+	}
+}
@@ -5,9 +5,9 @@ import (
 	"fmt"
 	"sync"
 
-	ipfsnode "github.com/ipfs/go-ipfs-api"
 	"github.com/rubixchain/rubixgoplatform/block"
 	"github.com/rubixchain/rubixgoplatform/contract"
+	"github.com/rubixchain/rubixgoplatform/core/ipfsclient"
 	"github.com/rubixchain/rubixgoplatform/core/ipfsport"
 	"github.com/rubixchain/rubixgoplatform/core/wallet"
 	"github.com/rubixchain/rubixgoplatform/did"
@@ -31,22 +31,7 @@ func (c *Core) validateSigner(b *block.Block) bool {
 		return false
 	}
 	for _, signer := range signers {
-		var dc did.DIDCrypto
-		switch b.GetTransType() {
-		case block.TokenGeneratedType, block.TokenBurntType:
-			dc, err = c.SetupForienDID(signer)
-			if err != nil {
-				c.log.Error("failed to setup forien DID", "err", err)
-				return false
-			}
-		default:
-			dc, err = c.SetupForienDIDQuorum(signer)
-			if err != nil {
-				c.log.Error("failed to setup forien DID quorum", "err", err)
-				return false
-			}
-		}
-		err := b.VerifySignature(dc)
+		err := c.verifyBlockSigner(b, signer)
 		if err != nil {
 			c.log.Error("Failed to verify signature", "err", err)
 			return false
@@ -55,6 +40,27 @@ func (c *Core) validateSigner(b *block.Block) bool {
 	return true
 }
 
+// verifyBlockSigner verifies the block signature of the signer
+func (c *Core) verifyBlockSigner(b *block.Block, signer string) error {
+	var dc did.DIDCrypto
+	var err error
+	switch b.GetTransType() {
+	case block.TokenGeneratedType, block.TokenBurntType:
+		dc, err = c.SetupForienDID(signer)
+		if err != nil {
+			c.log.Error("failed to setup forien DID", "err", err)
+			return err
+		}
+	default:
+		dc, err = c.SetupForienDIDQuorum(signer)
+		if err != nil {
+			c.log.Error("failed to setup forien DID quorum", "err", err)
+			return err
+		}
+	}
+	return b.VerifySignature(dc)
+}
+
 func (c *Core) syncParentToken(p *ipfsport.Peer, pt string) error {
 	b, err := c.getFromIPFS(pt)
 	if err != nil {
@@ -183,7 +189,7 @@ func (c *Core) validateTokenOwnership(cr *ConensusRequest, sc *contract.Contract
 			}
 			ct := token.GetTokenString(tl, tn)
 			tb := bytes.NewBuffer([]byte(ct))
-			tid, err := c.ipfs.Add(tb, ipfsnode.Pin(false), ipfsnode.OnlyHash(true))
+			tid, err := c.ipfs.Add(tb, ipfsclient.Pin(false), ipfsclient.OnlyHash(true))
 			if err != nil {
 				c.log.Error("Failed to validate, failed to get token hash", "err", err)
 				return false
@@ -320,7 +326,7 @@ func (c *Core) checkTokenState(tokenId, did string, index int, resultArray []Tok
 	tokenIDTokenStateBuffer := bytes.NewBuffer([]byte(tokenIDTokenStateData))
 
 	//add to ipfs get only the hash of the token+tokenstate
-	tokenIDTokenStateHash, err := c.ipfs.Add(tokenIDTokenStateBuffer, ipfsnode.Pin(false), ipfsnode.OnlyHash(true))
+	tokenIDTokenStateHash, err := c.ipfs.Add(tokenIDTokenStateBuffer, ipfsclient.Pin(false), ipfsclient.OnlyHash(true))
 	if err != nil {
 		c.log.Error("Error adding data to ipfs", err)
 		result.Error = err
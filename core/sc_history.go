@@ -0,0 +1,308 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rubixchain/rubixgoplatform/block"
+	"github.com/rubixchain/rubixgoplatform/core/model"
+)
+
+const (
+	SCHistoryStorage string = "schistory"
+)
+
+const (
+	SCStateKeyAdded    string = "added"
+	SCStateKeyRemoved  string = "removed"
+	SCStateKeyModified string = "modified"
+)
+
+// SCExecution is the index record of the smart contract execution block
+type SCExecution struct {
+	ID          string    `gorm:"column:id;primaryKey" json:"id"`
+	Token       string    `gorm:"column:token" json:"token"`
+	BlockNo     uint64    `gorm:"column:block_no" json:"block_no"`
+	BlockID     string    `gorm:"column:block_id" json:"block_id"`
+	ExecutorDID string    `gorm:"column:executor_did" json:"executor_did"`
+	Data        string    `gorm:"column:data" json:"data"`
+	StateHash   string    `gorm:"column:state_hash" json:"state_hash"`
+	Fuel        uint64    `gorm:"column:fuel" json:"fuel"`
+	Time        time.Time `gorm:"column:time" json:"time"`
+}
+
+func (c *Core) initSCHistory() error {
+	return c.s.Init(SCHistoryStorage, &SCExecution{}, true)
+}
+
+func (se *SCExecution) info() *model.SCExecutionInfo {
+	return &model.SCExecutionInfo{
+		Token:       se.Token,
+		BlockNo:     se.BlockNo,
+		BlockID:     se.BlockID,
+		ExecutorDID: se.ExecutorDID,
+		Data:        se.Data,
+		StateHash:   se.StateHash,
+		Fuel:        se.Fuel,
+		Time:        se.Time,
+	}
+}
+
+// readSCHistory returns the indexed executions sorted by the block number
+func (c *Core) readSCHistory(token string) []SCExecution {
+	var ses []SCExecution
+	err := c.s.Read(SCHistoryStorage, &ses, "token=?", token)
+	if err != nil {
+		return nil
+	}
+	sort.Slice(ses, func(i, j int) bool {
+		return ses[i].BlockNo < ses[j].BlockNo
+	})
+	return ses
+}
+
+// indexSCHistory indexes the execution blocks of the contract token chain
+// which are not indexed yet, the execution time of the blocks executed by
+// the contract server is the time of the indexing
+func (c *Core) indexSCHistory(token string) ([]SCExecution, error) {
+	c.schLock.Lock()
+	defer c.schLock.Unlock()
+	ses := c.readSCHistory(token)
+	indexed := make(map[uint64]bool)
+	for _, se := range ses {
+		indexed[se.BlockNo] = true
+	}
+	blks, _, err := c.w.GetAllTokenBlocks(token, c.TokenType(SmartContractString), "")
+	if err != nil {
+		c.log.Error("Failed to get smart contract token chain", "err", err)
+		return nil, err
+	}
+	added := false
+	for _, bb := range blks {
+		b := block.InitBlock(bb, nil)
+		if b == nil {
+			return nil, fmt.Errorf("failed to initialize smart contract block")
+		}
+		bn, err := b.GetBlockNumber(token)
+		if err != nil {
+			return nil, err
+		}
+		if indexed[bn] || b.GetExecutorDID() == "" {
+			continue
+		}
+		bid, err := b.GetBlockID(token)
+		if err != nil {
+			return nil, err
+		}
+		sce := parseSCExecution(b.GetSmartContractData())
+		se := SCExecution{
+			ID:          token + "." + bid,
+			Token:       token,
+			BlockNo:     bn,
+			BlockID:     bid,
+			ExecutorDID: b.GetExecutorDID(),
+			Data:        sce.Data,
+			StateHash:   sce.StateHash,
+			Fuel:        sce.Fuel,
+			Time:        time.Now(),
+		}
+		if sce.Time > 0 {
+			se.Time = time.Unix(sce.Time, 0)
+		}
+		err = c.s.Write(SCHistoryStorage, &se)
+		if err != nil {
+			c.log.Error("Failed to write smart contract history", "err", err)
+			return nil, err
+		}
+		indexed[bn] = true
+		added = true
+	}
+	if added {
+		ses = c.readSCHistory(token)
+	}
+	return ses, nil
+}
+
+// getSCExecutionAt returns the latest execution as of the block number or the
+// unix time, returns nil if the contract is not executed
+func (c *Core) getSCExecutionAt(token string, bn uint64, t int64) (*SCExecution, error) {
+	ses, err := c.indexSCHistory(token)
+	if err != nil {
+		return nil, err
+	}
+	var se *SCExecution
+	for i := range ses {
+		if t > 0 && ses[i].Time.Unix() > t {
+			break
+		}
+		if t == 0 && bn > 0 && ses[i].BlockNo > bn {
+			break
+		}
+		se = &ses[i]
+	}
+	return se, nil
+}
+
+// getSCStateOf returns the state of the execution, the state is empty if the
+// contract is not executed
+func (c *Core) getSCStateOf(token string, se *SCExecution) ([]byte, error) {
+	if se == nil {
+		return []byte{}, nil
+	}
+	if se.StateHash == "" {
+		return nil, fmt.Errorf("state is not recorded for the block %d, executed by the contract server", se.BlockNo)
+	}
+	return c.getSCStateByHash(token, se.StateHash)
+}
+
+// diffSCState returns the changes of the top level keys, returns nil if any
+// of the states is not a JSON object
+func diffSCState(from []byte, to []byte) []model.SCStateChange {
+	var fm, tm map[string]json.RawMessage
+	if json.Unmarshal(from, &fm) != nil || json.Unmarshal(to, &tm) != nil {
+		return nil
+	}
+	keys := make([]string, 0)
+	for k := range fm {
+		keys = append(keys, k)
+	}
+	for k := range tm {
+		if _, ok := fm[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	changes := make([]model.SCStateChange, 0)
+	for _, k := range keys {
+		fv, fok := fm[k]
+		tv, tok := tm[k]
+		switch {
+		case !fok:
+			changes = append(changes, model.SCStateChange{Key: k, Op: SCStateKeyAdded, To: tv})
+		case !tok:
+			changes = append(changes, model.SCStateChange{Key: k, Op: SCStateKeyRemoved, From: fv})
+		default:
+			var fb, tb bytes.Buffer
+			if json.Compact(&fb, fv) != nil || json.Compact(&tb, tv) != nil || !bytes.Equal(fb.Bytes(), tb.Bytes()) {
+				changes = append(changes, model.SCStateChange{Key: k, Op: SCStateKeyModified, From: fv, To: tv})
+			}
+		}
+	}
+	return changes
+}
+
+// GetSmartContractHistory returns the executions of the smart contract
+func (c *Core) GetSmartContractHistory(req *model.SCHistoryRequest) *model.SCHistoryResponse {
+	resp := &model.SCHistoryResponse{
+		BasicResponse: model.BasicResponse{
+			Status: false,
+		},
+	}
+	_, err := c.w.GetSmartContractToken(req.Token)
+	if err != nil {
+		resp.Message = "Failed to get smart contract history, token does not exist"
+		return resp
+	}
+	ses, err := c.indexSCHistory(req.Token)
+	if err != nil {
+		resp.Message = "Failed to index smart contract history, " + err.Error()
+		return resp
+	}
+	resp.History = make([]model.SCExecutionInfo, 0, len(ses))
+	for i := range ses {
+		resp.History = append(resp.History, *ses[i].info())
+	}
+	resp.Status = true
+	resp.Message = "Got smart contract history"
+	return resp
+}
+
+// GetSmartContractState returns the state of the smart contract as of the
+// block number or the time
+func (c *Core) GetSmartContractState(req *model.SCStateRequest) *model.SCStateResponse {
+	resp := &model.SCStateResponse{
+		BasicResponse: model.BasicResponse{
+			Status: false,
+		},
+	}
+	_, err := c.w.GetSmartContractToken(req.Token)
+	if err != nil {
+		resp.Message = "Failed to get smart contract state, token does not exist"
+		return resp
+	}
+	se, err := c.getSCExecutionAt(req.Token, req.BlockNo, req.Time)
+	if err != nil {
+		resp.Message = "Failed to get smart contract execution, " + err.Error()
+		return resp
+	}
+	state, err := c.getSCStateOf(req.Token, se)
+	if err != nil {
+		resp.Message = "Failed to get smart contract state, " + err.Error()
+		return resp
+	}
+	if se != nil {
+		resp.Execution = se.info()
+	}
+	resp.State = string(state)
+	resp.Status = true
+	resp.Message = "Got smart contract state"
+	return resp
+}
+
+// GetSmartContractStateDiff returns the difference between the states of the
+// two blocks
+func (c *Core) GetSmartContractStateDiff(req *model.SCStateDiffRequest) *model.SCStateDiffResponse {
+	resp := &model.SCStateDiffResponse{
+		BasicResponse: model.BasicResponse{
+			Status: false,
+		},
+	}
+	_, err := c.w.GetSmartContractToken(req.Token)
+	if err != nil {
+		resp.Message = "Failed to get smart contract state diff, token does not exist"
+		return resp
+	}
+	// state of the deployed contract is empty
+	var fse *SCExecution
+	if req.FromBlock > 0 {
+		fse, err = c.getSCExecutionAt(req.Token, req.FromBlock, 0)
+		if err != nil {
+			resp.Message = "Failed to get smart contract execution, " + err.Error()
+			return resp
+		}
+	}
+	tse, err := c.getSCExecutionAt(req.Token, req.ToBlock, 0)
+	if err != nil {
+		resp.Message = "Failed to get smart contract execution, " + err.Error()
+		return resp
+	}
+	fs, err := c.getSCStateOf(req.Token, fse)
+	if err != nil {
+		resp.Message = "Failed to get smart contract state, " + err.Error()
+		return resp
+	}
+	ts, err := c.getSCStateOf(req.Token, tse)
+	if err != nil {
+		resp.Message = "Failed to get smart contract state, " + err.Error()
+		return resp
+	}
+	diff := &model.SCStateDiff{
+		FromBlock: req.FromBlock,
+		ToBlock:   req.ToBlock,
+		Changed:   !bytes.Equal(fs, ts),
+		Changes:   diffSCState(fs, ts),
+	}
+	if fse != nil {
+		diff.FromStateHash = fse.StateHash
+	}
+	if tse != nil {
+		diff.ToStateHash = tse.StateHash
+	}
+	resp.Diff = diff
+	resp.Status = true
+	resp.Message = "Got smart contract state diff"
+	return resp
+}
@@ -0,0 +1,30 @@
+package core
+
+import (
+	"testing"
+)
+
+func TestSCStateDiff(t *testing.T) {
+	from := []byte(`{"a": 1, "b": "x", "c": [1, 2]}`)
+	to := []byte(`{"a":1,"b":"y","d":true}`)
+	changes := diffSCState(from, to)
+	if len(changes) != 3 {
+		t.Fatal("State changes mismatch", changes)
+	}
+	if changes[0].Key != "b" || changes[0].Op != SCStateKeyModified ||
+		changes[1].Key != "c" || changes[1].Op != SCStateKeyRemoved ||
+		changes[2].Key != "d" || changes[2].Op != SCStateKeyAdded {
+		t.Fatal("State changes mismatch", changes)
+	}
+	if diffSCState([]byte("raw"), to) != nil {
+		t.Fatal("Non JSON state is diffed")
+	}
+	se := parseSCExecution("legacy data")
+	if se.Data != "legacy data" || se.StateHash != "" {
+		t.Fatal("Legacy smart contract data is not parsed")
+	}
+	se = parseSCExecution(`{"data":"in","stateHash":"Qm","fuel":10}`)
+	if se.Data != "in" || se.StateHash != "Qm" || se.Fuel != 10 {
+		t.Fatal("Smart contract data is not parsed")
+	}
+}
@@ -0,0 +1,142 @@
+package core
+
+import (
+	"github.com/rubixchain/rubixgoplatform/core/model"
+	"github.com/rubixchain/rubixgoplatform/token"
+)
+
+// subscribeContract subscribes the smart contract events, the subscription is
+// persisted and the token chain is synced from the last seen block
+func (c *Core) subscribeContract(topic string) error {
+	if !c.ps.IsSubscribed(topic) {
+		err := c.ps.SubscribeTopic(topic, c.ContractCallBack)
+		if err != nil {
+			return err
+		}
+	}
+	err := c.w.AddSCSubscription(topic)
+	if err != nil {
+		return err
+	}
+	go c.catchUpContract(topic)
+	return nil
+}
+
+// syncContract syncs the smart contract token chain from the peer and updates
+// the state, returns true if the new blocks are synced
+func (c *Core) syncContract(peerID string, did string, smartContractToken string) (bool, error) {
+	tokenType := token.SmartContractTokenType
+	lbid := c.latestBlockID(smartContractToken, tokenType)
+	p, err := c.getPeer(peerID + "." + did)
+	if err != nil {
+		c.log.Error("Failed to get peer", "err", err)
+		return false, err
+	}
+	defer p.Close()
+	err = c.syncTokenChainFrom(p, "", smartContractToken, tokenType)
+	if err != nil {
+		c.log.Error("Failed to sync token chain block", "err", err)
+		return false, err
+	}
+	c.log.Info("Token chain of " + smartContractToken + " syncing successful")
+	err = c.updateSCState(smartContractToken)
+	if err != nil {
+		c.log.Error("Failed to update smart contract state", "err", err)
+	}
+	_, err = c.indexSCHistory(smartContractToken)
+	if err != nil {
+		c.log.Error("Failed to index smart contract history", "err", err)
+	}
+	nbid := c.latestBlockID(smartContractToken, tokenType)
+	sub := c.w.GetSCSubscription(smartContractToken)
+	if sub != nil {
+		sub.PeerID = peerID
+		sub.DID = did
+		sub.LastBlockID = nbid
+		c.w.UpdateSCSubscription(sub)
+	}
+	return nbid != lbid, nil
+}
+
+func (c *Core) latestBlockID(t string, tokenType int) string {
+	blk := c.w.GetLatestTokenBlock(t, tokenType)
+	if blk == nil {
+		return ""
+	}
+	bid, err := blk.GetBlockID(t)
+	if err != nil {
+		return ""
+	}
+	return bid
+}
+
+// catchUpContract syncs the blocks missed while the node was not subscribed,
+// the peer of the last event is used, otherwise the deployer is used. The
+// callbacks are queued if the new blocks are synced.
+func (c *Core) catchUpContract(topic string) {
+	sub := c.w.GetSCSubscription(topic)
+	if sub == nil {
+		return
+	}
+	peerID, did := sub.PeerID, sub.DID
+	if did == "" {
+		sc, err := c.w.GetSmartContractToken(topic)
+		if err != nil || sc[0].Deployer == "" {
+			c.log.Debug("No peer to sync the smart contract", "token", topic)
+			return
+		}
+		did = sc[0].Deployer
+		peerID = c.w.GetPeerID(did)
+	}
+	if peerID == c.peerID {
+		return
+	}
+	err := c.fetchSmartContractFiles(topic)
+	if err != nil {
+		c.log.Error("Failed to fetch smart contract files", "token", topic, "err", err)
+	}
+	synced, err := c.syncContract(peerID, did, topic)
+	if err != nil {
+		c.log.Error("Failed to catch up smart contract", "token", topic, "err", err)
+		return
+	}
+	if synced {
+		c.log.Info("Smart contract " + topic + " caught up")
+		payload := map[string]interface{}{
+			"smart_contract_hash": topic,
+			"port":                c.cfg.NodePort,
+		}
+		c.queueCallbacks(topic, payload)
+	}
+}
+
+// resumeContractSubscriptions subscribes the smart contracts subscribed
+// before the restart
+func (c *Core) resumeContractSubscriptions() {
+	for _, sub := range c.w.GetSCSubscriptions() {
+		err := c.subscribeContract(sub.SmartContractHash)
+		if err != nil {
+			c.log.Error("Failed to resume smart contract subscription", "token", sub.SmartContractHash, "err", err)
+			continue
+		}
+		c.log.Info("Resumed smart contract subscription " + sub.SmartContractHash)
+	}
+}
+
+// GetContractSubscriptions returns the smart contracts subscribed by the node
+func (c *Core) GetContractSubscriptions() []model.SCSubscription {
+	subs := c.w.GetSCSubscriptions()
+	ms := make([]model.SCSubscription, 0, len(subs))
+	for _, sub := range subs {
+		ms = append(ms, model.SCSubscription{
+			SmartContractToken: sub.SmartContractHash,
+			PeerID:             sub.PeerID,
+			DID:                sub.DID,
+			LastBlockID:        sub.LastBlockID,
+			Active:             c.ps.IsSubscribed(sub.SmartContractHash),
+			CreatedAt:          sub.CreatedAt,
+			UpdatedAt:          sub.UpdatedAt,
+		})
+	}
+	return ms
+}
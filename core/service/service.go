@@ -1,11 +1,10 @@
 package service
 
 import (
-	"crypto/sha256"
 	"fmt"
-	"strconv"
 
 	"github.com/rubixchain/rubixgoplatform/core/storage"
+	"github.com/rubixchain/rubixgoplatform/token"
 	"github.com/rubixchain/rubixgoplatform/wrapper/logger"
 )
 
@@ -18,7 +17,6 @@ const (
 	ArbitrationTable     string = "Arbitration"
 	ArbitrationTempTable string = "ArbitrationTemp"
 	AribitrationLocked   string = "AribirationLocked"
-	HashTable            string = "HashTable"
 )
 
 type Service struct {
@@ -37,11 +35,6 @@ type TokenDetials struct {
 	DID   string `gorm:"column:did"`
 }
 
-type HashEntry struct {
-	Hash  string `gorm:"column:hash;primaryKey"`
-	Value int    `gorm:"column:value"`
-}
-
 func NewService(s storage.Storage, as storage.Storage, log logger.Logger) (*Service, error) {
 	srv := &Service{
 		s:   s,
@@ -66,45 +59,17 @@ func NewService(s storage.Storage, as storage.Storage, log logger.Logger) (*Serv
 	if err != nil {
 		srv.log.Error("Failed to init arbitration locked table")
 	}
-	err = s.Init(HashTable, &HashEntry{}, false)
-	if err != nil {
-		srv.log.Error("Failed to create hash table")
-	}
-	// var he HashEntry
-	// err = s.Read(HashTable, &he, "value=?", 0)
-	// if err != nil {
-	// 	go srv.CalculateHash()
-	// }
 	return srv, nil
 }
 
-func (s *Service) CalculateHash() {
-	for i := 0; i < 5000000; i++ {
-		hash := sha256.Sum256([]byte(strconv.Itoa(i)))
-		hashString := fmt.Sprintf("%x", hash)
-		he := HashEntry{
-			Hash:  hashString,
-			Value: i,
-		}
-		err := s.s.Write(HashTable, &he)
-		if err != nil {
-			s.log.Error("Failed to write hash tbale", "err", err)
-			return
-		}
-		if i%1000 == 0 {
-			s.log.Info("Hash Calulation in progress...", "count", i)
-		}
-	}
-}
-
+// GetTokenNumber returns the token number of the hash from the token index
 func (s *Service) GetTokenNumber(hash string) (int, error) {
-	var he HashEntry
-	err := s.s.Read(HashTable, &he, "hash=?", hash)
-	if err != nil {
+	tn := token.GetTokenNumberFromHash(hash)
+	if tn < 0 {
 		s.log.Error("Failed to get the token number", "hash", hash)
-		return 0, err
+		return 0, fmt.Errorf("invalid token hash")
 	}
-	return he.Value, nil
+	return tn, nil
 }
 
 func (s *Service) GetTokenDetials(t string) (*TokenDetials, error) {
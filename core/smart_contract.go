@@ -4,15 +4,13 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"io"
 	"io/ioutil"
-	"net/http"
 	"os"
 	"path/filepath"
 
 	"github.com/rubixchain/rubixgoplatform/core/model"
 	"github.com/rubixchain/rubixgoplatform/core/wallet"
-	"github.com/rubixchain/rubixgoplatform/token"
+	"github.com/rubixchain/rubixgoplatform/did"
 )
 
 const (
@@ -301,16 +299,37 @@ func (c *Core) FetchSmartContract(requestID string, fetchSmartContractRequest *F
 	return basicResponse
 }
 
-func (c *Core) PublishNewEvent(nc *model.NewContractEvent) {
-	c.publishNewEvent(nc)
+func (c *Core) PublishNewEvent(reqID string, nc *model.NewContractEvent) {
+	br := model.BasicResponse{
+		Status:  true,
+		Message: "New event published successfully",
+	}
+	dc, err := c.SetupDID(reqID, nc.Did)
+	if err != nil {
+		br.Status = false
+		br.Message = "Failed to setup DID, " + err.Error()
+	} else {
+		err = c.publishNewEvent(dc, nc)
+		if err != nil {
+			br.Status = false
+			br.Message = "Failed to publish new event, " + err.Error()
+		}
+	}
+	dch := c.GetWebReq(reqID)
+	if dch == nil {
+		c.log.Error("Failed to get did channels")
+		return
+	}
+	dch.OutChan <- &br
 }
 
-func (c *Core) publishNewEvent(newEvent *model.NewContractEvent) error {
+func (c *Core) publishNewEvent(dc did.DIDCrypto, newEvent *model.NewContractEvent) error {
 	topic := newEvent.SmartContractToken
 	if c.ps != nil {
-		err := c.ps.Publish(topic, newEvent)
+		err := c.ps.Publish(topic, dc, newEvent)
 		if err != nil {
 			c.log.Error("Failed to publish new event", "err", err)
+			return err
 		}
 		c.log.Info("New state published on smart contract " + topic)
 	}
@@ -320,21 +339,48 @@ func (c *Core) publishNewEvent(newEvent *model.NewContractEvent) error {
 func (c *Core) SubsribeContractSetup(requestID string, topic string) error {
 	reqID = requestID
 	c.l.AddRoute(APIPeerStatus, "GET", c.peerStatus)
-	err := c.ps.SubscribeTopic(topic, c.ContractCallBack)
+	err := c.subscribeContract(topic)
 	if err != nil {
-		c.log.Error("Unable to subscribe smart contract ", topic)
+		c.log.Error("Unable to subscribe smart contract "+topic, "err", err)
+		return err
 	}
 	c.log.Info("Subscribing smart contract " + topic + " is successful")
-	return err
+	return nil
+}
+
+// UnsubscribeContract cancels the smart contract event subscription
+func (c *Core) UnsubscribeContract(topic string) error {
+	if c.w.GetSCSubscription(topic) == nil && !c.ps.IsSubscribed(topic) {
+		return fmt.Errorf("smart contract is not subscribed")
+	}
+	if c.ps.IsSubscribed(topic) {
+		err := c.ps.Unsubscribe(topic)
+		if err != nil {
+			c.log.Error("Unable to unsubscribe smart contract "+topic, "err", err)
+			return err
+		}
+	}
+	err := c.w.RemoveSCSubscription(topic)
+	if err != nil {
+		c.log.Error("Failed to remove smart contract subscription "+topic, "err", err)
+		return err
+	}
+	c.log.Info("Unsubscribed smart contract " + topic)
+	return nil
 }
 
-func (c *Core) ContractCallBack(peerID string, topic string, data []byte) {
+func (c *Core) ContractCallBack(peerID string, signer string, topic string, data []byte) {
 	var newEvent model.NewContractEvent
 	var fetchSC FetchSmartContractRequest
 	requestID := reqID
 	err := json.Unmarshal(data, &newEvent)
 	if err != nil {
 		c.log.Error("Failed to get contract details", "err", err)
+		return
+	}
+	if newEvent.Did != signer || newEvent.SmartContractToken != topic {
+		c.log.Error("Smart contract event is not published by the event DID", "did", newEvent.Did, "signer", signer)
+		return
 	}
 	c.log.Info("Update on smart contract " + newEvent.SmartContractToken)
 	if newEvent.Type == 1 {
@@ -369,67 +415,13 @@ func (c *Core) ContractCallBack(peerID string, topic string, data []byte) {
 		c.FetchSmartContract(requestID, &fetchSC)
 		c.log.Info("Smart contract " + smartContractToken + " files fetching successful")
 	}
-	publisherPeerID := peerID
-	did := newEvent.Did
-	tokenType := token.SmartContractTokenType
-	address := publisherPeerID + "." + did
-	p, err := c.getPeer(address)
-	if err != nil {
-		c.log.Error("Failed to get peer", "err", err)
-		return
-	}
-	err = c.syncTokenChainFrom(p, "", smartContractToken, tokenType)
-	if err != nil {
-		c.log.Error("Failed to sync token chain block", "err", err)
-		return
-	}
-	c.log.Info("Token chain of " + smartContractToken + " syncing successful")
-	curlUrl, err := c.w.GetSmartContractTokenUrl(smartContractToken)
+	_, err = c.syncContract(peerID, newEvent.Did, smartContractToken)
 	if err != nil {
-		c.log.Error("Failed to get smart contract token URL", "err", err)
 		return
 	}
 	payload := map[string]interface{}{
 		"smart_contract_hash": newEvent.SmartContractToken,
 		"port":                c.cfg.NodePort,
 	}
-	payLoadBytes, err := json.Marshal(payload)
-	if err != nil {
-		c.log.Error("Failed to marshal JSON", "err", err)
-		return
-	}
-	request, err := http.NewRequest("POST", curlUrl, bytes.NewBuffer(payLoadBytes))
-	if err != nil {
-		fmt.Println("Error creating HTTP request for smart contract statefile updationcallback: ", err)
-		return
-	}
-	request.Header.Set("Content-Type", "application/json; charset=UTF-8")
-	client := &http.Client{}
-	response, err := client.Do(request)
-	if err != nil {
-		fmt.Println("Error sending HTTP request for smart contract statefile updation: ", err)
-		return
-	}
-	if response.StatusCode != http.StatusOK {
-		c.log.Error("Error getting response from SC", "status", response.Status)
-		return
-	}
-	responseBodyBytes, err := io.ReadAll(response.Body)
-	if err != nil {
-		fmt.Printf("Error reading response body: %s\n", err)
-		return
-	}
-	responseBody := string(responseBodyBytes)
-	var responseData map[string]interface{}
-	if err := json.Unmarshal([]byte(responseBody), &responseData); err != nil {
-		c.log.Error("Error parsing JSON:", err)
-		return
-	}
-	message, ok := responseData["message"].(string)
-	if !ok {
-		c.log.Error("Error: 'message' field not found or not a string")
-		return
-	}
-	c.log.Debug(message)
-	defer response.Body.Close()
+	c.queueCallbacks(smartContractToken, payload)
 }
@@ -0,0 +1,214 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rubixchain/rubixgoplatform/contract"
+	"github.com/rubixchain/rubixgoplatform/core/ipfsclient"
+	"github.com/rubixchain/rubixgoplatform/core/model"
+	"github.com/rubixchain/rubixgoplatform/core/wasm"
+)
+
+const (
+	SCBinaryFileName string = "binaryCodeFile.wasm"
+	SCStateFileName  string = "stateFile"
+)
+
+func (c *Core) scFolderPath(token string) string {
+	return c.cfg.DirPath + "SmartContract/" + token
+}
+
+// parseSCExecution parses the smart contract data of the block, the data of
+// the blocks executed by the contract server is returned as the input data
+func parseSCExecution(scData string) *model.SmartContractExecution {
+	var se model.SmartContractExecution
+	err := json.Unmarshal([]byte(scData), &se)
+	if err != nil || se.StateHash == "" {
+		return &model.SmartContractExecution{Data: scData}
+	}
+	return &se
+}
+
+func (c *Core) scStateHash(state []byte) (string, error) {
+	return c.ipfs.Add(bytes.NewReader(state), ipfsclient.OnlyHash(true))
+}
+
+// fetchSmartContractFiles fetches the smart contract files if they are not
+// available in the node
+func (c *Core) fetchSmartContractFiles(token string) error {
+	if _, err := os.Stat(c.scFolderPath(token)); err == nil {
+		return nil
+	}
+	var fetchSC FetchSmartContractRequest
+	fetchSC.SmartContractToken = token
+	folder, err := c.CreateSCTempFolder()
+	if err != nil {
+		c.log.Error("Fetch smart contract failed, failed to create smart contract folder", "err", err)
+		return err
+	}
+	fetchSC.SmartContractTokenPath, err = c.RenameSCFolder(folder, token)
+	if err != nil {
+		c.log.Error("Fetch smart contract failed, failed to create SC folder", "err", err)
+		return err
+	}
+	br := c.FetchSmartContract(reqID, &fetchSC)
+	if !br.Status {
+		os.RemoveAll(fetchSC.SmartContractTokenPath)
+		return fmt.Errorf("failed to fetch smart contract files")
+	}
+	return nil
+}
+
+// getSCState returns the smart contract state of the latest block, returns
+// nil if the contract is executed by the contract server
+func (c *Core) getSCState(token string) ([]byte, error) {
+	blk := c.w.GetLatestTokenBlock(token, c.TokenType(SmartContractString))
+	if blk == nil {
+		return nil, fmt.Errorf("smart contract token chain not synced")
+	}
+	se := parseSCExecution(blk.GetSmartContractData())
+	if se.StateHash == "" {
+		return nil, nil
+	}
+	return c.getSCStateByHash(token, se.StateHash)
+}
+
+// getSCStateByHash returns the state from the state file if the hash matches,
+// otherwise the state is fetched from the network
+func (c *Core) getSCStateByHash(token string, hash string) ([]byte, error) {
+	state, err := ioutil.ReadFile(filepath.Join(c.scFolderPath(token), SCStateFileName))
+	if err == nil {
+		h, err := c.scStateHash(state)
+		if err == nil && h == hash {
+			return state, nil
+		}
+	}
+	return c.getFromIPFS(hash)
+}
+
+// runSmartContract executes the smart contract in the node runtime and
+// returns the smart contract data of the block & the new state, the input
+// data is returned as it is if the contract is not executable by the runtime
+func (c *Core) runSmartContract(token string, did string, amount float64, input string) (string, []byte, error) {
+	err := c.fetchSmartContractFiles(token)
+	if err != nil {
+		return "", nil, err
+	}
+	code, err := ioutil.ReadFile(filepath.Join(c.scFolderPath(token), SCBinaryFileName))
+	if err != nil {
+		c.log.Error("Failed to read smart contract binary", "err", err)
+		return "", nil, err
+	}
+	if !c.scrt.IsContract(code) {
+		c.log.Debug("Smart contract is not executable by the node runtime", "token", token)
+		return input, nil, nil
+	}
+	state, err := c.getSCState(token)
+	if err != nil {
+		c.log.Error("Failed to get smart contract state", "err", err)
+		return "", nil, err
+	}
+	ec := &wasm.Context{
+		CallerDID: did,
+		RBTAmount: amount,
+		Input:     []byte(input),
+		State:     state,
+	}
+	res, err := c.scrt.Execute(context.Background(), code, ec)
+	if err != nil {
+		c.log.Error("Smart contract execution failed", "token", token, "err", err)
+		return "", nil, err
+	}
+	c.log.Debug("Smart contract executed", "token", token, "fuel", res.Fuel)
+	se := model.SmartContractExecution{
+		Data: input,
+		Fuel: res.Fuel,
+		Time: time.Now().Unix(),
+	}
+	se.StateHash, err = c.scStateHash(res.State)
+	if err != nil {
+		c.log.Error("Failed to get smart contract state hash", "err", err)
+		return "", nil, err
+	}
+	for _, e := range res.Events {
+		se.Events = append(se.Events, string(e))
+	}
+	scData, err := json.Marshal(se)
+	if err != nil {
+		return "", nil, err
+	}
+	return string(scData), res.State, nil
+}
+
+// verifySCExecution executes the contract of the execution request again on
+// the state of the latest synced block and checks the fuel, the state hash &
+// the events reported by the executor
+func (c *Core) verifySCExecution(sc *contract.Contract) error {
+	ti := sc.GetTransTokenInfo()
+	if len(ti) == 0 {
+		return fmt.Errorf("smart contract token is missing")
+	}
+	se := parseSCExecution(sc.GetSmartContractData())
+	scData, state, err := c.runSmartContract(ti[0].Token, sc.GetExecutorDID(), sc.GetTotalRBTs(), se.Data)
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		if se.StateHash != "" {
+			return fmt.Errorf("smart contract is not executable by the runtime")
+		}
+		return nil
+	}
+	re := parseSCExecution(scData)
+	if re.StateHash != se.StateHash {
+		return fmt.Errorf("smart contract state hash mismatch")
+	}
+	if re.Fuel != se.Fuel {
+		return fmt.Errorf("smart contract fuel mismatch, expected %d, reported %d", re.Fuel, se.Fuel)
+	}
+	if len(re.Events) != len(se.Events) {
+		return fmt.Errorf("smart contract events mismatch")
+	}
+	for i := range re.Events {
+		if re.Events[i] != se.Events[i] {
+			return fmt.Errorf("smart contract events mismatch")
+		}
+	}
+	return nil
+}
+
+// pinSCState adds & pins the state to IPFS, the state must be available to
+// the network before the block referring it is committed
+func (c *Core) pinSCState(state []byte, hash string) error {
+	h, err := c.ipfs.Add(bytes.NewReader(state))
+	if err != nil {
+		c.log.Error("Failed to add smart contract state to IPFS", "err", err)
+		return err
+	}
+	if h != hash {
+		return fmt.Errorf("smart contract state hash mismatch")
+	}
+	return nil
+}
+
+// writeSCState writes the state file of the executed block, the file is only
+// the local copy of the state pinned in IPFS
+func (c *Core) writeSCState(token string, state []byte) error {
+	return ioutil.WriteFile(filepath.Join(c.scFolderPath(token), SCStateFileName), state, 0644)
+}
+
+// updateSCState updates the state file to the state of the latest block
+func (c *Core) updateSCState(token string) error {
+	state, err := c.getSCState(token)
+	if err != nil || state == nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(c.scFolderPath(token), SCStateFileName), state, 0644)
+}
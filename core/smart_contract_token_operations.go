@@ -143,7 +143,7 @@ func (c *Core) deploySmartContractToken(reqID string, deployReq *model.DeploySma
 		Mode:               SmartContractDeployMode,
 	}
 
-	txnDetails, _, err := c.initiateConsensus(conensusRequest, consensusContract, didCryptoLib)
+	txnDetails, _, err := c.initiateConsensus(c.webReqContext(reqID), conensusRequest, consensusContract, didCryptoLib)
 
 	if err != nil {
 		c.log.Error("Consensus failed", "err", err)
@@ -231,6 +231,20 @@ func (c *Core) executeSmartContractToken(reqID string, executeReq *model.Execute
 		return resp
 	}
 
+	scData, scState, err := c.runSmartContract(executeReq.SmartContractToken, did, smartContractValue, executeReq.SmartContractData)
+	if err != nil {
+		resp.Message = "Failed to execute smart contract, " + err.Error()
+		return resp
+	}
+
+	if scState != nil {
+		err = c.pinSCState(scState, parseSCExecution(scData).StateHash)
+		if err != nil {
+			resp.Message = "Failed to store smart contract state, " + err.Error()
+			return resp
+		}
+	}
+
 	smartContractInfoArray := make([]contract.TokenInfo, 0)
 	smartContractInfo := contract.TokenInfo{
 		Token:      executeReq.SmartContractToken,
@@ -250,7 +264,7 @@ func (c *Core) executeSmartContractToken(reqID string, executeReq *model.Execute
 			Comment:            executeReq.Comment,
 			SmartContractToken: executeReq.SmartContractToken,
 			TransTokens:        smartContractInfoArray,
-			SmartContractData:  executeReq.SmartContractData,
+			SmartContractData:  scData,
 		},
 	}
 
@@ -282,18 +296,28 @@ func (c *Core) executeSmartContractToken(reqID string, executeReq *model.Execute
 		Mode:               SmartContractExecuteMode,
 	}
 
-	txnDetails, _, err := c.initiateConsensus(conensusRequest, consensusContract, didCryptoLib)
+	txnDetails, _, err := c.initiateConsensus(c.webReqContext(reqID), conensusRequest, consensusContract, didCryptoLib)
 
 	if err != nil {
 		c.log.Error("Consensus failed", "err", err)
 		resp.Message = "Consensus failed" + err.Error()
 		return resp
 	}
+	if scState != nil {
+		err = c.writeSCState(executeReq.SmartContractToken, scState)
+		if err != nil {
+			c.log.Error("Failed to write smart contract state file", "err", err)
+		}
+	}
 	et := time.Now()
 	dif := et.Sub(st)
 
 	txnDetails.TotalTime = float64(dif.Milliseconds())
 	c.w.AddTransactionHistory(txnDetails)
+	_, err = c.indexSCHistory(executeReq.SmartContractToken)
+	if err != nil {
+		c.log.Error("Failed to index smart contract history", "err", err)
+	}
 	tokens := make([]string, 0)
 	tokens = append(tokens, executeReq.SmartContractToken)
 	explorerTrans := &ExplorerTrans{
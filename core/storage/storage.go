@@ -2,6 +2,8 @@ package storage
 
 const (
 	StorageDBType int = iota + 1
+	StorageLDBType
+	StorageMemType
 )
 
 type Storage interface {
@@ -14,6 +16,13 @@ type Storage interface {
 	ReadWithOffset(storageName string, offset int, limit int, vaule interface{}, querryString string, querryVaule ...interface{}) error
 	GetDataCount(stroageName string, querryString string, querryVaule ...interface{}) int64
 	Close() error
+	// Begin starts the transaction, all the operations on the returned storage
+	// are applied on Commit and discarded on Rollback
+	Begin() (Storage, error)
+	Commit() error
+	Rollback() error
+	// Backup writes the consistent copy of the storage into the path
+	Backup(path string) error
 }
 
 type StorageType struct {
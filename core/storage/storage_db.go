@@ -1,6 +1,8 @@
 package storage
 
 import (
+	"fmt"
+
 	"github.com/rubixchain/rubixgoplatform/wrapper/adapter"
 	"github.com/rubixchain/rubixgoplatform/wrapper/config"
 	"github.com/rubixchain/rubixgoplatform/wrapper/uuid"
@@ -8,6 +10,7 @@ import (
 
 type StorageDB struct {
 	ad *adapter.Adapter
+	tx bool
 }
 
 func NewStorageDB(cfg *config.Config) (*StorageDB, error) {
@@ -62,9 +65,48 @@ func (s *StorageDB) GetDataCount(stroageName string, querryString string, querry
 
 // Close will close the stroage BD
 func (s *StorageDB) Close() error {
+	if s.tx {
+		return fmt.Errorf("storage in transaction, commit or rollback the transaction")
+	}
 	db, err := s.ad.GetDB().DB()
 	if err != nil {
 		return err
 	}
 	return db.Close()
 }
+
+// Begin will start the transaction
+func (s *StorageDB) Begin() (Storage, error) {
+	if s.tx {
+		return nil, fmt.Errorf("nested transaction is not supported")
+	}
+	ad, err := s.ad.Begin()
+	if err != nil {
+		return nil, err
+	}
+	return &StorageDB{ad: ad, tx: true}, nil
+}
+
+// Commit will commit the transaction
+func (s *StorageDB) Commit() error {
+	if !s.tx {
+		return fmt.Errorf("no transaction in progress")
+	}
+	return s.ad.Commit()
+}
+
+// Rollback will discard the transaction
+func (s *StorageDB) Rollback() error {
+	if !s.tx {
+		return fmt.Errorf("no transaction in progress")
+	}
+	return s.ad.Rollback()
+}
+
+// Backup will write the copy of the storage into the file
+func (s *StorageDB) Backup(path string) error {
+	if s.tx {
+		return fmt.Errorf("storage in transaction, commit or rollback the transaction")
+	}
+	return s.ad.Backup(path)
+}
@@ -1,6 +1,8 @@
 package storage
 
 import (
+	"fmt"
+
 	"github.com/rubixchain/rubixgoplatform/wrapper/adapter"
 	"github.com/rubixchain/rubixgoplatform/wrapper/config"
 	"github.com/rubixchain/rubixgoplatform/wrapper/uuid"
@@ -8,6 +10,7 @@ import (
 
 type StorageFile struct {
 	ad *adapter.Adapter
+	tx bool
 }
 
 func NewStorageFile(cfg *config.Config) (*StorageFile, error) {
@@ -62,9 +65,48 @@ func (s *StorageFile) GetDataCount(stroageName string, querryString string, quer
 
 // Close will close the stroage BD
 func (s *StorageFile) Close() error {
+	if s.tx {
+		return fmt.Errorf("storage in transaction, commit or rollback the transaction")
+	}
 	db, err := s.ad.GetDB().DB()
 	if err != nil {
 		return err
 	}
 	return db.Close()
 }
+
+// Begin will start the transaction
+func (s *StorageFile) Begin() (Storage, error) {
+	if s.tx {
+		return nil, fmt.Errorf("nested transaction is not supported")
+	}
+	ad, err := s.ad.Begin()
+	if err != nil {
+		return nil, err
+	}
+	return &StorageFile{ad: ad, tx: true}, nil
+}
+
+// Commit will commit the transaction
+func (s *StorageFile) Commit() error {
+	if !s.tx {
+		return fmt.Errorf("no transaction in progress")
+	}
+	return s.ad.Commit()
+}
+
+// Rollback will discard the transaction
+func (s *StorageFile) Rollback() error {
+	if !s.tx {
+		return fmt.Errorf("no transaction in progress")
+	}
+	return s.ad.Rollback()
+}
+
+// Backup will write the copy of the storage into the file
+func (s *StorageFile) Backup(path string) error {
+	if s.tx {
+		return fmt.Errorf("storage in transaction, commit or rollback the transaction")
+	}
+	return s.ad.Backup(path)
+}
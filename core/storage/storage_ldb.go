@@ -1,151 +1,779 @@
 package storage
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+	"gorm.io/gorm/schema"
 )
 
+// LDBIndexColumns are the columns with the secondary index, the queries with
+// the equal condition on these columns avoid the full table scan
+var LDBIndexColumns = []string{"did", "token_status", "did_last_char"}
+
+const (
+	ldbRowPrefix   string = "t"
+	ldbIndexPrefix string = "i"
+	ldbSeqPrefix   string = "q"
+	ldbSep         string = "\x00"
+)
+
+var ldbCondRegex = regexp.MustCompile(`^\s*(\w+)\s*(<=|>=|<>|!=|=|<|>)\s*\?\s*$`)
+var ldbNullRegex = regexp.MustCompile(`(?i)^\s*(\w+)\s+(is\s+null|is\s+not\s+null)\s*$`)
+var ldbAndRegex = regexp.MustCompile(`(?i)\s+AND\s+`)
+
+// StorageLDB is the embedded key value storage, rows are kept as the JSON
+// documents keyed by the primary key along with the secondary indexes
 type StorageLDB struct {
-	dirPath string
-	sm      map[string]bool
-	l       sync.Mutex
+	db   *leveldb.DB
+	h    ldbHandle
+	tr   *leveldb.Transaction
+	meta *ldbMeta
+}
+
+// ldbHandle is implemented by both the DB and the transaction
+type ldbHandle interface {
+	Get(key []byte, ro *opt.ReadOptions) ([]byte, error)
+	NewIterator(slice *util.Range, ro *opt.ReadOptions) iterator.Iterator
+	Write(b *leveldb.Batch, wo *opt.WriteOptions) error
+}
+
+// ldbMeta is shared between the storage & its transactions, writers are
+// serialized as the index update is a read-modify-write
+type ldbMeta struct {
+	wl sync.Mutex
+	l  sync.RWMutex
+	pk map[string]string
 }
 
+type ldbField struct {
+	index []int
+	col   string
+	pk    bool
+}
+
+type ldbCond struct {
+	col string
+	op  string
+	val interface{}
+}
+
+type ldbRow struct {
+	pk   string
+	cols map[string]json.RawMessage
+}
+
+var ldbFieldCache sync.Map
+
 func NewStorageLDB(dirPath string) (*StorageLDB, error) {
+	db, err := leveldb.OpenFile(dirPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	return newStorageLDB(db), nil
+}
 
-	s := &StorageLDB{
-		dirPath: dirPath,
-		sm:      make(map[string]bool),
+func newStorageLDB(db *leveldb.DB) *StorageLDB {
+	return &StorageLDB{
+		db: db,
+		h:  db,
+		meta: &ldbMeta{
+			pk: make(map[string]string),
+		},
 	}
-	return s, nil
 }
 
-// Init will initialize storage
-func (s *StorageLDB) Init(storageName string, value interface{}) error {
-	s.l.Lock()
-	_, ok := s.sm[storageName]
-	if !ok {
-		s.sm[storageName] = false
+func (s *StorageLDB) lock() {
+	if s.tr == nil {
+		s.meta.wl.Lock()
+	}
+}
+
+func (s *StorageLDB) unlock() {
+	if s.tr == nil {
+		s.meta.wl.Unlock()
+	}
+}
+
+// ldbFields returns the columns of the model using the gorm column naming
+func ldbFields(t reflect.Type) []ldbField {
+	if f, ok := ldbFieldCache.Load(t); ok {
+		return f.([]ldbField)
+	}
+	fields := make([]ldbField, 0)
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		tag := sf.Tag.Get("gorm")
+		if tag == "-" {
+			continue
+		}
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct && sf.Type != reflect.TypeOf(time.Time{}) {
+			for _, ef := range ldbFields(sf.Type) {
+				ef.index = append([]int{i}, ef.index...)
+				fields = append(fields, ef)
+			}
+			continue
+		}
+		f := ldbField{
+			index: []int{i},
+			col:   schema.NamingStrategy{}.ColumnName("", sf.Name),
+		}
+		for _, o := range strings.Split(tag, ";") {
+			o = strings.TrimSpace(o)
+			if strings.HasPrefix(strings.ToLower(o), "column:") {
+				f.col = o[len("column:"):]
+			} else if strings.ToLower(o) == "primarykey" {
+				f.pk = true
+			}
+		}
+		fields = append(fields, f)
+	}
+	ldbFieldCache.Store(t, fields)
+	return fields
+}
+
+func ldbModelType(value interface{}) (reflect.Type, error) {
+	t := reflect.TypeOf(value)
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("invalid data type")
+	}
+	return t, nil
+}
+
+func ldbPKColumn(fields []ldbField) string {
+	for _, f := range fields {
+		if f.pk {
+			return f.col
+		}
+	}
+	return ""
+}
+
+func ldbRowKey(table string, pk string) []byte {
+	return []byte(ldbRowPrefix + ldbSep + table + ldbSep + pk)
+}
+
+func ldbIndexKey(table string, col string, raw []byte, pk string) []byte {
+	return []byte(ldbIndexPrefix + ldbSep + table + ldbSep + col + ldbSep + string(raw) + ldbSep + pk)
+}
+
+func ldbIndexed(col string) bool {
+	for _, c := range LDBIndexColumns {
+		if c == col {
+			return true
+		}
+	}
+	return false
+}
+
+func encodeLDBRow(v reflect.Value, fields []ldbField) (string, map[string]json.RawMessage, error) {
+	pk := ""
+	cols := make(map[string]json.RawMessage)
+	for _, f := range fields {
+		fv := v.FieldByIndex(f.index)
+		jb, err := json.Marshal(fv.Interface())
+		if err != nil {
+			return "", nil, err
+		}
+		cols[f.col] = jb
+		if f.pk {
+			pk = fmt.Sprintf("%v", fv.Interface())
+		}
+	}
+	return pk, cols, nil
+}
+
+func decodeLDBRow(cols map[string]json.RawMessage, v reflect.Value, fields []ldbField) error {
+	for _, f := range fields {
+		raw, ok := cols[f.col]
+		if !ok {
+			continue
+		}
+		fv := v.FieldByIndex(f.index)
+		err := json.Unmarshal(raw, fv.Addr().Interface())
+		if err != nil {
+			return err
+		}
 	}
-	s.l.Unlock()
 	return nil
 }
 
-func (s *StorageLDB) getStorage(storageName string) {
-	wait := true
-	for {
-		s.l.Lock()
-		status, ok := s.sm[storageName]
-		if ok {
-			if !status {
-				s.sm[storageName] = true
-				wait = false
+func parseLDBQuery(querryString string, querryVaule []interface{}) ([]ldbCond, error) {
+	conds := make([]ldbCond, 0)
+	if strings.TrimSpace(querryString) == "" {
+		return conds, nil
+	}
+	parts := ldbAndRegex.Split(strings.TrimSpace(querryString), -1)
+	i := 0
+	for _, p := range parts {
+		if m := ldbNullRegex.FindStringSubmatch(p); m != nil {
+			op := "null"
+			if strings.Contains(strings.ToLower(m[2]), "not") {
+				op = "notnull"
 			}
-		} else {
-			s.sm[storageName] = true
-			wait = false
+			conds = append(conds, ldbCond{col: m[1], op: op})
+			continue
+		}
+		m := ldbCondRegex.FindStringSubmatch(p)
+		if m == nil {
+			return nil, fmt.Errorf("unsupported query %s", p)
+		}
+		if i >= len(querryVaule) {
+			return nil, fmt.Errorf("query values does not match the query")
+		}
+		conds = append(conds, ldbCond{col: m[1], op: m[2], val: querryVaule[i]})
+		i++
+	}
+	if i != len(querryVaule) {
+		return nil, fmt.Errorf("query values does not match the query")
+	}
+	return conds, nil
+}
+
+// compareLDBValue compares the stored value with the query value
+func compareLDBValue(raw json.RawMessage, val interface{}) (int, bool) {
+	if raw == nil {
+		return 0, false
+	}
+	switch v := val.(type) {
+	case string:
+		var s string
+		if json.Unmarshal(raw, &s) != nil {
+			return 0, false
+		}
+		return strings.Compare(s, v), true
+	case bool:
+		var b bool
+		if json.Unmarshal(raw, &b) != nil {
+			return 0, false
+		}
+		if b == v {
+			return 0, true
+		}
+		return 1, true
+	case time.Time:
+		var t time.Time
+		if json.Unmarshal(raw, &t) != nil {
+			return 0, false
+		}
+		if t.Before(v) {
+			return -1, true
+		} else if t.After(v) {
+			return 1, true
+		}
+		return 0, true
+	case []byte:
+		var b []byte
+		if json.Unmarshal(raw, &b) != nil {
+			return 0, false
+		}
+		return bytes.Compare(b, v), true
+	}
+	rv := reflect.ValueOf(val)
+	var f float64
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f = float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		f = float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		f = rv.Float()
+	default:
+		return 0, false
+	}
+	var sf float64
+	if json.Unmarshal(raw, &sf) != nil {
+		return 0, false
+	}
+	if sf < f {
+		return -1, true
+	} else if sf > f {
+		return 1, true
+	}
+	return 0, true
+}
+
+func matchLDBRow(r *ldbRow, conds []ldbCond) bool {
+	for _, c := range conds {
+		if c.op == "null" || c.op == "notnull" {
+			raw := r.cols[c.col]
+			null := raw == nil || string(raw) == "null"
+			if null != (c.op == "null") {
+				return false
+			}
+			continue
+		}
+		cmp, ok := compareLDBValue(r.cols[c.col], c.val)
+		if !ok {
+			return false
+		}
+		switch c.op {
+		case "=":
+			ok = cmp == 0
+		case "!=", "<>":
+			ok = cmp != 0
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *StorageLDB) getRow(table string, pk string) (*ldbRow, error) {
+	vb, err := s.h.Get(ldbRowKey(table, pk), nil)
+	if err != nil {
+		return nil, err
+	}
+	r := &ldbRow{pk: pk}
+	err = json.Unmarshal(vb, &r.cols)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// scan returns the rows matching the conditions, the primary key and the
+// secondary indexes are used when there is an equal condition on them
+func (s *StorageLDB) scan(table string, pkCol string, conds []ldbCond) ([]*ldbRow, error) {
+	rows := make([]*ldbRow, 0)
+	for _, c := range conds {
+		if c.op == "=" && pkCol != "" && c.col == pkCol {
+			r, err := s.getRow(table, fmt.Sprintf("%v", c.val))
+			if err == leveldb.ErrNotFound {
+				return rows, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			if matchLDBRow(r, conds) {
+				rows = append(rows, r)
+			}
+			return rows, nil
 		}
-		s.l.Unlock()
-		if !wait {
+	}
+	for _, c := range conds {
+		if c.op != "=" || !ldbIndexed(c.col) {
+			continue
+		}
+		raw, err := json.Marshal(c.val)
+		if err != nil {
 			break
 		}
-		time.Sleep(100 * time.Microsecond)
+		prefix := ldbIndexKey(table, c.col, raw, "")
+		iter := s.h.NewIterator(util.BytesPrefix(prefix), nil)
+		pks := make([]string, 0)
+		for iter.Next() {
+			pks = append(pks, string(iter.Key()[len(prefix):]))
+		}
+		iter.Release()
+		if err := iter.Error(); err != nil {
+			return nil, err
+		}
+		for _, pk := range pks {
+			r, err := s.getRow(table, pk)
+			if err != nil {
+				return nil, err
+			}
+			if matchLDBRow(r, conds) {
+				rows = append(rows, r)
+			}
+		}
+		return rows, nil
 	}
+	prefix := ldbRowKey(table, "")
+	iter := s.h.NewIterator(util.BytesPrefix(prefix), nil)
+	defer iter.Release()
+	for iter.Next() {
+		r := &ldbRow{pk: string(iter.Key()[len(prefix):])}
+		err := json.Unmarshal(iter.Value(), &r.cols)
+		if err != nil {
+			return nil, err
+		}
+		if matchLDBRow(r, conds) {
+			rows = append(rows, r)
+		}
+	}
+	return rows, iter.Error()
 }
 
-func (s *StorageLDB) releaseStorage(storageName string) {
-	s.l.Lock()
-	_, ok := s.sm[storageName]
-	if ok {
-		s.sm[storageName] = false
+func (s *StorageLDB) putRow(b *leveldb.Batch, table string, pk string, cols map[string]json.RawMessage, old *ldbRow) error {
+	if old != nil {
+		s.deleteRow(b, table, old)
+	}
+	vb, err := json.Marshal(cols)
+	if err != nil {
+		return err
+	}
+	b.Put(ldbRowKey(table, pk), vb)
+	for col, raw := range cols {
+		if ldbIndexed(col) {
+			b.Put(ldbIndexKey(table, col, raw, pk), nil)
+		}
 	}
-	s.l.Unlock()
+	return nil
 }
 
-// Write will write into storage
-func (s *StorageLDB) Write(storageName string, value interface{}) error {
-	v, ok := value.(*StorageType)
-	if !ok {
-		return fmt.Errorf("invalid data type")
+func (s *StorageLDB) deleteRow(b *leveldb.Batch, table string, r *ldbRow) {
+	b.Delete(ldbRowKey(table, r.pk))
+	for col, raw := range r.cols {
+		if ldbIndexed(col) {
+			b.Delete(ldbIndexKey(table, col, raw, r.pk))
+		}
+	}
+}
+
+// nextSeq generates the row key for the models without the primary key
+func (s *StorageLDB) nextSeq(b *leveldb.Batch, table string, count int) (uint64, error) {
+	key := []byte(ldbSeqPrefix + ldbSep + table)
+	seq := uint64(0)
+	vb, err := s.h.Get(key, nil)
+	if err == nil {
+		seq, err = strconv.ParseUint(string(vb), 10, 64)
+		if err != nil {
+			return 0, err
+		}
+	} else if err != leveldb.ErrNotFound {
+		return 0, err
 	}
-	s.getStorage(storageName)
-	defer s.releaseStorage(storageName)
-	db, err := leveldb.OpenFile(s.dirPath+storageName+".db", nil)
+	b.Put(key, []byte(strconv.FormatUint(seq+uint64(count), 10)))
+	return seq + 1, nil
+}
+
+func (s *StorageLDB) setPKColumn(table string, fields []ldbField) {
+	s.meta.l.Lock()
+	s.meta.pk[table] = ldbPKColumn(fields)
+	s.meta.l.Unlock()
+}
+
+// pkColumn returns the primary key column of the model, the registered
+// table is used when the model is not known
+func (s *StorageLDB) pkColumn(table string, value interface{}) string {
+	t, err := ldbModelType(value)
+	if err == nil {
+		return ldbPKColumn(ldbFields(t))
+	}
+	return s.getPKColumn(table)
+}
+
+func (s *StorageLDB) getPKColumn(table string) string {
+	s.meta.l.RLock()
+	defer s.meta.l.RUnlock()
+	return s.meta.pk[table]
+}
+
+// Init will initialize storage
+func (s *StorageLDB) Init(storageName string, value interface{}, force bool) error {
+	t, err := ldbModelType(value)
 	if err != nil {
 		return err
 	}
-	vb, err := json.Marshal(v.Value)
+	s.setPKColumn(storageName, ldbFields(t))
+	return nil
+}
+
+// Write will write into storage
+func (s *StorageLDB) Write(storageName string, value interface{}) error {
+	t, err := ldbModelType(value)
 	if err != nil {
-		db.Close()
 		return err
 	}
-	err = db.Put([]byte(v.Key), vb, nil)
-	db.Close()
-	return err
+	fields := ldbFields(t)
+	pkCol := ldbPKColumn(fields)
+	v := reflect.Indirect(reflect.ValueOf(value))
+	items := []reflect.Value{v}
+	if v.Kind() == reflect.Slice {
+		items = make([]reflect.Value, 0)
+		for i := 0; i < v.Len(); i++ {
+			items = append(items, reflect.Indirect(v.Index(i)))
+		}
+	}
+	s.lock()
+	defer s.unlock()
+	b := new(leveldb.Batch)
+	seq := uint64(0)
+	if pkCol == "" {
+		seq, err = s.nextSeq(b, storageName, len(items))
+		if err != nil {
+			return err
+		}
+	}
+	added := make(map[string]bool)
+	for _, item := range items {
+		pk, cols, err := encodeLDBRow(item, fields)
+		if err != nil {
+			return err
+		}
+		if pkCol == "" {
+			pk = fmt.Sprintf("%020d", seq)
+			seq++
+		} else {
+			_, err = s.h.Get(ldbRowKey(storageName, pk), nil)
+			if err == nil || added[pk] {
+				return fmt.Errorf("UNIQUE constraint failed: %s.%s", storageName, pkCol)
+			}
+			added[pk] = true
+		}
+		err = s.putRow(b, storageName, pk, cols, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return s.h.Write(b, nil)
+}
+
+// WriteBatch will write into storage
+func (s *StorageLDB) WriteBatch(storageName string, value interface{}, batchSize int) error {
+	return s.Write(storageName, value)
 }
 
-// Update will update the storage
+// Update will update the storage, the row is replaced by the primary key
+// similar to the DB save, models without the primary key update the matching rows
 func (s *StorageLDB) Update(storageName string, value interface{}, querryString string, querryVaule ...interface{}) error {
-	v, ok := value.(*StorageType)
-	if !ok {
-		return fmt.Errorf("invalid data type")
+	t, err := ldbModelType(value)
+	if err != nil {
+		return err
 	}
-	k, ok := querryVaule[0].(string)
-	if !ok {
-		return fmt.Errorf("invalid data type")
+	conds, err := parseLDBQuery(querryString, querryVaule)
+	if err != nil {
+		return err
 	}
-	v.Key = k
-	return s.Write(storageName, value)
+	fields := ldbFields(t)
+	pk, cols, err := encodeLDBRow(reflect.Indirect(reflect.ValueOf(value)), fields)
+	if err != nil {
+		return err
+	}
+	s.lock()
+	defer s.unlock()
+	b := new(leveldb.Batch)
+	if ldbPKColumn(fields) != "" {
+		old, err := s.getRow(storageName, pk)
+		if err != nil && err != leveldb.ErrNotFound {
+			return err
+		}
+		err = s.putRow(b, storageName, pk, cols, old)
+		if err != nil {
+			return err
+		}
+		return s.h.Write(b, nil)
+	}
+	rows, err := s.scan(storageName, "", conds)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		seq, err := s.nextSeq(b, storageName, 1)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, &ldbRow{pk: fmt.Sprintf("%020d", seq)})
+	}
+	for _, r := range rows {
+		err = s.putRow(b, storageName, r.pk, cols, r)
+		if err != nil {
+			return err
+		}
+	}
+	return s.h.Write(b, nil)
 }
 
 // Delete will delet the data from the storage
 func (s *StorageLDB) Delete(storageName string, value interface{}, querryString string, querryVaule ...interface{}) error {
-	k, ok := querryVaule[0].(string)
-	if !ok {
-		return fmt.Errorf("invalid data type")
+	conds, err := parseLDBQuery(querryString, querryVaule)
+	if err != nil {
+		return err
 	}
-	s.getStorage(storageName)
-	defer s.releaseStorage(storageName)
-	db, err := leveldb.OpenFile(s.dirPath+storageName+".db", nil)
+	s.lock()
+	defer s.unlock()
+	rows, err := s.scan(storageName, s.pkColumn(storageName, value), conds)
 	if err != nil {
 		return err
 	}
-	err = db.Delete([]byte(k), nil)
-	db.Close()
-	return err
+	b := new(leveldb.Batch)
+	for _, r := range rows {
+		s.deleteRow(b, storageName, r)
+	}
+	return s.h.Write(b, nil)
 }
 
-// Read will read from the storage
-func (s *StorageLDB) Read(storageName string, value interface{}, querryString string, querryVaule ...interface{}) error {
-	v, ok := value.(*StorageType)
-	if !ok {
-		return fmt.Errorf("invalid data type")
-	}
-	k, ok := querryVaule[0].(string)
-	if !ok {
-		return fmt.Errorf("invalid data type")
+func (s *StorageLDB) read(storageName string, value interface{}, offset int, limit int, querryString string, querryVaule ...interface{}) error {
+	t, err := ldbModelType(value)
+	if err != nil {
+		return err
 	}
-	s.getStorage(storageName)
-	defer s.releaseStorage(storageName)
-	db, err := leveldb.OpenFile(s.dirPath+storageName+".db", nil)
+	conds, err := parseLDBQuery(querryString, querryVaule)
 	if err != nil {
 		return err
 	}
-	vb, err := db.Get([]byte(k), nil)
-	db.Close()
+	fields := ldbFields(t)
+	rows, err := s.scan(storageName, ldbPKColumn(fields), conds)
 	if err != nil {
 		return err
 	}
-	v.Key = k
-	err = json.Unmarshal(vb, &v.Value)
-	return err
+	if offset > 0 {
+		if offset > len(rows) {
+			offset = len(rows)
+		}
+		rows = rows[offset:]
+	}
+	if limit >= 0 && limit < len(rows) {
+		rows = rows[:limit]
+	}
+	if len(rows) == 0 {
+		return fmt.Errorf("no records found")
+	}
+	v := reflect.ValueOf(value).Elem()
+	if v.Kind() != reflect.Slice {
+		return decodeLDBRow(rows[0].cols, v, fields)
+	}
+	ptr := v.Type().Elem().Kind() == reflect.Ptr
+	sv := reflect.MakeSlice(v.Type(), 0, len(rows))
+	for _, r := range rows {
+		item := reflect.New(t)
+		err = decodeLDBRow(r.cols, item.Elem(), fields)
+		if err != nil {
+			return err
+		}
+		if ptr {
+			sv = reflect.Append(sv, item)
+		} else {
+			sv = reflect.Append(sv, item.Elem())
+		}
+	}
+	v.Set(sv)
+	return nil
+}
+
+// Read will read from the storage
+func (s *StorageLDB) Read(storageName string, value interface{}, querryString string, querryVaule ...interface{}) error {
+	return s.read(storageName, value, 0, -1, querryString, querryVaule...)
+}
+
+// ReadWithOffset will read from the storage
+func (s *StorageLDB) ReadWithOffset(storageName string, offset int, limit int, value interface{}, querryString string, querryVaule ...interface{}) error {
+	return s.read(storageName, value, offset, limit, querryString, querryVaule...)
+}
+
+func (s *StorageLDB) GetDataCount(storageName string, querryString string, querryVaule ...interface{}) int64 {
+	conds, err := parseLDBQuery(querryString, querryVaule)
+	if err != nil {
+		return 0
+	}
+	rows, err := s.scan(storageName, s.getPKColumn(storageName), conds)
+	if err != nil {
+		return 0
+	}
+	return int64(len(rows))
 }
 
 // Close will close the stroage BD
 func (s *StorageLDB) Close() error {
+	if s.tr != nil {
+		return fmt.Errorf("storage in transaction, commit or rollback the transaction")
+	}
+	return s.db.Close()
+}
+
+// Begin will start the transaction, the other writers wait till it is finished
+func (s *StorageLDB) Begin() (Storage, error) {
+	if s.tr != nil {
+		return nil, fmt.Errorf("nested transaction is not supported")
+	}
+	s.meta.wl.Lock()
+	tr, err := s.db.OpenTransaction()
+	if err != nil {
+		s.meta.wl.Unlock()
+		return nil, err
+	}
+	return &StorageLDB{db: s.db, h: tr, tr: tr, meta: s.meta}, nil
+}
+
+// Commit will commit the transaction
+func (s *StorageLDB) Commit() error {
+	if s.tr == nil {
+		return fmt.Errorf("no transaction in progress")
+	}
+	err := s.tr.Commit()
+	if err != nil {
+		s.tr.Discard()
+	}
+	s.tr = nil
+	s.meta.wl.Unlock()
+	return err
+}
+
+// Rollback will discard the transaction
+func (s *StorageLDB) Rollback() error {
+	if s.tr == nil {
+		return fmt.Errorf("no transaction in progress")
+	}
+	s.tr.Discard()
+	s.tr = nil
+	s.meta.wl.Unlock()
 	return nil
 }
+
+// Backup will write the snapshot of the storage into the new LevelDB directory
+func (s *StorageLDB) Backup(path string) error {
+	if s.tr != nil {
+		return fmt.Errorf("storage in transaction, commit or rollback the transaction")
+	}
+	return CopyLDB(s.db, path)
+}
+
+// CopyLDB writes the consistent snapshot of the LevelDB into the directory
+func CopyLDB(db *leveldb.DB, path string) error {
+	sn, err := db.GetSnapshot()
+	if err != nil {
+		return err
+	}
+	defer sn.Release()
+	bdb, err := leveldb.OpenFile(path, &opt.Options{ErrorIfExist: true})
+	if err != nil {
+		return err
+	}
+	defer bdb.Close()
+	iter := sn.NewIterator(nil, nil)
+	defer iter.Release()
+	b := new(leveldb.Batch)
+	for iter.Next() {
+		b.Put(iter.Key(), iter.Value())
+		if b.Len() >= 1000 {
+			err = bdb.Write(b, nil)
+			if err != nil {
+				return err
+			}
+			b.Reset()
+		}
+	}
+	err = iter.Error()
+	if err != nil {
+		return err
+	}
+	return bdb.Write(b, nil)
+}
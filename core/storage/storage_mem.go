@@ -0,0 +1,17 @@
+package storage
+
+import (
+	"github.com/syndtr/goleveldb/leveldb"
+	lstorage "github.com/syndtr/goleveldb/leveldb/storage"
+)
+
+// NewStorageMemory creates the in-memory storage for the tests & the ephemeral
+// nodes, it has the same semantics as the LevelDB storage and nothing is
+// persisted once it is closed
+func NewStorageMemory() (*StorageLDB, error) {
+	db, err := leveldb.Open(lstorage.NewMemStorage(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return newStorageLDB(db), nil
+}
@@ -96,6 +96,125 @@ func TestBasic(t *testing.T) {
 	}
 }
 
+func TestTransaction(t *testing.T) {
+	s, err := NewStorageDB(&config.Config{DBAddress: "txtest.db", DBType: "Sqlite3"})
+	if err != nil {
+		t.Fatal("Failed to init DB", err.Error())
+	}
+	defer os.Remove("txtest.db")
+	defer s.Close()
+	if err := s.Init("user", &model{}, true); err != nil {
+		t.Fatal("Failed to initialize storage", err.Error())
+	}
+	tx, err := s.Begin()
+	if err != nil {
+		t.Fatal("Failed to begin transaction", err.Error())
+	}
+	if err := tx.Write("user", &model{Name: "TestUser1", Age: 20, Address: "Hyderabad"}); err != nil {
+		t.Fatal("Failed to write storage", err.Error())
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal("Failed to rollback transaction", err.Error())
+	}
+	var m model
+	if err := s.Read("user", &m, "Name=?", "TestUser1"); err == nil {
+		t.Fatal("Rolled back data is present in the storage")
+	}
+	tx, err = s.Begin()
+	if err != nil {
+		t.Fatal("Failed to begin transaction", err.Error())
+	}
+	if _, err := tx.Begin(); err == nil {
+		t.Fatal("Nested transaction should not be allowed")
+	}
+	if err := tx.Write("user", &model{Name: "TestUser2", Age: 30, Address: "Hyderabad"}); err != nil {
+		t.Fatal("Failed to write storage", err.Error())
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal("Failed to commit transaction", err.Error())
+	}
+	if err := s.Read("user", &m, "Name=?", "TestUser2"); err != nil {
+		t.Fatal("Committed data is missing in the storage", err.Error())
+	}
+}
+
+type ldbToken struct {
+	TokenID     string `gorm:"column:token_id;primaryKey"`
+	DID         string `gorm:"column:did"`
+	TokenStatus int    `gorm:"column:token_status"`
+	TokenValue  float64
+}
+
+func TestLDBStorage(t *testing.T) {
+	s, err := NewStorageLDB("ldbtest")
+	if err != nil {
+		t.Fatal("Failed to init LDB", err.Error())
+	}
+	defer os.RemoveAll("ldbtest")
+	defer s.Close()
+	if err := s.Init("token", &ldbToken{}, true); err != nil {
+		t.Fatal("Failed to initialize storage", err.Error())
+	}
+	for i := 0; i < 10; i++ {
+		tk := ldbToken{TokenID: fmt.Sprintf("token%d", i), DID: fmt.Sprintf("did%d", i%2), TokenStatus: i % 3, TokenValue: 1}
+		if err := s.Write("token", &tk); err != nil {
+			t.Fatal("Failed to write storage", err.Error())
+		}
+	}
+	if err := s.Write("token", &ldbToken{TokenID: "token1"}); err == nil {
+		t.Fatal("Duplicate primary key should not be allowed")
+	}
+	var tks []ldbToken
+	if err := s.Read("token", &tks, "did=? AND token_status=?", "did0", 0); err != nil {
+		t.Fatal("Failed to read storage", err.Error())
+	}
+	// token0 & token6
+	if len(tks) != 2 {
+		t.Fatal("Index read mismatch", len(tks))
+	}
+	if c := s.GetDataCount("token", "token_status!=?", 0); c != 6 {
+		t.Fatal("Count mismatch", c)
+	}
+	tk := tks[0]
+	tk.DID = "did1"
+	if err := s.Update("token", &tk, "token_id=?", tk.TokenID); err != nil {
+		t.Fatal("Failed to update storage", err.Error())
+	}
+	if c := s.GetDataCount("token", "did=?", "did0"); c != 4 {
+		t.Fatal("Stale index after update", c)
+	}
+	tx, err := s.Begin()
+	if err != nil {
+		t.Fatal("Failed to begin transaction", err.Error())
+	}
+	if err := tx.Delete("token", &ldbToken{}, "did=?", "did1"); err != nil {
+		t.Fatal("Failed to delete storage", err.Error())
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal("Failed to rollback transaction", err.Error())
+	}
+	if c := s.GetDataCount("token", "did=?", "did1"); c != 6 {
+		t.Fatal("Rolled back data is missing", c)
+	}
+	tx, err = s.Begin()
+	if err != nil {
+		t.Fatal("Failed to begin transaction", err.Error())
+	}
+	if err := tx.Delete("token", &ldbToken{}, "did=?", "did1"); err != nil {
+		t.Fatal("Failed to delete storage", err.Error())
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal("Failed to commit transaction", err.Error())
+	}
+	var rt ldbToken
+	if err := s.Read("token", &rt, "token_id=?", "token1"); err == nil {
+		t.Fatal("Deleted data is present in the storage")
+	}
+	if err := s.ReadWithOffset("token", 1, 2, &tks, "token_value>=?", 1); err != nil || len(tks) != 2 {
+		t.Fatal("Failed to read with offset")
+	}
+}
+
 func TestTemp(t *testing.T) {
 	ts := make([]int, 0)
 	ts = append(ts, 10)
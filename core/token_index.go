@@ -0,0 +1,96 @@
+package core
+
+import (
+	"sort"
+
+	"github.com/rubixchain/rubixgoplatform/core/model"
+	"github.com/rubixchain/rubixgoplatform/token"
+)
+
+const (
+	TokenIndexLogInterval int = 500000
+)
+
+func (c *Core) initTokenIndex() error {
+	var err error
+	c.ti, err = token.NewTokenIndex(c.cfg.DirPath + RubixRootDir + TokenIndexDir)
+	if err != nil {
+		return err
+	}
+	token.SetTokenIndex(c.ti)
+	return nil
+}
+
+func (c *Core) closeTokenIndex() {
+	if c.ti == nil {
+		return
+	}
+	token.SetTokenIndex(nil)
+	c.ti.Close()
+}
+
+// indexedLevels returns the token levels with all the numbers indexed
+func (c *Core) indexedLevels() []int {
+	ls := make([]int, 0)
+	for l, v := range token.TokenMap {
+		if v > 0 && c.ti.IsLevelIndexed(l) {
+			ls = append(ls, l)
+		}
+	}
+	sort.Ints(ls)
+	return ls
+}
+
+// buildTokenIndex builds the token number index of all the levels, token
+// validation uses the linear search for the levels not yet indexed
+func (c *Core) buildTokenIndex() {
+	c.lock.Lock()
+	if c.tiBuilding || c.ti == nil {
+		c.lock.Unlock()
+		return
+	}
+	c.tiBuilding = true
+	c.lock.Unlock()
+	defer func() {
+		c.lock.Lock()
+		c.tiBuilding = false
+		c.lock.Unlock()
+	}()
+	total := token.MaxTokenNumber()
+	if c.ti.Count() >= total {
+		return
+	}
+	c.log.Info("Building token index", "indexed", c.ti.Count(), "total", total)
+	levels := len(c.indexedLevels())
+	err := c.ti.BuildAll(func(count int, total int) {
+		if count%TokenIndexLogInterval == 0 || count == total {
+			c.log.Info("Token index build in progress", "indexed", count, "total", total)
+		}
+		if ls := c.indexedLevels(); len(ls) != levels {
+			levels = len(ls)
+			c.log.Debug("Token index levels done", "levels", levels)
+		}
+	})
+	if err != nil {
+		c.log.Error("Failed to build token index", "err", err)
+		return
+	}
+	c.log.Info("Token index build done", "indexed", c.ti.Count())
+}
+
+// GetTokenIndexStatus returns the build status of the token index
+func (c *Core) GetTokenIndexStatus() model.TokenIndexStatus {
+	c.lock.Lock()
+	building := c.tiBuilding
+	c.lock.Unlock()
+	ts := model.TokenIndexStatus{
+		Total:         token.MaxTokenNumber(),
+		Building:      building,
+		IndexedLevels: make([]int, 0),
+	}
+	if c.ti != nil {
+		ts.Indexed = c.ti.Count()
+		ts.IndexedLevels = c.indexedLevels()
+	}
+	return ts
+}
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -12,7 +13,7 @@ import (
 )
 
 func (c *Core) InitiateRBTTransfer(reqID string, req *model.RBTTransferRequest) {
-	br := c.initiateRBTTransfer(reqID, req)
+	br := c.initiateRBTTransfer(c.webReqContext(reqID), reqID, req)
 	dc := c.GetWebReq(reqID)
 	if dc == nil {
 		c.log.Error("Failed to get did channels")
@@ -21,7 +22,7 @@ func (c *Core) InitiateRBTTransfer(reqID string, req *model.RBTTransferRequest)
 	dc.OutChan <- br
 }
 
-func (c *Core) initiateRBTTransfer(reqID string, req *model.RBTTransferRequest) *model.BasicResponse {
+func (c *Core) initiateRBTTransfer(ctx context.Context, reqID string, req *model.RBTTransferRequest) *model.BasicResponse {
 	st := time.Now()
 	resp := &model.BasicResponse{
 		Status: false,
@@ -51,8 +52,14 @@ func (c *Core) initiateRBTTransfer(reqID string, req *model.RBTTransferRequest)
 		resp.Message = "Insufficient tokens or tokens are locked"
 		return resp
 	}
-	// release the locked tokens before exit
-	defer c.w.ReleaseTokens(wt)
+	// release the locked tokens before exit, the tokens of the failed transfer
+	// pledged by the quorums are kept locked for the reconciler
+	release := true
+	defer func() {
+		if release {
+			c.w.ReleaseTokens(wt)
+		}
+	}()
 
 	for i := range wt {
 		c.w.Pin(wt[i].TokenID, wallet.OwnerRole, did)
@@ -123,17 +130,28 @@ func (c *Core) initiateRBTTransfer(reqID string, req *model.RBTTransferRequest)
 		ReceiverPeerID: rpeerid,
 		ContractBlock:  sc.GetBlock(),
 	}
-	td, _, err := c.initiateConsensus(cr, sc, dc)
+	if ctx.Err() != nil {
+		c.log.Error("Transfer cancelled before consensus", "err", ctx.Err())
+		resp.Message = "Transfer cancelled, " + ctx.Err().Error()
+		return resp
+	}
+	err = c.startTransferJournal(cr.ReqID, sc, rpeerid, req.TokenCount)
+	if err != nil {
+		c.log.Error("Failed to write transfer journal", "err", err)
+		resp.Message = "Failed to write transfer journal, " + err.Error()
+		return resp
+	}
+	td, _, err := c.initiateConsensus(ctx, cr, sc, dc)
 	if err != nil {
+		release = !c.finishTransferJournal(cr.ReqID, false)
 		c.log.Error("Consensus failed", "err", err)
 		resp.Message = "Consensus failed" + err.Error()
 		return resp
 	}
+	// transaction history is recorded along with the token status by the consensus
+	c.finishTransferJournal(cr.ReqID, true)
 	et := time.Now()
 	dif := et.Sub(st)
-	td.Amount = req.TokenCount
-	td.TotalTime = float64(dif.Milliseconds())
-	c.w.AddTransactionHistory(td)
 	etrans := &ExplorerTrans{
 		TID:         td.TransactionID,
 		SenderDID:   did,
@@ -0,0 +1,313 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rubixchain/rubixgoplatform/block"
+	"github.com/rubixchain/rubixgoplatform/contract"
+	"github.com/rubixchain/rubixgoplatform/core/wallet"
+)
+
+const (
+	TransferJournalStorage string = "transferjournal"
+)
+
+// Transfer journal phases, each phase is recorded before moving to the next
+// step. TransferPledging is recorded with the transfer block before the first
+// quorum is asked to pledge, quorums may hold the block from that phase on, so
+// the tokens are never released automatically from it.
+const (
+	TransferStarted int = iota + 1
+	TransferPledging
+	TransferPledged
+	TransferSending
+	TransferSent
+	TransferCommitted
+)
+
+// TransferJournal is the write ahead record of the in-flight RBT transfer
+type TransferJournal struct {
+	ReqID           string    `gorm:"column:req_id;primaryKey" json:"req_id"`
+	TransactionID   string    `gorm:"column:transaction_id" json:"transaction_id"`
+	SenderDID       string    `gorm:"column:sender_did" json:"sender_did"`
+	ReceiverDID     string    `gorm:"column:receiver_did" json:"receiver_did"`
+	ReceiverPeerID  string    `gorm:"column:receiver_peer_id" json:"receiver_peer_id"`
+	Amount          float64   `gorm:"column:amount" json:"amount"`
+	Comment         string    `gorm:"column:comment" json:"comment"`
+	TokenInfo       string    `gorm:"column:token_info" json:"token_info"`
+	Phase           int       `gorm:"column:phase" json:"phase"`
+	Local           bool      `gorm:"column:local" json:"local"`
+	TokenChainBlock []byte    `gorm:"column:token_chain_block" json:"token_chain_block"`
+	CreationTime    time.Time `gorm:"column:creation_time" json:"creation_time"`
+	UpdateTime      time.Time `gorm:"column:update_time" json:"update_time"`
+}
+
+func (c *Core) initTransferJournal() error {
+	return c.s.Init(TransferJournalStorage, &TransferJournal{}, true)
+}
+
+func (c *Core) getTransferJournal(reqID string) *TransferJournal {
+	var tj TransferJournal
+	err := c.s.Read(TransferJournalStorage, &tj, "req_id=?", reqID)
+	if err != nil || tj.ReqID == "" {
+		return nil
+	}
+	return &tj
+}
+
+// startTransferJournal records the transfer before the consensus is initiated
+func (c *Core) startTransferJournal(reqID string, sc *contract.Contract, receiverPeerID string, amount float64) error {
+	jb, err := json.Marshal(sc.GetTransTokenInfo())
+	if err != nil {
+		return err
+	}
+	tj := TransferJournal{
+		ReqID:          reqID,
+		SenderDID:      sc.GetSenderDID(),
+		ReceiverDID:    sc.GetReceiverDID(),
+		ReceiverPeerID: receiverPeerID,
+		Amount:         amount,
+		Comment:        sc.GetComment(),
+		TokenInfo:      string(jb),
+		Phase:          TransferStarted,
+		CreationTime:   time.Now(),
+		UpdateTime:     time.Now(),
+	}
+	return c.s.Write(TransferJournalStorage, &tj)
+}
+
+// updateTransferJournal moves the transfer to the next phase, transfers
+// without a journal (i.e. other consensus modes) are ignored
+func (c *Core) updateTransferJournal(reqID string, phase int, tid string, nb *block.Block, local bool) error {
+	tj := c.getTransferJournal(reqID)
+	if tj == nil {
+		return nil
+	}
+	tj.Phase = phase
+	tj.UpdateTime = time.Now()
+	if tid != "" {
+		tj.TransactionID = tid
+	}
+	if nb != nil {
+		tj.TokenChainBlock = nb.GetBlock()
+	}
+	tj.Local = local
+	err := c.s.Update(TransferJournalStorage, tj, "req_id=?", reqID)
+	if err != nil {
+		c.log.Error("Failed to update transfer journal", "req_id", reqID, "phase", phase, "err", err)
+	}
+	return err
+}
+
+// finishTransferJournal removes the journal once the transfer is settled, a
+// failed transfer which is already pledged by the quorums is left for the
+// reconciler and true is returned, the tokens must stay locked
+func (c *Core) finishTransferJournal(reqID string, success bool) bool {
+	tj := c.getTransferJournal(reqID)
+	if tj == nil {
+		return false
+	}
+	if !success && tj.Phase >= TransferPledging {
+		c.log.Error("Transfer is pledged by the quorums but failed to complete, it will be reconciled on restart", "req_id", reqID, "tid", tj.TransactionID, "phase", tj.Phase)
+		return true
+	}
+	c.removeTransferJournal(reqID)
+	return false
+}
+
+func (c *Core) removeTransferJournal(reqID string) {
+	err := c.s.Delete(TransferJournalStorage, &TransferJournal{}, "req_id=?", reqID)
+	if err != nil {
+		c.log.Error("Failed to remove transfer journal", "req_id", reqID, "err", err)
+	}
+}
+
+// reconcileTransfers completes or rolls back the transfers left unfinished by
+// an unplanned shutdown. Transfers which are not pledged are rolled back as
+// the token chain is not changed, the transfers accepted by the receiver are
+// completed locally. The pledging and pledged transfers are completed if the
+// receiver has the transfer block, otherwise they are left for the manual
+// resolution.
+func (c *Core) reconcileTransfers() {
+	var tjs []TransferJournal
+	err := c.s.Read(TransferJournalStorage, &tjs, "req_id!=?", "")
+	if err != nil || len(tjs) == 0 {
+		return
+	}
+	c.log.Info("Reconciling unfinished transfers", "count", len(tjs))
+	for i := range tjs {
+		tj := &tjs[i]
+		var ti []contract.TokenInfo
+		err := json.Unmarshal([]byte(tj.TokenInfo), &ti)
+		if err != nil {
+			c.log.Error("Invalid transfer journal, failed to parse token info", "req_id", tj.ReqID, "err", err)
+			continue
+		}
+		var nb *block.Block
+		if tj.TokenChainBlock != nil {
+			nb = block.InitBlock(tj.TokenChainBlock, nil)
+		}
+		switch {
+		case tj.Phase < TransferPledging:
+			c.rollbackTransfer(tj, ti, nb)
+		case nb == nil:
+			c.log.Error("Invalid transfer journal, transfer block is missing, resolve it manually", "req_id", tj.ReqID, "tid", tj.TransactionID)
+			continue
+		case tj.Phase < TransferSent:
+			// receiver is asked in the background, the tokens stay locked
+			go c.resolveTransfer(tj, ti, nb)
+			continue
+		default:
+			err = c.completeTransfer(tj, ti, nb)
+			if err != nil {
+				c.log.Error("Failed to complete the transfer", "req_id", tj.ReqID, "err", err)
+				continue
+			}
+		}
+		c.removeTransferJournal(tj.ReqID)
+	}
+}
+
+// receiverHasBlock checks whether the receiver token chain has the transfer
+// block, the chain is read from the block previous to the transfer block
+func (c *Core) receiverHasBlock(tj *TransferJournal, ti []contract.TokenInfo, nb *block.Block) (bool, error) {
+	if len(ti) == 0 {
+		return false, fmt.Errorf("no tokens in the transfer")
+	}
+	t := ti[0].Token
+	nbid, err := nb.GetBlockID(t)
+	if err != nil {
+		return false, err
+	}
+	pbid, err := nb.GetPrevBlockID(t)
+	if err != nil {
+		return false, err
+	}
+	p, err := c.getPeer(tj.ReceiverPeerID + "." + tj.ReceiverDID)
+	if err != nil {
+		return false, err
+	}
+	defer p.Close()
+	tr := TCBSyncRequest{
+		Token:     t,
+		TokenType: ti[0].TokenType,
+		BlockID:   pbid,
+	}
+	var trep TCBSyncReply
+	err = p.SendJSONRequest("POST", APISyncTokenChain, nil, &tr, &trep, false)
+	if err != nil {
+		return false, err
+	}
+	// receiver does not have the previous block, so it does not have the transfer block
+	if !trep.Status {
+		return false, nil
+	}
+	for _, bb := range trep.TCBlock {
+		b := block.InitBlock(bb, nil)
+		if b == nil {
+			continue
+		}
+		bid, err := b.GetBlockID(t)
+		if err == nil && bid == nbid {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// resolveTransfer completes the pledged transfer if the receiver has the
+// transfer block. The quorums hold the block, so the tokens are not released
+// even if the receiver does not have it, such transfers need the manual
+// resolution.
+func (c *Core) resolveTransfer(tj *TransferJournal, ti []contract.TokenInfo, nb *block.Block) {
+	ok, err := c.receiverHasBlock(tj, ti, nb)
+	if err != nil {
+		c.log.Error("Failed to check the transfer block with the receiver, it will be reconciled on restart", "req_id", tj.ReqID, "err", err)
+		return
+	}
+	if !ok {
+		c.log.Error("Pledged transfer did not reach the receiver, tokens are kept locked, resolve it manually", "req_id", tj.ReqID, "tid", tj.TransactionID, "phase", tj.Phase)
+		return
+	}
+	err = c.completeTransfer(tj, ti, nb)
+	if err != nil {
+		c.log.Error("Failed to complete the transfer", "req_id", tj.ReqID, "err", err)
+		return
+	}
+	c.removeTransferJournal(tj.ReqID)
+}
+
+// isBlockApplied checks whether the token chain already has the transfer block
+func (c *Core) isBlockApplied(ti []contract.TokenInfo, nb *block.Block) bool {
+	if nb == nil || len(ti) == 0 {
+		return false
+	}
+	bid, err := nb.GetBlockID(ti[0].Token)
+	if err != nil {
+		return false
+	}
+	lb := c.w.GetLatestTokenBlock(ti[0].Token, ti[0].TokenType)
+	if lb == nil {
+		return false
+	}
+	lbid, err := lb.GetBlockID(ti[0].Token)
+	if err != nil {
+		return false
+	}
+	return lbid == bid
+}
+
+func (c *Core) rollbackTransfer(tj *TransferJournal, ti []contract.TokenInfo, nb *block.Block) {
+	if c.isBlockApplied(ti, nb) {
+		// token chain moved on, the tokens can not be released
+		c.log.Error("Transfer block already applied, unable to roll back", "req_id", tj.ReqID, "tid", tj.TransactionID)
+		return
+	}
+	wt := make([]wallet.Token, 0)
+	for _, t := range ti {
+		wt = append(wt, wallet.Token{TokenID: t.Token})
+	}
+	err := c.w.ReleaseTokens(wt)
+	if err != nil {
+		c.log.Error("Failed to release the tokens", "req_id", tj.ReqID, "err", err)
+		return
+	}
+	c.log.Info("Transfer rolled back", "req_id", tj.ReqID, "phase", tj.Phase)
+}
+
+func (c *Core) completeTransfer(tj *TransferJournal, ti []contract.TokenInfo, nb *block.Block) error {
+	// token status & history are committed together, history means the transfer is done
+	_, err := c.w.GetTransactionDetailsbyTransactionId(tj.TransactionID)
+	if tj.Phase < TransferCommitted && err != nil {
+		bid, err := nb.GetBlockID(ti[0].Token)
+		if err != nil {
+			return err
+		}
+		td := wallet.TransactionDetails{
+			TransactionID:   tj.TransactionID,
+			TransactionType: nb.GetTransType(),
+			BlockID:         bid,
+			Mode:            wallet.SendMode,
+			SenderDID:       tj.SenderDID,
+			ReceiverDID:     tj.ReceiverDID,
+			Comment:         tj.Comment,
+			DateTime:        tj.UpdateTime,
+			Status:          true,
+			Amount:          tj.Amount,
+		}
+		err = c.w.TokensTransferred(tj.SenderDID, ti, nb, tj.Local, &td)
+		if err != nil {
+			return err
+		}
+	} else if !tj.Local {
+		// the DB is committed before the token chain, write the block if it is missing
+		err = c.w.EnsureTokenBlock(nb)
+		if err != nil {
+			return err
+		}
+	}
+	c.log.Info("Transfer completed", "req_id", tj.ReqID, "tid", tj.TransactionID)
+	return nil
+}
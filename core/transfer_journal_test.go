@@ -0,0 +1,71 @@
+package core
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/rubixchain/rubixgoplatform/contract"
+	"github.com/rubixchain/rubixgoplatform/core/storage"
+	"github.com/rubixchain/rubixgoplatform/core/wallet"
+	"github.com/rubixchain/rubixgoplatform/wrapper/logger"
+)
+
+func TestTransferJournal(t *testing.T) {
+	log := logger.New(&logger.LoggerOptions{Name: "test", Color: []logger.ColorOption{logger.ColorOff}})
+	s, err := storage.NewStorageMemory()
+	if err != nil {
+		t.Fatal("Failed to create memory storage", err)
+	}
+	defer s.Close()
+	w, err := wallet.InitMemoryWallet(s, log)
+	if err != nil {
+		t.Fatal("Failed to init memory wallet", err)
+	}
+	c := &Core{s: s, w: w, log: log}
+	err = c.initTransferJournal()
+	if err != nil {
+		t.Fatal("Failed to init transfer journal", err)
+	}
+	for _, tk := range []string{"token1", "token2"} {
+		err = w.CreateToken(&wallet.Token{TokenID: tk, DID: "did", TokenValue: 1, TokenStatus: wallet.TokenIsLocked})
+		if err != nil {
+			t.Fatal("Failed to create token", err)
+		}
+	}
+	addJournal := func(reqID string, token string, phase int) {
+		ti, _ := json.Marshal([]contract.TokenInfo{{Token: token, TokenType: 0}})
+		tj := TransferJournal{ReqID: reqID, TokenInfo: string(ti), Phase: phase, CreationTime: time.Now(), UpdateTime: time.Now()}
+		err := s.Write(TransferJournalStorage, &tj)
+		if err != nil {
+			t.Fatal("Failed to write transfer journal", err)
+		}
+	}
+	// failed transfer pledged by the quorums keeps the tokens locked
+	addJournal("req1", "token1", TransferSending)
+	if !c.finishTransferJournal("req1", false) || c.getTransferJournal("req1") == nil {
+		t.Fatal("Pledged transfer journal is removed")
+	}
+	addJournal("req3", "token1", TransferPledging)
+	if !c.finishTransferJournal("req3", false) || c.getTransferJournal("req3") == nil {
+		t.Fatal("Pledging transfer journal is removed")
+	}
+	c.removeTransferJournal("req3")
+	addJournal("req2", "token2", TransferStarted)
+	if c.finishTransferJournal("req2", false) || c.getTransferJournal("req2") != nil {
+		t.Fatal("Unpledged transfer journal is not removed")
+	}
+	// unpledged transfer is rolled back, pledging one without the block is kept
+	addJournal("req2", "token2", TransferStarted)
+	c.removeTransferJournal("req1")
+	addJournal("req1", "token1", TransferPledging)
+	c.reconcileTransfers()
+	tk, err := w.ReadToken("token2")
+	if err != nil || tk.TokenStatus != wallet.TokenIsFree || c.getTransferJournal("req2") != nil {
+		t.Fatal("Unpledged transfer is not rolled back", err)
+	}
+	tk, err = w.ReadToken("token1")
+	if err != nil || tk.TokenStatus != wallet.TokenIsLocked || c.getTransferJournal("req1") == nil {
+		t.Fatal("Pledging transfer is rolled back", err)
+	}
+}
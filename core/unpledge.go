@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/rubixchain/rubixgoplatform/block"
+	"github.com/rubixchain/rubixgoplatform/core/model"
+	"github.com/rubixchain/rubixgoplatform/core/unpledge"
 	"github.com/rubixchain/rubixgoplatform/token"
 )
 
@@ -78,3 +80,100 @@ func (c *Core) Unpledge(t string, file string) error {
 	}
 	return nil
 }
+
+var unpledgeStatusString = map[int]string{
+	unpledge.UnpledgeQueued:       "queued",
+	unpledge.UnpledgeWorking:      "working",
+	unpledge.UnpledgeProofWritten: "proof-written",
+	unpledge.UnpledgeCallbackDone: "callback-done",
+	unpledge.UnpledgeFailed:       "failed",
+	unpledge.UnpledgeCancelled:    "cancelled",
+}
+
+// GetUnpledgeStatus returns the status of the unpledge queue
+func (c *Core) GetUnpledgeStatus() model.UnpledgeQueueStatus {
+	qs := c.up.GetStatus()
+	ms := model.UnpledgeQueueStatus{
+		Workers:  qs.Workers,
+		HashRate: qs.HashRate,
+		ETA:      int64(qs.ETA.Seconds()),
+		Tokens:   make([]model.UnpledgeTokenStatus, 0),
+	}
+	for _, ts := range qs.Tokens {
+		switch ts.Status {
+		case unpledge.UnpledgeQueued:
+			ms.Queued++
+		case unpledge.UnpledgeWorking, unpledge.UnpledgeProofWritten:
+			ms.Working++
+		case unpledge.UnpledgeFailed:
+			ms.Failed++
+		}
+		ms.Tokens = append(ms.Tokens, model.UnpledgeTokenStatus{
+			Token:        ts.Token,
+			Status:       unpledgeStatusString[ts.Status],
+			Reason:       ts.Reason,
+			Attempts:     ts.Attempts,
+			Hashes:       ts.Hashes,
+			CreationTime: ts.CreationTime,
+			UpdateTime:   ts.UpdateTime,
+		})
+	}
+	return ms
+}
+
+// RetryUnpledge queues the failed or cancelled token unpledge again
+func (c *Core) RetryUnpledge(t string) error {
+	return c.up.RetryUnpledge(t)
+}
+
+// CancelUnpledge cancels the pending token unpledge
+func (c *Core) CancelUnpledge(t string) error {
+	return c.up.CancelUnpledge(t)
+}
+
+// VerifyUnpledgeProof verifies the proof of work of the unpledged token, the
+// token chain must be available in the node. Proof is fetched from IPFS using
+// the unpledge id of the latest block if it is not provided.
+func (c *Core) VerifyUnpledgeProof(t string, proof []byte) (*model.UnpledgeProofResult, error) {
+	tokenType := token.RBTTokenType
+	if c.testNet {
+		tokenType = token.TestTokenType
+	}
+	b := c.w.GetLatestTokenBlock(t, tokenType)
+	if b == nil {
+		return nil, fmt.Errorf("token chain is not found")
+	}
+	if b.GetTransType() != block.TokenUnpledgedType {
+		return nil, fmt.Errorf("token is not unpledged")
+	}
+	pr := &model.UnpledgeProofResult{
+		Token: t,
+	}
+	if len(proof) == 0 {
+		pr.ProofID = b.GetUnpledgeId(t)
+		if pr.ProofID == "" {
+			return nil, fmt.Errorf("unpledge id is missing in the token chain")
+		}
+		var err error
+		proof, err = c.getFromIPFS(pr.ProofID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch the proof, %s", err.Error())
+		}
+	}
+	pr.Compact = unpledge.IsCompactProof(proof)
+	pcs, err := unpledge.ParseProof(proof)
+	if err != nil {
+		pr.Reason = err.Error()
+		return pr, nil
+	}
+	pr.Records = len(pcs) - 1
+	rdid, tid, err := c.getProofverificationDetails(t, tokenType, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the pledge details, %s", err.Error())
+	}
+	pr.Valid, err = c.up.ProofVerification(t, tokenType, pcs, rdid, tid)
+	if err != nil {
+		pr.Reason = err.Error()
+	}
+	return pr, nil
+}
@@ -0,0 +1,109 @@
+package unpledge
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fxamacker/cbor"
+	"github.com/rubixchain/rubixgoplatform/util"
+)
+
+const (
+	ProofVersion  int = 1
+	ProofHashSize int = 32
+)
+
+// Proof is the compact encoding of the proof file, the hashes are stored as
+// raw bytes. The first hash is the seed hash followed by the hashes recorded
+// every RecordInterval and the final hash.
+type Proof struct {
+	Version    int      `cbor:"v"`
+	Difficulty int      `cbor:"d"`
+	Hashes     [][]byte `cbor:"h"`
+}
+
+// EncodeProof encodes the proof lines in the compact format
+func EncodeProof(lines []string) ([]byte, error) {
+	if len(lines) < 2 {
+		return nil, fmt.Errorf("invalid proof, too few lines")
+	}
+	dl, err := strconv.Atoi(lines[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid proof difficulty level")
+	}
+	p := Proof{
+		Version:    ProofVersion,
+		Difficulty: dl,
+		Hashes:     make([][]byte, 0, len(lines)-1),
+	}
+	for _, l := range lines[1:] {
+		h, err := hex.DecodeString(l)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proof hash, %s", err.Error())
+		}
+		p.Hashes = append(p.Hashes, h)
+	}
+	return cbor.Marshal(p, cbor.CanonicalEncOptions())
+}
+
+// IsCompactProof checks for the CBOR map header, the text proof starts with
+// the difficulty level digits
+func IsCompactProof(data []byte) bool {
+	return len(data) > 0 && data[0]>>5 == 5
+}
+
+// ParseProof reads the proof in either the text or the compact format and
+// returns the proof lines, the first line must be the difficulty level and
+// every other line must be the hex encoded 32 byte hash
+func ParseProof(data []byte) ([]string, error) {
+	if !IsCompactProof(data) {
+		lines := util.BytesToString(data)
+		if len(lines) < 2 {
+			return nil, fmt.Errorf("invalid proof, too few lines")
+		}
+		if _, err := strconv.Atoi(lines[0]); err != nil {
+			return nil, fmt.Errorf("invalid proof difficulty level")
+		}
+		for i, l := range lines[1:] {
+			h, err := hex.DecodeString(l)
+			if err != nil || len(h) != ProofHashSize {
+				return nil, fmt.Errorf("invalid proof hash at line %d", i+2)
+			}
+		}
+		return lines, nil
+	}
+	var p Proof
+	err := cbor.Unmarshal(data, &p)
+	if err != nil {
+		return nil, fmt.Errorf("invalid compact proof, %s", err.Error())
+	}
+	if p.Version != ProofVersion {
+		return nil, fmt.Errorf("unsupported proof version %d", p.Version)
+	}
+	if len(p.Hashes) == 0 {
+		return nil, fmt.Errorf("invalid proof, no hashes")
+	}
+	lines := make([]string, 0, len(p.Hashes)+1)
+	lines = append(lines, strconv.Itoa(p.Difficulty))
+	for i, h := range p.Hashes {
+		if len(h) != ProofHashSize {
+			return nil, fmt.Errorf("invalid proof hash at index %d", i)
+		}
+		lines = append(lines, hex.EncodeToString(h))
+	}
+	return lines, nil
+}
+
+func writeProofFile(fileName string, lines []string, compact bool) error {
+	if !compact {
+		return os.WriteFile(fileName, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+	}
+	pb, err := EncodeProof(lines)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(fileName, pb, 0644)
+}
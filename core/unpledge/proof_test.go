@@ -0,0 +1,55 @@
+package unpledge
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestProofFormat(t *testing.T) {
+	hash := sha2Hash256("token")
+	lines := []string{strconv.Itoa(Difficultlevel), hash}
+	for i := 0; i < 20; i++ {
+		hash = sha3Hash256Loop(hash)
+		lines = append(lines, hash)
+	}
+	cb, err := EncodeProof(lines)
+	if err != nil {
+		t.Fatal("Failed to encode proof", err)
+	}
+	tb := []byte(strings.Join(lines, "\n") + "\n")
+	if len(cb) >= len(tb) {
+		t.Fatal("Compact proof is not smaller than the text proof")
+	}
+	for _, b := range [][]byte{cb, tb} {
+		pl, err := ParseProof(b)
+		if err != nil {
+			t.Fatal("Failed to parse proof", err)
+		}
+		if strings.Join(pl, ",") != strings.Join(lines, ",") {
+			t.Fatal("Proof lines mismatch")
+		}
+	}
+	if !IsCompactProof(cb) || IsCompactProof(tb) {
+		t.Fatal("Failed to detect the proof format")
+	}
+	_, err = ParseProof([]byte("6\n"))
+	if err == nil {
+		t.Fatal("Short proof should fail")
+	}
+	// hash lines shorter than the hash or not hex encoded
+	for _, l := range []string{"abc", hash[:len(hash)-2], strings.Repeat("x", len(hash))} {
+		_, err = ParseProof([]byte(strings.Join(append(lines[:len(lines)-1:len(lines)-1], l), "\n") + "\n"))
+		if err == nil {
+			t.Fatal("Invalid hash line is accepted", l)
+		}
+	}
+	sb, err := EncodeProof(append(lines[:len(lines)-1:len(lines)-1], "abcd"))
+	if err != nil {
+		t.Fatal("Failed to encode proof", err)
+	}
+	_, err = ParseProof(sb)
+	if err == nil {
+		t.Fatal("Short compact hash is accepted")
+	}
+}
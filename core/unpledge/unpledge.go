@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/rubixchain/rubixgoplatform/block"
@@ -23,61 +24,136 @@ import (
 const (
 	RecordInterval int = 5000
 	Difficultlevel int = 6
+	DefaultWorkers int = 1
 )
 
 const (
 	UnpledgeQueueTable string = "unpledgequeue"
+	UnpledgeStateTable string = "unpledgestate"
 )
 
+// Unpledge states of the token
+const (
+	UnpledgeQueued int = iota + 1
+	UnpledgeWorking
+	UnpledgeProofWritten
+	UnpledgeCallbackDone
+	UnpledgeFailed
+	UnpledgeCancelled
+)
+
+var errCancelled = errors.New("unpledge cancelled")
+
 type UnPledge struct {
-	s       storage.Storage
-	testNet bool
-	w       *wallet.Wallet
-	l       sync.Mutex
-	running bool
-	dir     string
-	cb      UnpledgeCBType
-	log     logger.Logger
+	s        storage.Storage
+	testNet  bool
+	w        *wallet.Wallet
+	l        sync.Mutex
+	workers  int
+	running  int
+	progress map[string]*tokenProgress
+	rate     float64
+	compact  bool
+	dir      string
+	cb       UnpledgeCBType
+	log      logger.Logger
 }
 
+// UnpledgeTokenList is the queue used by the earlier versions, it is moved
+// to the unpledge state on the init
 type UnpledgeTokenList struct {
 	Token string `gorm:"column:token"`
 }
 
+// UnpledgeState is the persisted unpledge state of the token
+type UnpledgeState struct {
+	Token        string    `gorm:"column:token;primaryKey"`
+	Status       int       `gorm:"column:status"`
+	Reason       string    `gorm:"column:reason"`
+	Attempts     int       `gorm:"column:attempts"`
+	CreationTime time.Time `gorm:"column:creation_time"`
+	UpdateTime   time.Time `gorm:"column:update_time"`
+}
+
+// UnpledgeStatus is the unpledge state with the proof of work progress
+type UnpledgeStatus struct {
+	UnpledgeState
+	Hashes int64
+}
+
+// QueueStatus is the status of the unpledge queue, ETA is zero if it can not
+// be estimated yet
+type QueueStatus struct {
+	Workers  int
+	HashRate float64
+	ETA      time.Duration
+	Tokens   []UnpledgeStatus
+}
+
+type tokenProgress struct {
+	hashes int64
+	cancel int32
+	start  time.Time
+}
+
 type UnpledgeCBType func(t string, file string) error
 
-func InitUnPledge(s storage.Storage, w *wallet.Wallet, testNet bool, dir string, cb UnpledgeCBType, log logger.Logger) (*UnPledge, error) {
+func InitUnPledge(s storage.Storage, w *wallet.Wallet, testNet bool, dir string, workers int, cb UnpledgeCBType, log logger.Logger) (*UnPledge, error) {
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
 	up := &UnPledge{
-		s:       s,
-		testNet: testNet,
-		w:       w,
-		dir:     dir,
-		cb:      cb,
-		log:     log.Named("unpledge"),
+		s:        s,
+		testNet:  testNet,
+		w:        w,
+		workers:  workers,
+		progress: make(map[string]*tokenProgress),
+		dir:      dir,
+		cb:       cb,
+		log:      log.Named("unpledge"),
 	}
 	err := up.s.Init(UnpledgeQueueTable, UnpledgeTokenList{}, true)
 	if err != nil {
 		up.log.Error("failed to init unpledge token list table", "err", err)
 		return nil, err
 	}
+	err = up.s.Init(UnpledgeStateTable, &UnpledgeState{}, true)
+	if err != nil {
+		up.log.Error("failed to init unpledge state table", "err", err)
+		return nil, err
+	}
 	var list []UnpledgeTokenList
 	err = up.s.Read(UnpledgeQueueTable, &list, "token != ?", "")
-	if err != nil {
-		tks, err := up.w.GetAllPledgedTokens()
-		if err == nil {
-			list = make([]UnpledgeTokenList, 0)
-			for i := range tks {
-				l := UnpledgeTokenList{
-					Token: tks[i].TokenID,
-				}
-				list = append(list, l)
+	if err == nil {
+		for i := range list {
+			err = up.queueToken(list[i].Token)
+			if err != nil {
+				up.log.Error("Failed to move unpledge list", "err", err)
+				return nil, err
 			}
-			for i := range list {
-				err = up.s.Write(UnpledgeQueueTable, &list[i])
-				if err != nil {
-					up.log.Error("Failed to write unpledge list", "err", err)
-					return nil, err
-				}
+			up.s.Delete(UnpledgeQueueTable, &UnpledgeTokenList{}, "token=?", list[i].Token)
+		}
+	}
+	// proof of work of the interrupted tokens is restarted, the tokens with
+	// the proof written are resumed at the callback by the workers
+	for _, st := range up.getStates("status=?", UnpledgeWorking) {
+		st.Status = UnpledgeQueued
+		err = up.saveState(&st)
+		if err != nil {
+			up.log.Error("Failed to requeue unpledge token", "err", err)
+			return nil, err
+		}
+	}
+	tks, err := up.w.GetAllPledgedTokens()
+	if err == nil {
+		for i := range tks {
+			if up.getState(tks[i].TokenID) != nil {
+				continue
+			}
+			err = up.queueToken(tks[i].TokenID)
+			if err != nil {
+				up.log.Error("Failed to write unpledge list", "err", err)
+				return nil, err
 			}
 		}
 	}
@@ -85,8 +161,79 @@ func InitUnPledge(s storage.Storage, w *wallet.Wallet, testNet bool, dir string,
 	return up, nil
 }
 
+// SetCompactProof sets the proof file format, the compact proofs can not be
+// read by the nodes running the earlier versions
+func (up *UnPledge) SetCompactProof(compact bool) {
+	up.l.Lock()
+	defer up.l.Unlock()
+	up.compact = compact
+}
+
+func (up *UnPledge) getState(t string) *UnpledgeState {
+	var st UnpledgeState
+	err := up.s.Read(UnpledgeStateTable, &st, "token=?", t)
+	if err != nil || st.Token == "" {
+		return nil
+	}
+	return &st
+}
+
+func (up *UnPledge) getStates(querry string, values ...interface{}) []UnpledgeState {
+	var sts []UnpledgeState
+	if up.s.GetDataCount(UnpledgeStateTable, querry, values...) == 0 {
+		return sts
+	}
+	err := up.s.Read(UnpledgeStateTable, &sts, querry, values...)
+	if err != nil {
+		return nil
+	}
+	return sts
+}
+
+func (up *UnPledge) saveState(st *UnpledgeState) error {
+	st.UpdateTime = time.Now()
+	if up.getState(st.Token) == nil {
+		return up.s.Write(UnpledgeStateTable, st)
+	}
+	return up.s.Update(UnpledgeStateTable, st, "token=?", st.Token)
+}
+
+func (up *UnPledge) queueToken(t string) error {
+	st := up.getState(t)
+	if st == nil {
+		st = &UnpledgeState{
+			Token:        t,
+			CreationTime: time.Now(),
+		}
+	}
+	st.Status = UnpledgeQueued
+	st.Reason = ""
+	return up.saveState(st)
+}
+
+func (up *UnPledge) setState(t string, status int, reason string) {
+	up.l.Lock()
+	defer up.l.Unlock()
+	st := up.getState(t)
+	if st == nil {
+		return
+	}
+	st.Status = status
+	st.Reason = reason
+	err := up.saveState(st)
+	if err != nil {
+		up.log.Error("Failed to update unpledge state", "token", t, "err", err)
+	}
+}
+
+// RunUnpledge starts the workers to process the queued tokens
 func (up *UnPledge) RunUnpledge() {
-	go up.runUnpledge()
+	up.l.Lock()
+	defer up.l.Unlock()
+	for up.running < up.workers {
+		up.running++
+		go up.runWorker()
+	}
 }
 
 func sha2Hash256(input string) string {
@@ -113,155 +260,319 @@ func sha3Hash256Loop(input string) string {
 
 func (up *UnPledge) isRunning() bool {
 	up.l.Lock()
-	s := up.running
+	s := up.running > 0
 	up.l.Unlock()
 	return s
 }
 
 func (up *UnPledge) AddUnPledge(t string) {
-	var list UnpledgeTokenList
-	err := up.s.Read(UnpledgeQueueTable, &list, "token = ?", t)
-	if err == nil {
+	up.l.Lock()
+	st := up.getState(t)
+	if st != nil && (st.Status == UnpledgeQueued || st.Status == UnpledgeWorking || st.Status == UnpledgeProofWritten) {
+		up.l.Unlock()
 		up.log.Error("Token already in the unpledge list")
 		return
 	}
-	list.Token = t
-	err = up.s.Write(UnpledgeQueueTable, &list)
+	err := up.queueToken(t)
+	up.l.Unlock()
 	if err != nil {
 		up.log.Error("Error adding token "+t+" to unpledge list", "err", err)
 		return
 	}
-	go up.runUnpledge()
+	up.RunUnpledge()
 }
 
-func (up *UnPledge) runUnpledge() {
+// RetryUnpledge queues the failed or cancelled token again
+func (up *UnPledge) RetryUnpledge(t string) error {
 	up.l.Lock()
-	if up.running {
+	st := up.getState(t)
+	if st == nil {
 		up.l.Unlock()
-		return
+		return fmt.Errorf("token is not in the unpledge list")
 	}
-	up.log.Info("Unpledging started")
-	up.running = true
-	up.l.Unlock()
-	defer func() {
-		up.l.Lock()
-		up.running = false
+	if st.Status != UnpledgeFailed && st.Status != UnpledgeCancelled {
 		up.l.Unlock()
-	}()
-	for {
-		var list UnpledgeTokenList
-		err := up.s.Read(UnpledgeQueueTable, &list, "token != ?", "")
-		if err != nil {
-			up.log.Info("All tokens are unplegded")
-			break
-		}
-		st := time.Now()
-		t := list.Token
-		tt := token.RBTTokenType
-		if up.testNet {
-			tt = token.TestTokenType
-		}
-		b := up.w.GetLatestTokenBlock(t, tt)
-		if b == nil {
-			up.log.Error("Failed to get the latest token block, removing the token from the unpledge list")
-			up.s.Delete(UnpledgeQueueTable, &UnpledgeTokenList{}, "token=?", t)
-			continue
-		}
-		bid, err := b.GetBlockID(t)
-		if err != nil {
-			up.log.Error("Failed to get the block id, removing the token from the unpledge list")
-			up.s.Delete(UnpledgeQueueTable, &UnpledgeTokenList{}, "token=?", t)
-			continue
+		return fmt.Errorf("only failed or cancelled token can be retried")
+	}
+	err := up.queueToken(t)
+	up.l.Unlock()
+	if err != nil {
+		return err
+	}
+	up.RunUnpledge()
+	return nil
+}
+
+// CancelUnpledge cancels the queued token or stops the proof of work of the
+// token in progress
+func (up *UnPledge) CancelUnpledge(t string) error {
+	up.l.Lock()
+	defer up.l.Unlock()
+	st := up.getState(t)
+	if st == nil {
+		return fmt.Errorf("token is not in the unpledge list")
+	}
+	switch st.Status {
+	case UnpledgeQueued:
+		st.Status = UnpledgeCancelled
+		st.Reason = "cancelled by the user"
+		return up.saveState(st)
+	case UnpledgeWorking:
+		tp, ok := up.progress[t]
+		if ok {
+			atomic.StoreInt32(&tp.cancel, 1)
 		}
-		if b.GetTransType() != block.TokenPledgedType {
-			up.log.Error("Token is not in pledged state, removing the token from the unpledge list")
-			up.s.Delete(UnpledgeQueueTable, &UnpledgeTokenList{}, "token=?", t)
-			continue
+		return nil
+	case UnpledgeProofWritten:
+		return fmt.Errorf("proof is already written, token can not be cancelled")
+	default:
+		return fmt.Errorf("token unpledge is not pending")
+	}
+}
+
+// GetStatus returns the state of all the tokens in the unpledge list
+func (up *UnPledge) GetStatus() *QueueStatus {
+	sts := up.getStates("token!=?", "")
+	up.l.Lock()
+	defer up.l.Unlock()
+	qs := &QueueStatus{
+		Workers: up.workers,
+		Tokens:  make([]UnpledgeStatus, 0),
+	}
+	expected := float64(int64(1) << (4 * Difficultlevel))
+	remaining := float64(0)
+	rate := float64(0)
+	for _, st := range sts {
+		us := UnpledgeStatus{
+			UnpledgeState: st,
 		}
-		blk := b.GetTransBlock()
-		if blk == nil {
-			refID := b.GetRefID()
-			if refID == "" {
-				up.log.Error("Token block missing transaction block, removing the token from the unpledge list")
-				up.s.Delete(UnpledgeQueueTable, &UnpledgeTokenList{}, "token=?", t)
-				continue
-			}
-			ss := strings.Split(refID, ",")
-			if len(ss) != 3 {
-				up.log.Error("Invalid reference ID, removing the token from the unpledge list")
-				up.s.Delete(UnpledgeQueueTable, &UnpledgeTokenList{}, "token=?", t)
-				continue
-			}
-			tt, err := strconv.ParseInt(ss[1], 10, 32)
-			if err != nil {
-				up.log.Error("Invalid reference ID, removing the token from the unpledge list", "err", err)
-				up.s.Delete(UnpledgeQueueTable, &UnpledgeTokenList{}, "token=?", t)
-				continue
+		switch st.Status {
+		case UnpledgeQueued:
+			remaining += expected
+		case UnpledgeWorking:
+			tp, ok := up.progress[st.Token]
+			if ok {
+				us.Hashes = atomic.LoadInt64(&tp.hashes)
+				if d := time.Since(tp.start).Seconds(); d > 0 {
+					rate += float64(us.Hashes) / d
+				}
 			}
-			blk, err = up.w.GetTokenBlock(ss[0], int(tt), ss[2])
-			if err != nil {
-				up.log.Error("Failed to get transaction block, removing the token from the unpledge list", "err", err)
-				up.s.Delete(UnpledgeQueueTable, &UnpledgeTokenList{}, "token=?", t)
-				continue
+			if float64(us.Hashes) < expected {
+				remaining += expected - float64(us.Hashes)
 			}
 		}
-		nb := block.InitBlock(blk, nil)
-		if nb == nil {
-			up.log.Error("Invalid transaction block, removing the token from the unpledge list")
-			up.s.Delete(UnpledgeQueueTable, &UnpledgeTokenList{}, "token=?", t)
-			continue
+		qs.Tokens = append(qs.Tokens, us)
+	}
+	if rate == 0 {
+		rate = up.rate * float64(up.workers)
+	}
+	qs.HashRate = rate
+	if rate > 0 {
+		qs.ETA = time.Duration(remaining / rate * float64(time.Second))
+	}
+	return qs
+}
+
+// nextToken claims the next token, the tokens with the proof written before
+// the restart are claimed first and returned with resume set. The worker is
+// released under the same lock when there is no token, so that the token
+// added meanwhile starts a new worker.
+func (up *UnPledge) nextToken() (string, *tokenProgress, bool, bool) {
+	up.l.Lock()
+	defer up.l.Unlock()
+	tp := &tokenProgress{
+		start: time.Now(),
+	}
+	for _, st := range up.getStates("status=?", UnpledgeProofWritten) {
+		if _, ok := up.progress[st.Token]; !ok {
+			up.progress[st.Token] = tp
+			return st.Token, tp, true, true
 		}
-		tid := nb.GetTid()
-		rdid := nb.GetReceiverDID()
+	}
+	sts := up.getStates("status=?", UnpledgeQueued)
+	if len(sts) == 0 {
+		up.running--
+		return "", nil, false, false
+	}
+	st := sts[0]
+	st.Status = UnpledgeWorking
+	st.Attempts++
+	err := up.saveState(&st)
+	if err != nil {
+		up.log.Error("Failed to update unpledge state", "token", st.Token, "err", err)
+		up.running--
+		return "", nil, false, false
+	}
+	up.progress[st.Token] = tp
+	return st.Token, tp, false, true
+}
 
-		hash := sha2Hash256(t + rdid + bid)
-		fileName := up.dir + t + ".proof"
-		f, err := os.Create(fileName)
-		if err != nil {
-			up.log.Error("Failed to create file, removing the token from the unpledge list")
-			up.s.Delete(UnpledgeQueueTable, &UnpledgeTokenList{}, "token=?", t)
-			continue
+func (up *UnPledge) runWorker() {
+	for {
+		t, tp, resume, ok := up.nextToken()
+		if !ok {
+			up.log.Info("All tokens are unplegded")
+			return
+		}
+		if resume {
+			up.resumeToken(t)
+		} else {
+			up.unpledgeToken(t, tp)
 		}
+		up.l.Lock()
+		delete(up.progress, t)
+		up.l.Unlock()
+	}
+}
 
-		// ::TODO:: need to update
-		dl := Difficultlevel
-		targetHash := tid[len(tid)-dl:]
-		f.WriteString(fmt.Sprintf("%d\n", dl))
-		f.WriteString(hash + "\n")
-		count := 1
-		for {
-			hash = sha3Hash256(hash)
-			if targetHash == hash[len(hash)-dl:] {
-				f.WriteString(hash + "\n")
-				break
-			}
-			if count%RecordInterval == 0 {
-				f.WriteString(hash + "\n")
-			}
-			count++
+func (up *UnPledge) unpledgeToken(t string, tp *tokenProgress) {
+	st := time.Now()
+	fileName, done, err := up.writeProof(t, tp)
+	if err == errCancelled {
+		up.log.Info("Unpledging cancelled for the token " + t)
+		up.setState(t, UnpledgeCancelled, "cancelled by the user")
+		return
+	}
+	if err != nil {
+		up.log.Error("Failed to unpledge the token", "token", t, "err", err)
+		up.setState(t, UnpledgeFailed, err.Error())
+		return
+	}
+	if done {
+		up.log.Info("Token is already unpledged", "token", t)
+		up.setState(t, UnpledgeCallbackDone, "")
+		return
+	}
+	up.setState(t, UnpledgeProofWritten, "")
+	if up.runCallback(t, fileName) {
+		up.log.Info("Unpledging completed for the token " + t + " in " + time.Since(st).String())
+	}
+}
+
+// resumeToken runs the callback of the token with the proof written before
+// the restart, the token is queued again if the proof file is missing
+func (up *UnPledge) resumeToken(t string) {
+	fileName := up.dir + t + ".proof"
+	if _, err := os.Stat(fileName); err != nil {
+		up.log.Info("Proof file is missing, queuing the token again", "token", t)
+		up.setState(t, UnpledgeQueued, "")
+		return
+	}
+	up.log.Info("Resuming the unpledge callback for the token " + t)
+	if up.runCallback(t, fileName) {
+		up.log.Info("Unpledging completed for the token " + t)
+	}
+}
+
+func (up *UnPledge) runCallback(t string, fileName string) bool {
+	if up.cb == nil {
+		up.log.Error("Callback function not set")
+		up.setState(t, UnpledgeFailed, "callback function not set")
+		return false
+	}
+	err := up.cb(t, fileName)
+	if err != nil {
+		up.log.Error("Error in unpledge callback", "err", err)
+		up.setState(t, UnpledgeFailed, "unpledge callback failed, "+err.Error())
+		return false
+	}
+	up.setState(t, UnpledgeCallbackDone, "")
+	return true
+}
+
+// writeProof runs the proof of work for the token and writes the proof file,
+// done is set if the token is already unpledged
+func (up *UnPledge) writeProof(t string, tp *tokenProgress) (string, bool, error) {
+	tt := token.RBTTokenType
+	if up.testNet {
+		tt = token.TestTokenType
+	}
+	b := up.w.GetLatestTokenBlock(t, tt)
+	if b == nil {
+		return "", false, fmt.Errorf("failed to get the latest token block")
+	}
+	bid, err := b.GetBlockID(t)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get the block id")
+	}
+	if b.GetTransType() == block.TokenUnpledgedType {
+		return "", true, nil
+	}
+	if b.GetTransType() != block.TokenPledgedType {
+		return "", false, fmt.Errorf("token is not in pledged state")
+	}
+	blk := b.GetTransBlock()
+	if blk == nil {
+		refID := b.GetRefID()
+		if refID == "" {
+			return "", false, fmt.Errorf("token block missing transaction block")
+		}
+		ss := strings.Split(refID, ",")
+		if len(ss) != 3 {
+			return "", false, fmt.Errorf("invalid reference ID")
 		}
-		f.Close()
-		if up.cb == nil {
-			up.log.Error("Callback function not set, removing the token from the unpledge list")
-			up.s.Delete(UnpledgeQueueTable, &UnpledgeTokenList{}, "token=?", t)
-			continue
+		tt, err := strconv.ParseInt(ss[1], 10, 32)
+		if err != nil {
+			return "", false, fmt.Errorf("invalid reference ID, %s", err.Error())
 		}
-		err = up.cb(t, fileName)
+		blk, err = up.w.GetTokenBlock(ss[0], int(tt), ss[2])
 		if err != nil {
-			up.log.Error("Error in unpledge alback, removing the token from the unpledge list", "err", err)
-			up.s.Delete(UnpledgeQueueTable, &UnpledgeTokenList{}, "token=?", t)
-			continue
+			return "", false, fmt.Errorf("failed to get transaction block, %s", err.Error())
 		}
-		et := time.Now()
-		df := et.Sub(st)
-		up.log.Info("Unpledging completed for the token " + t + " in " + df.String())
-		up.s.Delete(UnpledgeQueueTable, &UnpledgeTokenList{}, "token=?", t)
 	}
+	nb := block.InitBlock(blk, nil)
+	if nb == nil {
+		return "", false, fmt.Errorf("invalid transaction block")
+	}
+	tid := nb.GetTid()
+	rdid := nb.GetReceiverDID()
+
+	hash := sha2Hash256(t + rdid + bid)
+	fileName := up.dir + t + ".proof"
+
+	// ::TODO:: need to update
+	dl := Difficultlevel
+	targetHash := tid[len(tid)-dl:]
+	lines := []string{strconv.Itoa(dl), hash}
+	count := 1
+	for {
+		hash = sha3Hash256(hash)
+		if targetHash == hash[len(hash)-dl:] {
+			lines = append(lines, hash)
+			break
+		}
+		if count%RecordInterval == 0 {
+			lines = append(lines, hash)
+			atomic.StoreInt64(&tp.hashes, int64(count))
+			if atomic.LoadInt32(&tp.cancel) == 1 {
+				return "", false, errCancelled
+			}
+		}
+		count++
+	}
+	up.l.Lock()
+	compact := up.compact
+	up.l.Unlock()
+	err = writeProofFile(fileName, lines, compact)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to write proof file, %s", err.Error())
+	}
+	if d := time.Since(tp.start).Seconds(); d > 0 {
+		up.l.Lock()
+		up.rate = float64(count) / d
+		up.l.Unlock()
+	}
+	return fileName, false, nil
 }
 
-func (up *UnPledge) ProofVerification(tokenID string, proof []string, rdid string, tid string) (bool, error) {
-	tt := token.RBTTokenType
+func (up *UnPledge) ProofVerification(tokenID string, tt int, proof []string, rdid string, tid string) (bool, error) {
 	blk := up.w.GetLatestTokenBlock(tokenID, tt)
+	if blk == nil {
+		return false, errors.New("Failed to get the latest token block. Unable to verify proof file")
+	}
+	if len(proof) < 11 {
+		return false, errors.New("Proof is too short. Unable to verify proof file")
+	}
 
 	bid, err := blk.GetPrevBlockID(tokenID)
 	if err != nil {
@@ -276,6 +587,9 @@ func (up *UnPledge) ProofVerification(tokenID string, proof []string, rdid strin
 		return false, err
 	}
 	dl := Difficultlevel
+	if len(tid) < dl {
+		return false, errors.New("Invalid transaction ID. Unable to verify proof file")
+	}
 	if proof[0] != strconv.Itoa(dl) {
 		err := errors.New("First line of proof mismatch. Unable to verify proof file")
 		up.log.Error(err.Error())
@@ -331,7 +645,8 @@ func (up *UnPledge) ProofVerification(tokenID string, proof []string, rdid strin
 		target = targetHash
 	}
 	if c > RecordInterval-1 || suffixLasthash != tid[len(tid)-dl:] {
-		up.log.Error("Last line of proof mismatch, Unable to verify proof file")
+		err := errors.New("Last line of proof mismatch. Unable to verify proof file")
+		up.log.Error(err.Error())
 		return false, err
 	} else {
 		up.log.Info("Proof Verified for " + tokenID)
@@ -0,0 +1,144 @@
+package unpledge
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/rubixchain/rubixgoplatform/core/storage"
+	"github.com/rubixchain/rubixgoplatform/core/wallet"
+	"github.com/rubixchain/rubixgoplatform/wrapper/logger"
+)
+
+func waitStatus(up *UnPledge, t string, status int) bool {
+	for i := 0; i < 100; i++ {
+		st := up.getState(t)
+		if st != nil && st.Status == status {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
+
+func TestUnpledgeQueue(t *testing.T) {
+	log := logger.New(&logger.LoggerOptions{Name: "test", Color: []logger.ColorOption{logger.ColorOff}})
+	s, err := storage.NewStorageMemory()
+	if err != nil {
+		t.Fatal("Failed to create storage", err)
+	}
+	w, err := wallet.InitMemoryWallet(s, log)
+	if err != nil {
+		t.Fatal("Failed to init wallet", err)
+	}
+	s.Init(UnpledgeQueueTable, UnpledgeTokenList{}, true)
+	s.Write(UnpledgeQueueTable, &UnpledgeTokenList{Token: "token1"})
+	up, err := InitUnPledge(s, w, false, t.TempDir()+"/", 2, nil, log)
+	if err != nil {
+		t.Fatal("Failed to init unpledge", err)
+	}
+	// token from the earlier queue must be moved to the state
+	st := up.getState("token1")
+	if st == nil || st.Status != UnpledgeQueued {
+		t.Fatal("Token is not moved from the unpledge list")
+	}
+	if err := up.CancelUnpledge("token1"); err != nil {
+		t.Fatal("Failed to cancel unpledge", err)
+	}
+	if err := up.CancelUnpledge("token1"); err == nil {
+		t.Fatal("Cancelled token is cancelled again")
+	}
+	// token without the token chain must fail with the reason
+	up.AddUnPledge("token2")
+	if !waitStatus(up, "token2", UnpledgeFailed) {
+		t.Fatal("Token unpledge is not failed")
+	}
+	if up.getState("token2").Reason == "" {
+		t.Fatal("Failure reason is not recorded")
+	}
+	if err := up.RetryUnpledge("token1"); err != nil {
+		t.Fatal("Failed to retry unpledge", err)
+	}
+	if !waitStatus(up, "token1", UnpledgeFailed) || up.getState("token1").Attempts != 1 {
+		t.Fatal("Retried token is not processed")
+	}
+	qs := up.GetStatus()
+	if qs.Workers != 2 || len(qs.Tokens) != 2 {
+		t.Fatal("Invalid unpledge status", qs)
+	}
+	if err := up.RetryUnpledge("token3"); err == nil {
+		t.Fatal("Unknown token is retried")
+	}
+}
+
+func TestUnpledgeResume(t *testing.T) {
+	log := logger.New(&logger.LoggerOptions{Name: "test", Color: []logger.ColorOption{logger.ColorOff}})
+	s, err := storage.NewStorageMemory()
+	if err != nil {
+		t.Fatal("Failed to create storage", err)
+	}
+	w, err := wallet.InitMemoryWallet(s, log)
+	if err != nil {
+		t.Fatal("Failed to init wallet", err)
+	}
+	dir := t.TempDir() + "/"
+	s.Init(UnpledgeStateTable, &UnpledgeState{}, true)
+	s.Write(UnpledgeStateTable, &UnpledgeState{Token: "token1", Status: UnpledgeProofWritten, Attempts: 1})
+	s.Write(UnpledgeStateTable, &UnpledgeState{Token: "token2", Status: UnpledgeWorking, Attempts: 1})
+	s.Write(UnpledgeStateTable, &UnpledgeState{Token: "token3", Status: UnpledgeProofWritten, Attempts: 1})
+	os.WriteFile(dir+"token1.proof", []byte("6\n"), 0644)
+	cbs := make(chan string, 3)
+	cb := func(t string, file string) error {
+		cbs <- file
+		return nil
+	}
+	up, err := InitUnPledge(s, w, false, dir, 1, cb, log)
+	if err != nil {
+		t.Fatal("Failed to init unpledge", err)
+	}
+	if up.getState("token1").Status != UnpledgeProofWritten {
+		t.Fatal("Token with the proof written is queued again")
+	}
+	if up.getState("token2").Status != UnpledgeQueued {
+		t.Fatal("Interrupted token is not queued again")
+	}
+	up.RunUnpledge()
+	if !waitStatus(up, "token1", UnpledgeCallbackDone) {
+		t.Fatal("Token with the proof written is not resumed")
+	}
+	if f := <-cbs; f != dir+"token1.proof" || up.getState("token1").Attempts != 1 {
+		t.Fatal("Token is not resumed with the existing proof", f)
+	}
+	// proof file missing, proof of work is restarted
+	if !waitStatus(up, "token3", UnpledgeFailed) || up.getState("token3").Attempts != 2 {
+		t.Fatal("Token without the proof file is not queued again")
+	}
+}
+
+func TestUnpledgeWorkerExit(t *testing.T) {
+	log := logger.New(&logger.LoggerOptions{Name: "test", Color: []logger.ColorOption{logger.ColorOff}})
+	s, err := storage.NewStorageMemory()
+	if err != nil {
+		t.Fatal("Failed to create storage", err)
+	}
+	w, err := wallet.InitMemoryWallet(s, log)
+	if err != nil {
+		t.Fatal("Failed to init wallet", err)
+	}
+	up, err := InitUnPledge(s, w, false, t.TempDir()+"/", 1, nil, log)
+	if err != nil {
+		t.Fatal("Failed to init unpledge", err)
+	}
+	// the last worker finds no token and is about to exit
+	up.l.Lock()
+	up.running++
+	up.l.Unlock()
+	if _, _, _, ok := up.nextToken(); ok {
+		t.Fatal("Token is claimed from the empty list")
+	}
+	// token added before the worker returns must start a new worker
+	up.AddUnPledge("token1")
+	if !waitStatus(up, "token1", UnpledgeFailed) {
+		t.Fatal("Token added while the worker is exiting is not processed")
+	}
+}
@@ -0,0 +1,206 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/rubixchain/rubixgoplatform/block"
+	"github.com/rubixchain/rubixgoplatform/core/model"
+	"github.com/rubixchain/rubixgoplatform/token"
+	"github.com/rubixchain/rubixgoplatform/util"
+)
+
+// VerifyTokenChain audits the token chain end to end, every block is checked
+// for the hash link, owner & quorum signatures and the pledge details, the
+// genesis block is checked for the token level & number or the parent token
+func (c *Core) VerifyTokenChain(tkn string, tt int) (*model.TokenChainResult, error) {
+	blks, _, err := c.w.GetAllTokenBlocks(tkn, tt, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token chain, %s", err.Error())
+	}
+	if len(blks) == 0 {
+		return nil, fmt.Errorf("token chain is not found")
+	}
+	tr := &model.TokenChainResult{
+		Token:     tkn,
+		TokenType: tt,
+		Valid:     true,
+		Errors:    make([]string, 0),
+		Blocks:    make([]model.BlockResult, 0),
+	}
+	var pb *block.Block
+	for i, blk := range blks {
+		b := block.InitBlock(blk, nil)
+		if b == nil {
+			tr.Valid = false
+			tr.Errors = append(tr.Errors, fmt.Sprintf("failed to decode block at position %d", i))
+			return tr, nil
+		}
+		br := c.verifyChainBlock(tkn, b, pb)
+		if !br.Valid {
+			tr.Valid = false
+		}
+		tr.Blocks = append(tr.Blocks, br)
+		if i == 0 {
+			c.verifyGenesisBlock(tkn, tt, b, tr)
+		}
+		pb = b
+	}
+	if len(tr.Errors) > 0 {
+		tr.Valid = false
+	}
+	return tr, nil
+}
+
+func (c *Core) verifyChainBlock(tkn string, b *block.Block, pb *block.Block) model.BlockResult {
+	br := model.BlockResult{
+		TransType:        b.GetTransType(),
+		Owner:            b.GetOwner(),
+		Signatures:       make([]model.SignatureResult, 0),
+		QuorumSignatures: make([]model.SignatureResult, 0),
+		Errors:           make([]string, 0),
+	}
+	var err error
+	br.BlockID, err = b.GetBlockID(tkn)
+	if err != nil {
+		br.Errors = append(br.Errors, "failed to get block id")
+	}
+	br.BlockNumber, err = b.GetBlockNumber(tkn)
+	if err != nil {
+		br.Errors = append(br.Errors, "failed to get block number")
+	}
+	if pb != nil {
+		pid, _ := pb.GetBlockID(tkn)
+		pn, _ := pb.GetBlockNumber(tkn)
+		bpid, err := b.GetPrevBlockID(tkn)
+		if err != nil || bpid != pid {
+			br.Errors = append(br.Errors, fmt.Sprintf("previous block id %s does not match %s", bpid, pid))
+		}
+		if br.BlockNumber != pn+1 {
+			br.Errors = append(br.Errors, fmt.Sprintf("block number %d does not follow %d", br.BlockNumber, pn))
+		}
+		if br.TransType == block.TokenTransferredType && b.GetSenderDID() != "" && b.GetSenderDID() != pb.GetOwner() {
+			br.Errors = append(br.Errors, fmt.Sprintf("sender %s is not the previous owner %s", b.GetSenderDID(), pb.GetOwner()))
+		}
+	}
+	signers, err := b.GetSigner()
+	if err != nil {
+		br.Errors = append(br.Errors, "block signature is missing")
+	}
+	for _, signer := range signers {
+		err := c.verifyBlockSigner(b, signer)
+		br.Signatures = append(br.Signatures, model.SignatureResult{DID: signer, Valid: err == nil})
+		if err != nil {
+			br.Errors = append(br.Errors, fmt.Sprintf("signature of %s is invalid", signer))
+		}
+	}
+	qdids := verifyQuorumSignatures(b.GetTid(), b.GetQuorumSignature(), &br, c.verifyCreditSignature)
+	valid := len(qdids)
+	if br.TransType == block.TokenTransferredType && valid < MinConsensusRequired {
+		br.Errors = append(br.Errors, fmt.Sprintf("only %d valid quorum signatures, required %d", valid, MinConsensusRequired))
+	}
+	pds := b.GetPledgeDetails()
+	br.PledgeDetails = len(pds)
+	for _, pd := range pds {
+		if pd.Token == "" || pd.TokenBlockID == "" {
+			br.Errors = append(br.Errors, fmt.Sprintf("incomplete pledge details of %s", pd.DID))
+			continue
+		}
+		if !qdids[pd.DID] {
+			br.Errors = append(br.Errors, fmt.Sprintf("pledged token %s of %s is not backed by the quorum signature", pd.Token, pd.DID))
+		}
+	}
+	br.Valid = len(br.Errors) == 0
+	return br
+}
+
+// verifyQuorumSignatures returns the quorums with the valid signature of the
+// transaction, the signatures of the other transactions & the repeated
+// quorums are not counted
+func verifyQuorumSignatures(tid string, qss []string, br *model.BlockResult, verify func(cs *CreditSignature) bool) map[string]bool {
+	qdids := make(map[string]bool)
+	for _, qs := range qss {
+		var cs CreditSignature
+		err := json.Unmarshal([]byte(qs), &cs)
+		if err != nil {
+			br.Errors = append(br.Errors, "invalid quorum signature")
+			continue
+		}
+		if cs.Hash != tid {
+			br.QuorumSignatures = append(br.QuorumSignatures, model.SignatureResult{DID: cs.DID, Valid: false})
+			br.Errors = append(br.Errors, fmt.Sprintf("quorum signature of %s is not for the transaction %s", cs.DID, tid))
+			continue
+		}
+		if qdids[cs.DID] {
+			br.QuorumSignatures = append(br.QuorumSignatures, model.SignatureResult{DID: cs.DID, Valid: false})
+			br.Errors = append(br.Errors, fmt.Sprintf("quorum %s signed more than once", cs.DID))
+			continue
+		}
+		ok := verify(&cs)
+		br.QuorumSignatures = append(br.QuorumSignatures, model.SignatureResult{DID: cs.DID, Valid: ok})
+		if !ok {
+			br.Errors = append(br.Errors, fmt.Sprintf("quorum signature of %s is invalid", cs.DID))
+			continue
+		}
+		qdids[cs.DID] = true
+	}
+	return qdids
+}
+
+func (c *Core) verifyCreditSignature(cs *CreditSignature) bool {
+	dc, err := c.SetupForienDIDQuorum(cs.DID)
+	if err != nil {
+		c.log.Error("failed to setup forien DID quorum", "err", err)
+		return false
+	}
+	ok, err := dc.Verify(cs.Hash, util.StrToHex(cs.Signature), util.StrToHex(cs.PrivSignature))
+	if err != nil {
+		c.log.Error("Failed to verify quorum signature", "did", cs.DID, "err", err)
+		return false
+	}
+	return ok
+}
+
+func (c *Core) verifyGenesisBlock(tkn string, tt int, b *block.Block, tr *model.TokenChainResult) {
+	switch tt {
+	case c.TokenType(RBTString):
+		tl, tn, err := b.GetTokenDetials(tkn)
+		if err != nil {
+			tr.Errors = append(tr.Errors, "failed to get token details from the genesis block")
+			return
+		}
+		tr.TokenLevel = tl
+		tr.TokenNumber = tn
+		td, err := c.getFromIPFS(tkn)
+		if err != nil {
+			tr.Errors = append(tr.Errors, "failed to get token content")
+			return
+		}
+		vl, vn, _, err := token.ValidateWholeToken(string(td))
+		if err != nil {
+			tr.Errors = append(tr.Errors, "invalid whole token, "+err.Error())
+			return
+		}
+		if vl != tl || vn != tn {
+			tr.Errors = append(tr.Errors, fmt.Sprintf("genesis level %d & number %d does not match the token level %d & number %d", tl, tn, vl, vn))
+		}
+	case c.TokenType(PartString):
+		pt, _, err := b.GetParentDetials(tkn)
+		if err != nil || pt == "" {
+			tr.Errors = append(tr.Errors, "failed to get parent token from the genesis block")
+			return
+		}
+		tr.ParentToken = pt
+		plb := c.w.GetLatestTokenBlock(pt, c.TokenType(RBTString))
+		if plb == nil {
+			plb = c.w.GetLatestTokenBlock(pt, c.TokenType(PartString))
+		}
+		if plb == nil {
+			tr.Errors = append(tr.Errors, "parent token chain is not found")
+			return
+		}
+		if plb.GetTransType() != block.TokenBurntType {
+			tr.Errors = append(tr.Errors, "parent token is not burnt")
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/rubixchain/rubixgoplatform/core/model"
+)
+
+func testCreditSignature(did string, hash string) string {
+	cs := CreditSignature{DID: did, Hash: hash, Signature: "sig-" + did + "-" + hash}
+	b, _ := json.Marshal(&cs)
+	return string(b)
+}
+
+func TestVerifyQuorumSignatures(t *testing.T) {
+	verify := func(cs *CreditSignature) bool {
+		return cs.Signature == "sig-"+cs.DID+"-"+cs.Hash
+	}
+	tid := "tid1"
+	qss := make([]string, 0)
+	for i := 0; i < MinConsensusRequired; i++ {
+		qss = append(qss, testCreditSignature(fmt.Sprintf("quorum%d", i), tid))
+	}
+	var br model.BlockResult
+	qdids := verifyQuorumSignatures(tid, qss, &br, verify)
+	if len(qdids) != MinConsensusRequired || len(br.Errors) != 0 {
+		t.Fatal("Valid quorum signatures rejected", br.Errors)
+	}
+	// signature of the other transaction is replayed
+	replayed := append([]string{}, qss[1:]...)
+	replayed = append(replayed, testCreditSignature("quorum0", "tid2"))
+	br = model.BlockResult{}
+	qdids = verifyQuorumSignatures(tid, replayed, &br, verify)
+	if len(qdids) != MinConsensusRequired-1 || qdids["quorum0"] || len(br.Errors) != 1 {
+		t.Fatal("Replayed quorum signature accepted", br.Errors)
+	}
+	// same quorum repeated to reach the consensus
+	dup := make([]string, 0)
+	for i := 0; i < MinConsensusRequired; i++ {
+		dup = append(dup, testCreditSignature("quorum0", tid))
+	}
+	br = model.BlockResult{}
+	qdids = verifyQuorumSignatures(tid, dup, &br, verify)
+	if len(qdids) != 1 || len(br.Errors) != MinConsensusRequired-1 {
+		t.Fatal("Repeated quorum counted more than once", len(qdids))
+	}
+}
@@ -0,0 +1,249 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/rubixchain/rubixgoplatform/block"
+	"github.com/rubixchain/rubixgoplatform/contract"
+	"github.com/rubixchain/rubixgoplatform/core/model"
+	"github.com/rubixchain/rubixgoplatform/core/wallet"
+)
+
+// Wallet verification issues
+const (
+	WalletIssueMissingChain     string = "missing_chain"
+	WalletIssueInvalidBlock     string = "invalid_block"
+	WalletIssueBrokenLink       string = "broken_link"
+	WalletIssueInvalidSignature string = "invalid_signature"
+	WalletIssueOwnerMismatch    string = "owner_mismatch"
+	WalletIssueStatusMismatch   string = "status_mismatch"
+	WalletIssueStaleLock        string = "stale_lock"
+)
+
+var tokenStatusString = map[int]string{
+	wallet.TokenIsFree:        "free",
+	wallet.TokenIsLocked:      "locked",
+	wallet.TokenIsPledged:     "pledged",
+	wallet.TokenIsUnPledged:   "unpledged",
+	wallet.TokenIsTransferred: "transferred",
+	wallet.TokenIsCommitted:   "committed",
+	wallet.TokenIsGenerated:   "generated",
+	wallet.TokenIsDeployed:    "deployed",
+	wallet.TokenIsFetched:     "fetched",
+	wallet.TokenIsBurnt:       "burnt",
+	wallet.TokenIsExecuted:    "executed",
+}
+
+func statusString(s int) string {
+	ss, ok := tokenStatusString[s]
+	if !ok {
+		return fmt.Sprintf("unknown(%d)", s)
+	}
+	return ss
+}
+
+// VerifyWallet walks the token chain of every token in the wallet and cross
+// checks it with the tokens table. The report carries the repair plan, the
+// status fixes are applied only if repair is requested, broken chains are
+// reported but never modified. Stale locks are repaired only while no
+// request is in progress.
+func (c *Core) VerifyWallet(vr *model.VerifyWalletRequest) (*model.WalletReport, error) {
+	var tks []wallet.Token
+	var err error
+	if vr.DID != "" {
+		tks, err = c.w.GetAllTokens(vr.DID)
+	} else {
+		tks, err = c.w.GetAllWalletTokens()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tokens, %s", err.Error())
+	}
+	rp := &model.WalletReport{
+		Issues: make([]model.WalletIssue, 0),
+	}
+	inflight := c.inflightTokens()
+	for i := range tks {
+		rp.TokensChecked++
+		c.verifyWalletToken(&tks[i], vr, inflight, rp)
+	}
+	if vr.Repair {
+		for i := range rp.Issues {
+			c.repairWalletIssue(&rp.Issues[i])
+		}
+	}
+	c.log.Info("Wallet verification done", "tokens", rp.TokensChecked, "blocks", rp.BlocksChecked, "issues", len(rp.Issues))
+	return rp, nil
+}
+
+// inflightTokens returns the tokens held by the active pledge leases and the
+// in-flight transfers, these tokens are expected to be locked
+func (c *Core) inflightTokens() map[string]bool {
+	m := make(map[string]bool)
+	for _, pl := range c.GetPledgeLeases() {
+		m[pl.Token] = true
+	}
+	var tjs []TransferJournal
+	err := c.s.Read(TransferJournalStorage, &tjs, "req_id!=?", "")
+	if err != nil {
+		return m
+	}
+	for _, tj := range tjs {
+		var ti []contract.TokenInfo
+		err := json.Unmarshal([]byte(tj.TokenInfo), &ti)
+		if err != nil {
+			continue
+		}
+		for _, t := range ti {
+			m[t.Token] = true
+		}
+	}
+	return m
+}
+
+// hasActiveRequests checks whether any web request or consensus is in progress
+func (c *Core) hasActiveRequests() bool {
+	c.rlock.Lock()
+	wr := len(c.webReq)
+	c.rlock.Unlock()
+	c.qlock.RLock()
+	qr := len(c.quorumRequest)
+	c.qlock.RUnlock()
+	return wr > 0 || qr > 0
+}
+
+func (c *Core) verifyWalletToken(t *wallet.Token, vr *model.VerifyWalletRequest, inflight map[string]bool, rp *model.WalletReport) {
+	addIssue := func(blkID string, issue string, msg string) {
+		rp.Issues = append(rp.Issues, model.WalletIssue{
+			Token:   t.TokenID,
+			DID:     t.DID,
+			Status:  t.TokenStatus,
+			BlockID: blkID,
+			Issue:   issue,
+			Message: msg,
+		})
+	}
+	addRepair := func(blkID string, issue string, msg string, status int) {
+		rp.Issues = append(rp.Issues, model.WalletIssue{
+			Token:        t.TokenID,
+			DID:          t.DID,
+			Status:       t.TokenStatus,
+			BlockID:      blkID,
+			Issue:        issue,
+			Message:      msg,
+			Repairable:   true,
+			RepairStatus: status,
+		})
+	}
+	ts := RBTString
+	if t.TokenValue < 1.0 {
+		ts = PartString
+	}
+	blks, _, err := c.w.GetAllTokenBlocks(t.TokenID, c.TokenType(ts), "")
+	if err != nil || len(blks) == 0 {
+		addIssue("", WalletIssueMissingChain, "token chain is not found")
+		return
+	}
+	var lb *block.Block
+	prevID := ""
+	var prevNum uint64
+	for i, blk := range blks {
+		rp.BlocksChecked++
+		b := block.InitBlock(blk, nil)
+		if b == nil {
+			addIssue("", WalletIssueInvalidBlock, fmt.Sprintf("failed to decode block at position %d", i))
+			return
+		}
+		bid, err := b.GetBlockID(t.TokenID)
+		if err != nil {
+			addIssue("", WalletIssueInvalidBlock, fmt.Sprintf("failed to get block id at position %d", i))
+			return
+		}
+		bn, err := b.GetBlockNumber(t.TokenID)
+		if err != nil {
+			addIssue(bid, WalletIssueInvalidBlock, "failed to get block number")
+			return
+		}
+		if i > 0 {
+			pid, err := b.GetPrevBlockID(t.TokenID)
+			if err != nil || pid != prevID {
+				addIssue(bid, WalletIssueBrokenLink, fmt.Sprintf("previous block id %s does not match %s", pid, prevID))
+			}
+			if bn != prevNum+1 {
+				addIssue(bid, WalletIssueBrokenLink, fmt.Sprintf("block number %d does not follow %d", bn, prevNum))
+			}
+		}
+		if !vr.SkipSignature && !c.validateSigner(b) {
+			addIssue(bid, WalletIssueInvalidSignature, "block signature verification failed")
+		}
+		prevID = bid
+		prevNum = bn
+		lb = b
+	}
+	owner := lb.GetOwner()
+	st := t.TokenStatus
+	switch lb.GetTransType() {
+	case block.TokenPledgedType:
+		if st != wallet.TokenIsPledged {
+			addRepair(prevID, WalletIssueStatusMismatch, fmt.Sprintf("token is %s but the chain says pledged", statusString(st)), wallet.TokenIsPledged)
+		}
+		return
+	case block.TokenUnpledgedType:
+		if st == wallet.TokenIsPledged {
+			addRepair(prevID, WalletIssueStatusMismatch, "token is pledged but the chain says unpledged", wallet.TokenIsFree)
+			return
+		}
+	case block.TokenBurntType:
+		if st != wallet.TokenIsBurnt {
+			addRepair(prevID, WalletIssueStatusMismatch, fmt.Sprintf("token is %s but the chain says burnt", statusString(st)), wallet.TokenIsBurnt)
+		}
+		return
+	default:
+		if st == wallet.TokenIsPledged {
+			addIssue(prevID, WalletIssueStatusMismatch, "token is pledged but the latest block is not a pledge block")
+			return
+		}
+	}
+	if owner != "" && owner != t.DID && st != wallet.TokenIsTransferred {
+		addRepair(prevID, WalletIssueOwnerMismatch, fmt.Sprintf("token is %s but the chain owner is %s", statusString(st), owner), wallet.TokenIsTransferred)
+		return
+	}
+	if owner == t.DID && st == wallet.TokenIsTransferred {
+		addRepair(prevID, WalletIssueOwnerMismatch, "token is transferred but the chain owner is the wallet DID", wallet.TokenIsFree)
+		return
+	}
+	if st == wallet.TokenIsLocked && !inflight[t.TokenID] {
+		addRepair(prevID, WalletIssueStaleLock, "token is locked without any in-flight transfer or pledge", wallet.TokenIsFree)
+	}
+}
+
+// repairWalletIssue applies the planned status, token is skipped if the status
+// changed after the verification
+func (c *Core) repairWalletIssue(wi *model.WalletIssue) {
+	if !wi.Repairable {
+		return
+	}
+	// NFT, data token & smart contract flows lock the tokens without the
+	// journal, the lock can not be told stale while any request is active
+	if wi.Issue == WalletIssueStaleLock && c.hasActiveRequests() {
+		c.log.Info("Requests are in progress, skipping the stale lock repair", "token", wi.Token)
+		wi.Message += ", not repaired while the requests are in progress"
+		return
+	}
+	t, err := c.w.ReadToken(wi.Token)
+	if err != nil {
+		return
+	}
+	if t.DID != wi.DID || t.TokenStatus != wi.Status {
+		c.log.Info("Token changed after the verification, skipping the repair", "token", wi.Token)
+		return
+	}
+	t.TokenStatus = wi.RepairStatus
+	err = c.w.UpdateToken(t)
+	if err != nil {
+		c.log.Error("Failed to repair the token status", "token", wi.Token, "err", err)
+		return
+	}
+	c.log.Info("Token status repaired", "token", wi.Token, "issue", wi.Issue, "status", statusString(wi.RepairStatus))
+	wi.Repaired = true
+}
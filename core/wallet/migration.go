@@ -0,0 +1,161 @@
+package wallet
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+const (
+	SchemaVersionStorage string = "SchemaVersion"
+)
+
+// SchemaVersion records the migration applied on the wallet
+type SchemaVersion struct {
+	Version     int       `gorm:"column:version;primaryKey"`
+	Name        string    `gorm:"column:name"`
+	AppliedTime time.Time `gorm:"column:applied_time"`
+}
+
+// Migration is the schema or data migration step, steps are applied in the
+// order of the version and each step is applied only once
+type Migration struct {
+	Version int
+	Name    string
+	Migrate func(w *Wallet) error
+}
+
+// migrations must be appended with the next version, never reorder or
+// remove the released steps
+var migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "Initial wallet schema",
+		Migrate: func(w *Wallet) error { return nil },
+	},
+	{
+		Version: 2,
+		Name:    "Convert token chain keys to the fixed length block number",
+		Migrate: (*Wallet).migrateChainKeys,
+	},
+	{
+		Version: 3,
+		Name:    "Add DID last char to the DID peer map",
+		Migrate: (*Wallet).migrateDIDLastChar,
+	},
+}
+
+func (w *Wallet) initSchemaVersion() error {
+	return w.s.Init(SchemaVersionStorage, &SchemaVersion{}, true)
+}
+
+// GetAppliedMigrations returns the migrations applied on the wallet
+func (w *Wallet) GetAppliedMigrations() []SchemaVersion {
+	var svs []SchemaVersion
+	err := w.s.Read(SchemaVersionStorage, &svs, "version>?", 0)
+	if err != nil {
+		return make([]SchemaVersion, 0)
+	}
+	return svs
+}
+
+// GetSchemaVersion returns the current schema version of the wallet
+func (w *Wallet) GetSchemaVersion() int {
+	v := 0
+	for _, sv := range w.GetAppliedMigrations() {
+		if sv.Version > v {
+			v = sv.Version
+		}
+	}
+	return v
+}
+
+// GetPendingMigrations returns the migrations yet to be applied
+func (w *Wallet) GetPendingMigrations() []Migration {
+	v := w.GetSchemaVersion()
+	pm := make([]Migration, 0)
+	for _, m := range migrations {
+		if m.Version > v {
+			pm = append(pm, m)
+		}
+	}
+	return pm
+}
+
+// RunMigrations applies the pending migrations in order, it stops at the
+// first failed step so that it can be retried after fixing the cause
+func (w *Wallet) RunMigrations() ([]SchemaVersion, error) {
+	w.ml.Lock()
+	defer w.ml.Unlock()
+	applied := make([]SchemaVersion, 0)
+	for _, m := range w.GetPendingMigrations() {
+		w.log.Info("Running wallet migration", "version", m.Version, "name", m.Name)
+		st := time.Now()
+		err := m.Migrate(w)
+		if err != nil {
+			w.log.Error("Wallet migration failed", "version", m.Version, "name", m.Name, "err", err)
+			return applied, fmt.Errorf("migration %d failed, %s", m.Version, err.Error())
+		}
+		sv := SchemaVersion{
+			Version:     m.Version,
+			Name:        m.Name,
+			AppliedTime: time.Now(),
+		}
+		err = w.s.Write(SchemaVersionStorage, &sv)
+		if err != nil {
+			w.log.Error("Failed to record the schema version", "version", m.Version, "err", err)
+			return applied, err
+		}
+		applied = append(applied, sv)
+		w.log.Info("Wallet migration done", "version", m.Version, "duration", time.Since(st))
+	}
+	return applied, nil
+}
+
+// migrateChainKeys converts all the token chain keys with the old block
+// number format, earlier it was converted lazily on the block read
+func (w *Wallet) migrateChainKeys() error {
+	for _, db := range []*ChainDB{w.tcs, w.ntcs, w.dtcs, w.smartContractTokenChainStorage} {
+		b := new(leveldb.Batch)
+		iter := db.NewIterator(nil, nil)
+		for iter.Next() {
+			key := string(iter.Key())
+			if strings.HasPrefix(key, ReferenceType+"-") || !isOldKey(key) {
+				continue
+			}
+			nk := old2NewKey(key)
+			if nk == key {
+				continue
+			}
+			v := make([]byte, len(iter.Value()))
+			copy(v, iter.Value())
+			b.Delete([]byte(key))
+			b.Put([]byte(nk), v)
+		}
+		iter.Release()
+		err := iter.Error()
+		if err != nil {
+			return err
+		}
+		if b.Len() == 0 {
+			continue
+		}
+		db.l.Lock()
+		err = db.Write(b, nil)
+		db.l.Unlock()
+		if err != nil {
+			return err
+		}
+		w.log.Info("Token chain keys converted", "count", b.Len()/2)
+	}
+	return nil
+}
+
+func (w *Wallet) migrateDIDLastChar() error {
+	if w.s.GetDataCount(DIDPeerStorage, "did_last_char is NULL") == 0 {
+		return nil
+	}
+	return w.AddDIDLastChar()
+}
@@ -20,6 +20,27 @@ type CallBackUrl struct {
 	CreatedAt         time.Time `gorm:"column:created_at" json:"created_at"`
 }
 
+// SCCallBack is the callback URL of the smart contract, the deliveries are
+// signed with the secret
+type SCCallBack struct {
+	ID                string    `gorm:"column:id;primaryKey" json:"id"`
+	SmartContractHash string    `gorm:"column:smart_contract_hash" json:"smart_contract_hash"`
+	CallBackUrl       string    `gorm:"column:callback_url" json:"callback_url"`
+	Secret            string    `gorm:"column:secret" json:"-"`
+	CreatedAt         time.Time `gorm:"column:created_at" json:"created_at"`
+}
+
+// SCSubscription is the smart contract subscribed by the node, the peer which
+// published the last event is used to sync the token chain on the resume
+type SCSubscription struct {
+	SmartContractHash string    `gorm:"column:smart_contract_hash;primaryKey" json:"smart_contract_hash"`
+	PeerID            string    `gorm:"column:peer_id" json:"peer_id"`
+	DID               string    `gorm:"column:did" json:"did"`
+	LastBlockID       string    `gorm:"column:last_block_id" json:"last_block_id"`
+	CreatedAt         time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt         time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
 func (w *Wallet) CreateSmartContractToken(sc *SmartContract) error {
 	err := w.s.Write(SmartContractStorage, sc)
 	if err != nil {
@@ -125,3 +146,120 @@ func (w *Wallet) GetSmartContractTokenUrl(smartcontracttoken string) (string, er
 	url := callback.CallBackUrl
 	return url, nil
 }
+
+// AddSmartContractCallBack adds the callback URL of the smart contract, the
+// secret is updated if the URL is already registered
+func (w *Wallet) AddSmartContractCallBack(cb *SCCallBack) error {
+	cb.ID = cb.SmartContractHash + "|" + cb.CallBackUrl
+	var ecb SCCallBack
+	err := w.s.Read(SCCallBackStorage, &ecb, "id=?", cb.ID)
+	if err == nil && ecb.ID != "" {
+		err = w.s.Update(SCCallBackStorage, cb, "id=?", cb.ID)
+	} else {
+		err = w.s.Write(SCCallBackStorage, cb)
+	}
+	if err != nil {
+		w.log.Error("Failed to write smart contract callback", "err", err)
+		return err
+	}
+	return nil
+}
+
+// GetSmartContractCallBacks returns the callback URLs of the smart contract,
+// the URL registered before the multiple URLs support is returned without
+// the secret
+func (w *Wallet) GetSmartContractCallBacks(smartContractToken string) ([]SCCallBack, error) {
+	var cbs []SCCallBack
+	err := w.s.Read(SCCallBackStorage, &cbs, "smart_contract_hash=?", smartContractToken)
+	if err != nil {
+		cbs = make([]SCCallBack, 0)
+	}
+	var callback CallBackUrl
+	err = w.s.Read(CallBackUrlStorage, &callback, "smart_contract_hash=?", smartContractToken)
+	if err == nil && callback.CallBackUrl != "" {
+		found := false
+		for _, cb := range cbs {
+			if cb.CallBackUrl == callback.CallBackUrl {
+				found = true
+				break
+			}
+		}
+		if !found {
+			cbs = append(cbs, SCCallBack{
+				ID:                smartContractToken + "|" + callback.CallBackUrl,
+				SmartContractHash: smartContractToken,
+				CallBackUrl:       callback.CallBackUrl,
+				CreatedAt:         callback.CreatedAt,
+			})
+		}
+	}
+	return cbs, nil
+}
+
+// GetSmartContractCallBack returns the callback of the smart contract URL
+func (w *Wallet) GetSmartContractCallBack(smartContractToken string, url string) *SCCallBack {
+	cbs, _ := w.GetSmartContractCallBacks(smartContractToken)
+	for i := range cbs {
+		if cbs[i].CallBackUrl == url {
+			return &cbs[i]
+		}
+	}
+	return nil
+}
+
+// AddSCSubscription adds the smart contract subscription, the existing
+// subscription is left as it is
+func (w *Wallet) AddSCSubscription(smartContractToken string) error {
+	if w.GetSCSubscription(smartContractToken) != nil {
+		return nil
+	}
+	st := time.Now()
+	sub := &SCSubscription{
+		SmartContractHash: smartContractToken,
+		CreatedAt:         st,
+		UpdatedAt:         st,
+	}
+	err := w.s.Write(SCSubscriptionStorage, sub)
+	if err != nil {
+		w.log.Error("Failed to write smart contract subscription", "err", err)
+		return err
+	}
+	return nil
+}
+
+// UpdateSCSubscription updates the smart contract subscription
+func (w *Wallet) UpdateSCSubscription(sub *SCSubscription) error {
+	sub.UpdatedAt = time.Now()
+	err := w.s.Update(SCSubscriptionStorage, sub, "smart_contract_hash=?", sub.SmartContractHash)
+	if err != nil {
+		w.log.Error("Failed to update smart contract subscription", "err", err)
+		return err
+	}
+	return nil
+}
+
+// GetSCSubscription returns the smart contract subscription, returns nil if
+// the smart contract is not subscribed
+func (w *Wallet) GetSCSubscription(smartContractToken string) *SCSubscription {
+	var sub SCSubscription
+	err := w.s.Read(SCSubscriptionStorage, &sub, "smart_contract_hash=?", smartContractToken)
+	if err != nil || sub.SmartContractHash == "" {
+		return nil
+	}
+	return &sub
+}
+
+// GetSCSubscriptions returns all the smart contract subscriptions
+func (w *Wallet) GetSCSubscriptions() []SCSubscription {
+	var subs []SCSubscription
+	err := w.s.Read(SCSubscriptionStorage, &subs, "smart_contract_hash!=?", "")
+	if err != nil {
+		return make([]SCSubscription, 0)
+	}
+	return subs
+}
+
+// RemoveSCSubscription removes the smart contract subscription
+func (w *Wallet) RemoveSCSubscription(smartContractToken string) error {
+	return w.s.Delete(SCSubscriptionStorage, &SCSubscription{}, "smart_contract_hash=?", smartContractToken)
+}
@@ -0,0 +1,47 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/rubixchain/rubixgoplatform/core/storage"
+	"github.com/rubixchain/rubixgoplatform/wrapper/logger"
+)
+
+func TestSCSubscription(t *testing.T) {
+	s, err := storage.NewStorageMemory()
+	if err != nil {
+		t.Fatal("Failed to create memory storage", err)
+	}
+	defer s.Close()
+	w, err := InitMemoryWallet(s, logger.New(&logger.LoggerOptions{Name: "test", Color: []logger.ColorOption{logger.ColorOff}}))
+	if err != nil {
+		t.Fatal("Failed to init memory wallet", err)
+	}
+	token := "bafybmitestcontract"
+	if w.GetSCSubscription(token) != nil {
+		t.Fatal("Unexpected smart contract subscription")
+	}
+	err = w.AddSCSubscription(token)
+	if err != nil {
+		t.Fatal("Failed to add smart contract subscription", err)
+	}
+	sub := w.GetSCSubscription(token)
+	sub.PeerID, sub.DID, sub.LastBlockID = "peer", "did", "1-blockhash"
+	err = w.UpdateSCSubscription(sub)
+	if err != nil {
+		t.Fatal("Failed to update smart contract subscription", err)
+	}
+	// subscribing again keeps the last seen block
+	err = w.AddSCSubscription(token)
+	if err != nil {
+		t.Fatal("Failed to add smart contract subscription", err)
+	}
+	subs := w.GetSCSubscriptions()
+	if len(subs) != 1 || subs[0].LastBlockID != "1-blockhash" || subs[0].PeerID != "peer" {
+		t.Fatal("Smart contract subscription mismatch", subs)
+	}
+	err = w.RemoveSCSubscription(token)
+	if err != nil || len(w.GetSCSubscriptions()) != 0 {
+		t.Fatal("Failed to remove smart contract subscription", err)
+	}
+}
@@ -104,6 +104,20 @@ func (w *Wallet) GetAllTokens(did string) ([]Token, error) {
 	return t, nil
 }
 
+// GetAllWalletTokens returns the tokens of all the DIDs in the wallet
+func (w *Wallet) GetAllWalletTokens() ([]Token, error) {
+	var t []Token
+	if w.s.GetDataCount(TokenStorage, "token_id!=?", "") == 0 {
+		return t, nil
+	}
+	err := w.s.Read(TokenStorage, &t, "token_id!=?", "")
+	if err != nil {
+		w.log.Error("Failed to get tokens", "err", err)
+		return nil, err
+	}
+	return t, nil
+}
+
 func (w *Wallet) GetAllPledgedTokens() ([]Token, error) {
 	var t []Token
 	err := w.s.Read(TokenStorage, &t, "token_status=?", TokenIsPledged)
@@ -320,29 +334,45 @@ func (w *Wallet) UpdateToken(t *Token) error {
 	return nil
 }
 
-func (w *Wallet) TokensTransferred(did string, ti []contract.TokenInfo, b *block.Block, local bool) error {
+// TokensTransferred marks the tokens as transferred and records the
+// transaction history (if provided) in the same unit of work
+func (w *Wallet) TokensTransferred(did string, ti []contract.TokenInfo, b *block.Block, local bool, td *TransactionDetails) error {
 	w.l.Lock()
 	defer w.l.Unlock()
+	u, err := w.BeginUnitOfWork()
+	if err != nil {
+		return err
+	}
+	defer u.Rollback()
 	// ::TODO:: need to address part & other tokens
 	// Skip update if it is local DID
 	if !local {
-		err := w.CreateTokenBlock(b)
-		if err != nil {
-			return err
-		}
 		for i := range ti {
-			var t Token
-			err := w.s.Read(TokenStorage, &t, "did=? AND token_id=?", did, ti[i].Token)
-			if err != nil {
-				return err
-			}
-			t.TokenStatus = TokenIsTransferred
-			err = w.s.Update(TokenStorage, &t, "did=? AND token_id=?", did, ti[i].Token)
+			err := u.UpdateTokenStatus(did, ti[i].Token, TokenIsTransferred)
 			if err != nil {
 				return err
 			}
 		}
 	}
+	if td != nil {
+		err = u.AddTransactionHistory(td)
+		if err != nil {
+			return err
+		}
+	}
+	err = u.Commit()
+	if err != nil {
+		return err
+	}
+	// token chain is written after the commit, the storage is not held across
+	// the LevelDB write and the write can be retried till the block exist
+	if !local {
+		err = w.EnsureTokenBlock(b)
+		if err != nil {
+			w.log.Error("Failed to write the token block, transfer is committed", "err", err)
+			return err
+		}
+	}
 	// for i := range pt {
 	// 	var t Token
 	// 	err := w.s.Read(PartTokenStorage, &t, "did=? AND token_id=?", did, pt[i])
@@ -367,7 +397,9 @@ func (w *Wallet) TokensTransferred(did string, ti []contract.TokenInfo, b *block
 	return nil
 }
 
-func (w *Wallet) TokensReceived(did string, ti []contract.TokenInfo, b *block.Block) error {
+// TokensReceived adds the received tokens to the DID and records the
+// transaction history (if provided) in the same unit of work
+func (w *Wallet) TokensReceived(did string, ti []contract.TokenInfo, b *block.Block, td *TransactionDetails) error {
 	w.l.Lock()
 	defer w.l.Unlock()
 	// TODO :: Needs to be address
@@ -375,11 +407,14 @@ func (w *Wallet) TokensReceived(did string, ti []contract.TokenInfo, b *block.Bl
 	if err != nil {
 		return err
 	}
-
+	// fetch & pin the tokens before the unit of work, it should not be held across IPFS calls
+	tks := make([]Token, len(ti))
+	exist := make([]bool, len(ti))
 	for i := range ti {
 		var t Token
 		err := w.s.Read(TokenStorage, &t, "token_id=?", ti[i].Token)
-		if err != nil || t.TokenID == "" {
+		exist[i] = err == nil && t.TokenID != ""
+		if !exist[i] {
 			dir := util.GetRandString()
 			err := util.CreateDir(dir)
 			if err != nil {
@@ -403,26 +438,45 @@ func (w *Wallet) TokensReceived(did string, ti []contract.TokenInfo, b *block.Bl
 				ParentTokenID: pt,
 				DID:           did,
 			}
-			err = w.s.Write(TokenStorage, &t)
+		}
+		//Pinnig the whole tokens and pat tokens
+		ok, err := w.Pin(ti[i].Token, OwnerRole, did)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("failed to pin token")
+		}
+		tks[i] = t
+	}
+	u, err := w.BeginUnitOfWork()
+	if err != nil {
+		return err
+	}
+	defer u.Rollback()
+	for i := range tks {
+		if !exist[i] {
+			err = u.WriteToken(&tks[i])
 			if err != nil {
 				return err
 			}
 		}
-
-		t.DID = did
-		t.TokenStatus = TokenIsFree
-		err = w.s.Update(TokenStorage, &t, "token_id=?", ti[i].Token)
+		tks[i].DID = did
+		tks[i].TokenStatus = TokenIsFree
+		err = u.UpdateToken(&tks[i])
 		if err != nil {
 			return err
 		}
-		//Pinnig the whole tokens and pat tokens
-		ok, err := w.Pin(ti[i].Token, OwnerRole, did)
+	}
+	if td != nil {
+		err = u.AddTransactionHistory(td)
 		if err != nil {
 			return err
 		}
-		if !ok {
-			return fmt.Errorf("failed to pin token")
-		}
+	}
+	err = u.Commit()
+	if err != nil {
+		return err
 	}
 	// for i := range pt {
 	// 	var t Token
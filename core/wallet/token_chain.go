@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/rubixchain/rubixgoplatform/block"
+	"github.com/rubixchain/rubixgoplatform/core/storage"
 	tkn "github.com/rubixchain/rubixgoplatform/token"
 	ut "github.com/rubixchain/rubixgoplatform/util"
 	"github.com/syndtr/goleveldb/leveldb/opt"
@@ -555,6 +556,53 @@ func (w *Wallet) CreateTokenBlock(b *block.Block) error {
 	return w.addBlocks(b)
 }
 
+// EnsureTokenBlock writes the token block for the tokens which do not have
+// it yet, it can be called again after a failed or partial write
+func (w *Wallet) EnsureTokenBlock(b *block.Block) error {
+	tokens := b.GetTransTokens()
+	if tokens == nil {
+		return fmt.Errorf("faile to get tokens from the block")
+	}
+	for _, token := range tokens {
+		bid, err := b.GetBlockID(token)
+		if err != nil {
+			return err
+		}
+		_, err = w.getBlock(b.GetTokenType(token), token, bid)
+		if err == nil {
+			continue
+		}
+		err = w.addBlock(token, b)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (w *Wallet) ClearTokenBlocks(tokenType int) error {
 	return w.clearBlocks(tokenType)
 }
+
+// BackupTokenChains writes the snapshot of all the token chain storages into the directory
+func (w *Wallet) BackupTokenChains(dir string) error {
+	dbs := map[string]*ChainDB{
+		TokenChainStorage:              w.tcs,
+		NFTChainStorage:                w.ntcs,
+		DataChainStorage:               w.dtcs,
+		SmartContractTokenChainStorage: w.smartContractTokenChainStorage,
+	}
+	for name, db := range dbs {
+		err := storage.CopyLDB(&db.DB, dir+name)
+		if err != nil {
+			w.log.Error("Failed to backup the token chain storage", "storage", name, "err", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// TokenChainStorages returns the names of the token chain storages
+func TokenChainStorages() []string {
+	return []string{TokenChainStorage, NFTChainStorage, DataChainStorage, SmartContractTokenChainStorage}
+}
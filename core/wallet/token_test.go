@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/rubixchain/rubixgoplatform/core/storage"
 	"github.com/rubixchain/rubixgoplatform/crypto"
 	"github.com/rubixchain/rubixgoplatform/util"
+	"github.com/rubixchain/rubixgoplatform/wrapper/logger"
 	"github.com/syndtr/goleveldb/leveldb"
 )
 
@@ -120,3 +122,76 @@ func TestTokenChainBlock(t *testing.T) {
 	iter.Release()
 	db.Close()
 }
+
+func TestMemoryWallet(t *testing.T) {
+	s, err := storage.NewStorageMemory()
+	if err != nil {
+		t.Fatal("Failed to create memory storage", err)
+	}
+	defer s.Close()
+	w, err := InitMemoryWallet(s, logger.New(&logger.LoggerOptions{Name: "test", Color: []logger.ColorOption{logger.ColorOff}}))
+	if err != nil {
+		t.Fatal("Failed to init memory wallet", err)
+	}
+	did := "bafybmitestdid"
+	for i := 0; i < 3; i++ {
+		err = w.CreateToken(&Token{TokenID: fmt.Sprintf("token%d", i), DID: did, TokenValue: 1, TokenStatus: TokenIsFree})
+		if err != nil {
+			t.Fatal("Failed to create token", err)
+		}
+	}
+	tks, err := w.GetTokens(did, 2)
+	if err != nil || len(tks) != 2 {
+		t.Fatal("Failed to get tokens", err)
+	}
+	err = w.ReleaseTokens(tks)
+	if err != nil {
+		t.Fatal("Failed to release tokens", err)
+	}
+	at, err := w.GetAllTokens(did)
+	if err != nil || len(at) != 3 {
+		t.Fatal("Failed to get all tokens", err)
+	}
+	for _, tk := range at {
+		if tk.TokenStatus != TokenIsFree {
+			t.Fatal("Token is not released", tk.TokenID)
+		}
+	}
+	if w.GetLatestTokenBlock("token0", 0) != nil {
+		t.Fatal("Unexpected token chain block")
+	}
+}
+
+func TestWalletMigrations(t *testing.T) {
+	s, err := storage.NewStorageMemory()
+	if err != nil {
+		t.Fatal("Failed to create memory storage", err)
+	}
+	defer s.Close()
+	w, err := InitMemoryWallet(s, logger.New(&logger.LoggerOptions{Name: "test", Color: []logger.ColorOption{logger.ColorOff}}))
+	if err != nil {
+		t.Fatal("Failed to init memory wallet", err)
+	}
+	if len(w.GetPendingMigrations()) != len(migrations) {
+		t.Fatal("All migrations should be pending")
+	}
+	err = w.tcs.Put([]byte("wt-token1-5-blockhash"), []byte("block"), nil)
+	if err != nil {
+		t.Fatal("Failed to write block", err)
+	}
+	_, err = w.RunMigrations()
+	if err != nil {
+		t.Fatal("Failed to run migrations", err)
+	}
+	if w.GetSchemaVersion() != migrations[len(migrations)-1].Version || len(w.GetPendingMigrations()) != 0 {
+		t.Fatal("Migrations are not applied")
+	}
+	v, err := w.tcs.Get([]byte("wt-token1-0000000000000005-blockhash"), nil)
+	if err != nil || string(v) != "block" {
+		t.Fatal("Token chain key is not converted")
+	}
+	ml, err := w.RunMigrations()
+	if err != nil || len(ml) != 0 {
+		t.Fatal("Migrations should be applied only once")
+	}
+}
@@ -0,0 +1,86 @@
+package wallet
+
+import (
+	"fmt"
+
+	"github.com/rubixchain/rubixgoplatform/core/storage"
+)
+
+// UnitOfWork groups the token status changes with their history records,
+// the changes are applied together on Commit or discarded on Rollback
+type UnitOfWork struct {
+	w    *Wallet
+	s    storage.Storage
+	done bool
+}
+
+// BeginUnitOfWork starts the unit of work, keep it short lived as the
+// storage is locked for the other writers till it is finished
+func (w *Wallet) BeginUnitOfWork() (*UnitOfWork, error) {
+	s, err := w.s.Begin()
+	if err != nil {
+		w.log.Error("Failed to begin the transaction", "err", err)
+		return nil, err
+	}
+	return &UnitOfWork{w: w, s: s}, nil
+}
+
+// ReadToken reads the token with in the unit of work
+func (u *UnitOfWork) ReadToken(did string, token string) (*Token, error) {
+	var t Token
+	err := u.s.Read(TokenStorage, &t, "did=? AND token_id=?", did, token)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// WriteToken adds the new token
+func (u *UnitOfWork) WriteToken(t *Token) error {
+	return u.s.Write(TokenStorage, t)
+}
+
+// UpdateToken updates the token
+func (u *UnitOfWork) UpdateToken(t *Token) error {
+	return u.s.Update(TokenStorage, t, "token_id=?", t.TokenID)
+}
+
+// UpdateTokenStatus updates the status of the token owned by the DID
+func (u *UnitOfWork) UpdateTokenStatus(did string, token string, status int) error {
+	t, err := u.ReadToken(did, token)
+	if err != nil {
+		return err
+	}
+	t.TokenStatus = status
+	return u.s.Update(TokenStorage, t, "did=? AND token_id=?", did, token)
+}
+
+// AddTransactionHistory adds the history record of the transaction
+func (u *UnitOfWork) AddTransactionHistory(td *TransactionDetails) error {
+	return u.s.Write(TransactionStorage, td)
+}
+
+// Commit applies all the changes of the unit of work
+func (u *UnitOfWork) Commit() error {
+	if u.done {
+		return fmt.Errorf("unit of work already finished")
+	}
+	u.done = true
+	err := u.s.Commit()
+	if err != nil {
+		u.w.log.Error("Failed to commit the transaction", "err", err)
+	}
+	return err
+}
+
+// Rollback discards all the changes of the unit of work, it is a no-op once committed
+func (u *UnitOfWork) Rollback() {
+	if u.done {
+		return
+	}
+	u.done = true
+	err := u.s.Rollback()
+	if err != nil {
+		u.w.log.Error("Failed to rollback the transaction", "err", err)
+	}
+}
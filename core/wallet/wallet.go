@@ -4,11 +4,12 @@ import (
 	"fmt"
 	"sync"
 
-	ipfsnode "github.com/ipfs/go-ipfs-api"
+	"github.com/rubixchain/rubixgoplatform/core/ipfsclient"
 	"github.com/rubixchain/rubixgoplatform/core/storage"
 	"github.com/rubixchain/rubixgoplatform/wrapper/logger"
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/opt"
+	lstorage "github.com/syndtr/goleveldb/leveldb/storage"
 )
 
 const (
@@ -27,6 +28,8 @@ const (
 	SmartContractTokenChainStorage string = "smartcontractokenchainstorage"
 	SmartContractStorage           string = "smartcontract"
 	CallBackUrlStorage             string = "callbackurl"
+	SCCallBackStorage              string = "sccallback"
+	SCSubscriptionStorage          string = "scsubscription"
 )
 
 type WalletConfig struct {
@@ -46,12 +49,13 @@ type ChainDB struct {
 }
 
 type Wallet struct {
-	ipfs                           *ipfsnode.Shell
+	ipfs                           ipfsclient.Client
 	s                              storage.Storage
 	l                              sync.Mutex
 	dtl                            sync.Mutex
 	log                            logger.Logger
 	wl                             sync.Mutex
+	ml                             sync.Mutex
 	tcs                            *ChainDB
 	dtcs                           *ChainDB
 	ntcs                           *ChainDB
@@ -59,6 +63,23 @@ type Wallet struct {
 }
 
 func InitWallet(s storage.Storage, dir string, log logger.Logger) (*Wallet, error) {
+	return initWallet(s, dir, false, log)
+}
+
+// InitMemoryWallet will setup the wallet with the in-memory token chain
+// storage, nothing is written to the disk and the chains are lost on exit
+func InitMemoryWallet(s storage.Storage, log logger.Logger) (*Wallet, error) {
+	return initWallet(s, "", true, log)
+}
+
+func openChainDB(path string, op *opt.Options, mem bool) (*leveldb.DB, error) {
+	if mem {
+		return leveldb.Open(lstorage.NewMemStorage(), op)
+	}
+	return leveldb.OpenFile(path, op)
+}
+
+func initWallet(s storage.Storage, dir string, mem bool, log logger.Logger) (*Wallet, error) {
 	var err error
 	w := &Wallet{
 		log: log.Named("wallet"),
@@ -72,19 +93,19 @@ func InitWallet(s storage.Storage, dir string, log logger.Logger) (*Wallet, erro
 		WriteBuffer: 64 * 1024 * 1024,
 	}
 
-	tdb, err := leveldb.OpenFile(dir+TokenChainStorage, op)
+	tdb, err := openChainDB(dir+TokenChainStorage, op, mem)
 	if err != nil {
 		w.log.Error("failed to configure token chain block storage", "err", err)
 		return nil, fmt.Errorf("failed to configure token chain block storage")
 	}
 	w.tcs.DB = *tdb
-	ntdb, err := leveldb.OpenFile(dir+NFTChainStorage, op)
+	ntdb, err := openChainDB(dir+NFTChainStorage, op, mem)
 	if err != nil {
 		w.log.Error("failed to configure NFT chain block storage", "err", err)
 		return nil, fmt.Errorf("failed to configure NFT chain block storage")
 	}
 	w.ntcs.DB = *ntdb
-	dtdb, err := leveldb.OpenFile(dir+DataChainStorage, op)
+	dtdb, err := openChainDB(dir+DataChainStorage, op, mem)
 	if err != nil {
 		w.log.Error("failed to configure data chain block storage", "err", err)
 		return nil, fmt.Errorf("failed to configure data chain block storage")
@@ -136,7 +157,7 @@ func InitWallet(s storage.Storage, dir string, log logger.Logger) (*Wallet, erro
 		return nil, err
 	}
 
-	smartcontracTokenchainstorageDB, err := leveldb.OpenFile(dir+SmartContractTokenChainStorage, op)
+	smartcontracTokenchainstorageDB, err := openChainDB(dir+SmartContractTokenChainStorage, op, mem)
 	if err != nil {
 		w.log.Error("failed to configure token chain block storage", "err", err)
 		return nil, fmt.Errorf("failed to configure token chain block storage")
@@ -148,10 +169,25 @@ func InitWallet(s storage.Storage, dir string, log logger.Logger) (*Wallet, erro
 		w.log.Error("Failed to initialize Smart Contract Callback Url storage", "err", err)
 		return nil, err
 	}
+	err = w.s.Init(SCCallBackStorage, &SCCallBack{}, true)
+	if err != nil {
+		w.log.Error("Failed to initialize Smart Contract Callback storage", "err", err)
+		return nil, err
+	}
+	err = w.s.Init(SCSubscriptionStorage, &SCSubscription{}, true)
+	if err != nil {
+		w.log.Error("Failed to initialize Smart Contract Subscription storage", "err", err)
+		return nil, err
+	}
+	err = w.initSchemaVersion()
+	if err != nil {
+		w.log.Error("Failed to initialize schema version storage", "err", err)
+		return nil, err
+	}
 
 	return w, nil
 }
 
-func (w *Wallet) SetupWallet(ipfs *ipfsnode.Shell) {
+func (w *Wallet) SetupWallet(ipfs ipfsclient.Client) {
 	w.ipfs = ipfs
 }
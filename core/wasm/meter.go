@@ -0,0 +1,534 @@
+package wasm
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// FuelGlobal is the exported global of the instrumented binary which holds
+// the remaining fuel
+const FuelGlobal string = "__fuel"
+
+const (
+	secImport    byte = 2
+	secGlobal    byte = 6
+	secExport    byte = 7
+	secStart     byte = 8
+	secElement   byte = 9
+	secCode      byte = 10
+	secData      byte = 11
+	secDataCount byte = 12
+)
+
+const (
+	opBlock    byte = 0x02
+	opLoop     byte = 0x03
+	opIf       byte = 0x04
+	opElse     byte = 0x05
+	opEnd      byte = 0x0b
+	opBr       byte = 0x0c
+	opBrIf     byte = 0x0d
+	opBrTable  byte = 0x0e
+	opReturn   byte = 0x0f
+	opGlobalGt byte = 0x23
+	opGlobalSt byte = 0x24
+	opI32Const byte = 0x41
+	opI64Const byte = 0x42
+	opI64LtS   byte = 0x53
+	opI64Sub   byte = 0x7d
+	opI64ExtU  byte = 0xad
+	opPrefixFC byte = 0xfc
+)
+
+type section struct {
+	id   byte
+	data []byte
+}
+
+type reader struct {
+	b   []byte
+	pos int
+}
+
+func (r *reader) byte() (byte, error) {
+	if r.pos >= len(r.b) {
+		return 0, fmt.Errorf("unexpected end of binary")
+	}
+	b := r.b[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *reader) bytes(n int) ([]byte, error) {
+	if n < 0 || r.pos+n > len(r.b) {
+		return nil, fmt.Errorf("unexpected end of binary")
+	}
+	b := r.b[r.pos : r.pos+n]
+	r.pos += n
+	return b, nil
+}
+
+func (r *reader) u32() (uint32, error) {
+	var v uint32
+	for shift := uint(0); shift < 35; shift += 7 {
+		b, err := r.byte()
+		if err != nil {
+			return 0, err
+		}
+		v |= uint32(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return v, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid integer encoding")
+}
+
+// skipLEB skips the signed or unsigned integer
+func (r *reader) skipLEB() error {
+	for i := 0; i < 10; i++ {
+		b, err := r.byte()
+		if err != nil {
+			return err
+		}
+		if b&0x80 == 0 {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid integer encoding")
+}
+
+func (r *reader) skipLEBs(n int) error {
+	for i := 0; i < n; i++ {
+		err := r.skipLEB()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *reader) name() (string, error) {
+	n, err := r.u32()
+	if err != nil {
+		return "", err
+	}
+	b, err := r.bytes(int(n))
+	return string(b), err
+}
+
+func appendU32(b []byte, v uint32) []byte {
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if v != 0 {
+			c |= 0x80
+		}
+		b = append(b, c)
+		if v == 0 {
+			return b
+		}
+	}
+}
+
+func appendS64(b []byte, v int64) []byte {
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if (v == 0 && c&0x40 == 0) || (v == -1 && c&0x40 != 0) {
+			return append(b, c)
+		}
+		b = append(b, c|0x80)
+	}
+}
+
+func parseSections(code []byte) ([]*section, error) {
+	if len(code) < 8 || !bytes.Equal(code[:8], []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}) {
+		return nil, fmt.Errorf("invalid wasm binary header")
+	}
+	r := &reader{b: code, pos: 8}
+	ss := make([]*section, 0)
+	for r.pos < len(r.b) {
+		id, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		n, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		data, err := r.bytes(int(n))
+		if err != nil {
+			return nil, err
+		}
+		ss = append(ss, &section{id: id, data: data})
+	}
+	return ss, nil
+}
+
+// vecCount returns the number of items of the vector section
+func vecCount(s *section) (uint32, error) {
+	if s == nil {
+		return 0, nil
+	}
+	r := &reader{b: s.data}
+	return r.u32()
+}
+
+// importedGlobals returns the number of the globals imported by the binary
+func importedGlobals(s *section) (uint32, error) {
+	if s == nil {
+		return 0, nil
+	}
+	r := &reader{b: s.data}
+	n, err := r.u32()
+	if err != nil {
+		return 0, err
+	}
+	var g uint32
+	for i := uint32(0); i < n; i++ {
+		for j := 0; j < 2; j++ {
+			if _, err := r.name(); err != nil {
+				return 0, err
+			}
+		}
+		kind, err := r.byte()
+		if err != nil {
+			return 0, err
+		}
+		switch kind {
+		case 0x00:
+			err = r.skipLEB()
+		case 0x01:
+			// reference type & limits
+			_, err = r.byte()
+			if err == nil {
+				err = skipLimits(r)
+			}
+		case 0x02:
+			err = skipLimits(r)
+		case 0x03:
+			g++
+			_, err = r.bytes(2)
+		default:
+			err = fmt.Errorf("invalid import kind %d", kind)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return g, nil
+}
+
+func skipLimits(r *reader) error {
+	flag, err := r.byte()
+	if err != nil {
+		return err
+	}
+	if flag&0x01 != 0 {
+		return r.skipLEBs(2)
+	}
+	return r.skipLEB()
+}
+
+// appendItem appends the item to the vector section
+func appendItem(s *section, item []byte) error {
+	r := &reader{b: s.data}
+	n, err := r.u32()
+	if err != nil {
+		return err
+	}
+	data := appendU32(nil, n+1)
+	data = append(data, s.data[r.pos:]...)
+	s.data = append(data, item...)
+	return nil
+}
+
+// insertSection inserts the section before the first section of the ids
+func insertSection(ss []*section, s *section, before ...byte) []*section {
+	for i, es := range ss {
+		for _, id := range before {
+			if es.id == id {
+				ns := append([]*section{}, ss[:i]...)
+				ns = append(ns, s)
+				return append(ns, ss[i:]...)
+			}
+		}
+	}
+	return append(ss, s)
+}
+
+func findSection(ss []*section, id byte) *section {
+	for _, s := range ss {
+		if s.id == id {
+			return s
+		}
+	}
+	return nil
+}
+
+// chargeCode returns the code which deducts the cost from the fuel global,
+// the execution traps once the fuel is exhausted
+func chargeCode(g uint32, cost int64) []byte {
+	b := appendU32([]byte{opGlobalGt}, g)
+	b = appendS64(append(b, opI64Const), cost)
+	b = append(b, opI64Sub)
+	return checkCode(append(b, opGlobalSt), g)
+}
+
+// lengthChargeCode returns the code which deducts the length operand of the
+// bulk instruction from the fuel global, the length is saved in the scratch
+// global following the fuel global and pushed back for the instruction
+func lengthChargeCode(g uint32) []byte {
+	b := appendU32([]byte{opGlobalSt}, g+1)
+	b = appendU32(append(b, opGlobalGt), g)
+	b = appendU32(append(b, opGlobalGt), g+1)
+	b = append(b, opI64ExtU, opI64Sub, opGlobalSt)
+	b = checkCode(b, g)
+	return appendU32(append(b, opGlobalGt), g+1)
+}
+
+// checkCode stores the fuel global and traps if the fuel is exhausted
+func checkCode(b []byte, g uint32) []byte {
+	b = appendU32(b, g)
+	b = appendU32(append(b, opGlobalGt), g)
+	return append(b, opI64Const, 0x00, opI64LtS, opIf, 0x40, 0x00, opEnd)
+}
+
+// isBulk checks whether the 0xfc instruction takes the length operand on the
+// top of the stack, i.e. memory.init/copy/fill and table.init/copy/grow/fill
+func isBulk(sub uint32) bool {
+	switch sub {
+	case 8, 10, 11, 12, 14, 15, 17:
+		return true
+	}
+	return false
+}
+
+// skipImmediates skips the immediates of the instruction
+func skipImmediates(r *reader, op byte) error {
+	switch {
+	case op == 0x00 || op == 0x01 || op == opElse || op == opEnd || op == opReturn:
+		return nil
+	case op == opBlock || op == opLoop || op == opIf:
+		b, err := r.byte()
+		if err != nil {
+			return err
+		}
+		if b == 0x40 || (b >= 0x6f && b <= 0x7f) {
+			return nil
+		}
+		// type index of the block
+		r.pos--
+		return r.skipLEB()
+	case op == opBr || op == opBrIf:
+		return r.skipLEB()
+	case op == opBrTable:
+		n, err := r.u32()
+		if err != nil {
+			return err
+		}
+		return r.skipLEBs(int(n) + 1)
+	case op == 0x10:
+		return r.skipLEB()
+	case op == 0x11:
+		return r.skipLEBs(2)
+	case op == 0x1a || op == 0x1b:
+		return nil
+	case op == 0x1c:
+		n, err := r.u32()
+		if err != nil {
+			return err
+		}
+		_, err = r.bytes(int(n))
+		return err
+	case op >= 0x20 && op <= 0x26:
+		return r.skipLEB()
+	case op >= 0x28 && op <= 0x3e:
+		return r.skipLEBs(2)
+	case op == 0x3f || op == 0x40:
+		return r.skipLEB()
+	case op == 0x41 || op == 0x42:
+		return r.skipLEB()
+	case op == 0x43:
+		_, err := r.bytes(4)
+		return err
+	case op == 0x44:
+		_, err := r.bytes(8)
+		return err
+	case op >= 0x45 && op <= 0xc4:
+		return nil
+	case op == 0xd0:
+		_, err := r.byte()
+		return err
+	case op == 0xd1:
+		return nil
+	case op == 0xd2:
+		return r.skipLEB()
+	case op == opPrefixFC:
+		sub, err := r.u32()
+		if err != nil {
+			return err
+		}
+		switch {
+		case sub <= 7:
+			return nil
+		case sub == 8 || sub == 10 || sub == 12 || sub == 14:
+			return r.skipLEBs(2)
+		case sub == 9 || sub == 11 || sub == 13 || sub == 15 || sub == 16 || sub == 17:
+			return r.skipLEB()
+		}
+		return fmt.Errorf("unsupported instruction 0xfc %d", sub)
+	}
+	return fmt.Errorf("unsupported instruction 0x%x", op)
+}
+
+// isRunEnd checks whether the instruction ends the straight line run
+func isRunEnd(op byte) bool {
+	switch op {
+	case opBlock, opLoop, opIf, opElse, opEnd, opBr, opBrIf, opBrTable, opReturn:
+		return true
+	}
+	return false
+}
+
+// meterBody charges the cost of each straight line run of the function at
+// the start of the run, each instruction costs one fuel. The bulk memory &
+// table instructions are charged one more fuel per byte or element before
+// they are executed. The globals from the fuel global onwards are not
+// accessible to the binary.
+func meterBody(body []byte, g uint32) ([]byte, error) {
+	r := &reader{b: body}
+	n, err := r.u32()
+	if err != nil {
+		return nil, err
+	}
+	for i := uint32(0); i < n; i++ {
+		err = r.skipLEB()
+		if err == nil {
+			_, err = r.byte()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	out := append([]byte{}, body[:r.pos]...)
+	run := make([]byte, 0)
+	var cost int64
+	for r.pos < len(r.b) {
+		start := r.pos
+		op, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		if op == opPrefixFC {
+			sub, err := r.u32()
+			if err != nil {
+				return nil, err
+			}
+			if isBulk(sub) {
+				run = append(run, lengthChargeCode(g)...)
+			}
+			r.pos = start + 1
+		}
+		if op == opGlobalGt || op == opGlobalSt {
+			var idx uint32
+			idx, err = r.u32()
+			if err == nil && idx >= g {
+				err = fmt.Errorf("invalid global index %d", idx)
+			}
+		} else {
+			err = skipImmediates(r, op)
+		}
+		if err != nil {
+			return nil, err
+		}
+		cost++
+		run = append(run, body[start:r.pos]...)
+		if isRunEnd(op) || r.pos == len(r.b) {
+			out = append(out, chargeCode(g, cost)...)
+			out = append(out, run...)
+			run = run[:0]
+			cost = 0
+		}
+	}
+	return out, nil
+}
+
+// Instrument adds the fuel metering to the binary, the fuel global is
+// initialised with the fuel limit and exported as FuelGlobal. The binary
+// must be validated before, the fuel global and the scratch global of the
+// bulk instructions are appended after its globals.
+func Instrument(code []byte, fuel uint64) ([]byte, error) {
+	ss, err := parseSections(code)
+	if err != nil {
+		return nil, err
+	}
+	ig, err := importedGlobals(findSection(ss, secImport))
+	if err != nil {
+		return nil, err
+	}
+	gs := findSection(ss, secGlobal)
+	dg, err := vecCount(gs)
+	if err != nil {
+		return nil, err
+	}
+	g := ig + dg
+	gi := appendS64([]byte{0x7e, 0x01, opI64Const}, int64(fuel))
+	gi = append(gi, opEnd)
+	if gs == nil {
+		gs = &section{id: secGlobal, data: []byte{0x00}}
+		ss = insertSection(ss, gs, secExport, secStart, secElement, secDataCount, secCode, secData)
+	}
+	err = appendItem(gs, gi)
+	if err == nil {
+		err = appendItem(gs, []byte{0x7f, 0x01, opI32Const, 0x00, opEnd})
+	}
+	if err != nil {
+		return nil, err
+	}
+	es := findSection(ss, secExport)
+	if es == nil {
+		es = &section{id: secExport, data: []byte{0x00}}
+		ss = insertSection(ss, es, secStart, secElement, secDataCount, secCode, secData)
+	}
+	ei := appendU32(nil, uint32(len(FuelGlobal)))
+	ei = append(ei, FuelGlobal...)
+	ei = appendU32(append(ei, 0x03), g)
+	err = appendItem(es, ei)
+	if err != nil {
+		return nil, err
+	}
+	cs := findSection(ss, secCode)
+	if cs != nil {
+		r := &reader{b: cs.data}
+		n, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		data := appendU32(nil, n)
+		for i := uint32(0); i < n; i++ {
+			l, err := r.u32()
+			if err != nil {
+				return nil, err
+			}
+			body, err := r.bytes(int(l))
+			if err != nil {
+				return nil, err
+			}
+			mb, err := meterBody(body, g)
+			if err != nil {
+				return nil, err
+			}
+			data = appendU32(data, uint32(len(mb)))
+			data = append(data, mb...)
+		}
+		cs.data = data
+	}
+	out := append([]byte{}, code[:8]...)
+	for _, s := range ss {
+		out = append(out, s.id)
+		out = appendU32(out, uint32(len(s.data)))
+		out = append(out, s.data...)
+	}
+	return out, nil
+}
@@ -0,0 +1,295 @@
+package wasm
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rubixchain/rubixgoplatform/wrapper/logger"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	// HostModule is the module name of the host functions imported by the contract
+	HostModule string = "rubix"
+	// ExecuteFunc is the function exported by the contract, it takes no
+	// arguments and returns the status, non zero status fails the execution
+	ExecuteFunc string = "execute"
+)
+
+const (
+	DefaultFuelLimit    uint64        = 10000000
+	DefaultMemoryPages  uint32        = 256
+	DefaultMaxStateSize int           = 1 << 20
+	DefaultExecTimeout  time.Duration = 30 * time.Second
+)
+
+var (
+	ErrOutOfFuel     = errors.New("smart contract execution ran out of fuel")
+	ErrStateTooLarge = errors.New("smart contract state exceeds the size limit")
+)
+
+// Limits are the resource limits of the contract execution, each executed
+// instruction costs one fuel, the bulk memory & table instructions and the
+// host functions cost one more fuel per byte or element. The timeout is only
+// the backstop of the fuel limit. The quorums execute the contract again with
+// their own limits and check the fuel & the state reported by the executor.
+type Limits struct {
+	Fuel         uint64
+	MemoryPages  uint32
+	MaxStateSize int
+	Timeout      time.Duration
+}
+
+// Context is the input of the contract execution
+type Context struct {
+	CallerDID string
+	RBTAmount float64
+	Input     []byte
+	State     []byte
+}
+
+// Result is the output of the contract execution
+type Result struct {
+	State  []byte
+	Events [][]byte
+	Fuel   uint64
+}
+
+type execution struct {
+	ec     *Context
+	state  []byte
+	events [][]byte
+}
+
+type execKey struct{}
+
+// Runtime executes the smart contract binaries, the compiled binaries are
+// cached by the code hash
+type Runtime struct {
+	l     sync.Mutex
+	log   logger.Logger
+	lim   Limits
+	rt    wazero.Runtime
+	cache map[string]wazero.CompiledModule
+}
+
+// NewRuntime creates the runtime, the default limits are used for the
+// limits which are not set
+func NewRuntime(log logger.Logger, lim *Limits) (*Runtime, error) {
+	ctx := context.Background()
+	r := &Runtime{
+		log: log.Named("wasm"),
+		lim: Limits{
+			Fuel:         DefaultFuelLimit,
+			MemoryPages:  DefaultMemoryPages,
+			MaxStateSize: DefaultMaxStateSize,
+			Timeout:      DefaultExecTimeout,
+		},
+		cache: make(map[string]wazero.CompiledModule),
+	}
+	if lim != nil {
+		if lim.Fuel > 0 {
+			r.lim.Fuel = lim.Fuel
+		}
+		if lim.MemoryPages > 0 {
+			r.lim.MemoryPages = lim.MemoryPages
+		}
+		if lim.MaxStateSize > 0 {
+			r.lim.MaxStateSize = lim.MaxStateSize
+		}
+		if lim.Timeout > 0 {
+			r.lim.Timeout = lim.Timeout
+		}
+	}
+	cfg := wazero.NewRuntimeConfig().
+		WithCloseOnContextDone(true).
+		WithMemoryLimitPages(r.lim.MemoryPages)
+	r.rt = wazero.NewRuntimeWithConfig(ctx, cfg)
+	err := r.setupHost(ctx)
+	if err != nil {
+		r.rt.Close(ctx)
+		return nil, err
+	}
+	return r, nil
+}
+
+func getExecution(ctx context.Context) *execution {
+	return ctx.Value(execKey{}).(*execution)
+}
+
+// chargeFuel deducts the fuel of the host function
+func chargeFuel(m api.Module, n int) {
+	g, ok := m.ExportedGlobal(FuelGlobal).(api.MutableGlobal)
+	if !ok {
+		panic(fmt.Errorf("fuel global not found"))
+	}
+	v := int64(g.Get()) - int64(n)
+	g.Set(uint64(v))
+	if v < 0 {
+		panic(ErrOutOfFuel)
+	}
+}
+
+func readMemory(m api.Module, ptr uint32, l uint32) []byte {
+	chargeFuel(m, int(l))
+	b, ok := m.Memory().Read(ptr, l)
+	if !ok {
+		panic(fmt.Errorf("memory access out of range"))
+	}
+	return append([]byte{}, b...)
+}
+
+// copyMemory copies the data to the contract memory, returns the number of
+// bytes copied
+func copyMemory(m api.Module, ptr uint32, l uint32, data []byte) uint32 {
+	if int(l) > len(data) {
+		l = uint32(len(data))
+	}
+	chargeFuel(m, int(l))
+	if !m.Memory().Write(ptr, data[:l]) {
+		panic(fmt.Errorf("memory access out of range"))
+	}
+	return l
+}
+
+func (r *Runtime) setupHost(ctx context.Context) error {
+	_, err := r.rt.NewHostModuleBuilder(HostModule).
+		NewFunctionBuilder().WithFunc(func(ctx context.Context) uint32 {
+		return uint32(len(getExecution(ctx).state))
+	}).Export("state_len").
+		NewFunctionBuilder().WithFunc(func(ctx context.Context, m api.Module, ptr uint32, l uint32) uint32 {
+		return copyMemory(m, ptr, l, getExecution(ctx).state)
+	}).Export("state_read").
+		NewFunctionBuilder().WithFunc(func(ctx context.Context, m api.Module, ptr uint32, l uint32) {
+		if int(l) > r.lim.MaxStateSize {
+			panic(ErrStateTooLarge)
+		}
+		getExecution(ctx).state = readMemory(m, ptr, l)
+	}).Export("state_write").
+		NewFunctionBuilder().WithFunc(func(ctx context.Context) uint32 {
+		return uint32(len(getExecution(ctx).ec.Input))
+	}).Export("input_len").
+		NewFunctionBuilder().WithFunc(func(ctx context.Context, m api.Module, ptr uint32, l uint32) uint32 {
+		return copyMemory(m, ptr, l, getExecution(ctx).ec.Input)
+	}).Export("input_read").
+		NewFunctionBuilder().WithFunc(func(ctx context.Context) uint32 {
+		return uint32(len(getExecution(ctx).ec.CallerDID))
+	}).Export("caller_len").
+		NewFunctionBuilder().WithFunc(func(ctx context.Context, m api.Module, ptr uint32, l uint32) uint32 {
+		return copyMemory(m, ptr, l, []byte(getExecution(ctx).ec.CallerDID))
+	}).Export("caller_read").
+		NewFunctionBuilder().WithFunc(func(ctx context.Context) float64 {
+		return getExecution(ctx).ec.RBTAmount
+	}).Export("rbt_amount").
+		NewFunctionBuilder().WithFunc(func(ctx context.Context, m api.Module, ptr uint32, l uint32) {
+		e := getExecution(ctx)
+		e.events = append(e.events, readMemory(m, ptr, l))
+	}).Export("emit_event").
+		Instantiate(ctx)
+	return err
+}
+
+func (r *Runtime) compile(ctx context.Context, code []byte) (wazero.CompiledModule, error) {
+	h := sha3.Sum256(code)
+	key := hex.EncodeToString(h[:])
+	r.l.Lock()
+	defer r.l.Unlock()
+	cm, ok := r.cache[key]
+	if ok {
+		return cm, nil
+	}
+	// binary is validated as it is, the instrumented binary could be valid
+	// even if the binary accesses the globals it does not define
+	vm, err := r.rt.CompileModule(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	vm.Close(ctx)
+	ic, err := Instrument(code, r.lim.Fuel)
+	if err != nil {
+		return nil, err
+	}
+	cm, err = r.rt.CompileModule(ctx, ic)
+	if err != nil {
+		return nil, err
+	}
+	r.cache[key] = cm
+	return cm, nil
+}
+
+// IsContract checks whether the binary is executable by the runtime, the
+// binary should export the execute function and import only the host
+// functions
+func (r *Runtime) IsContract(code []byte) bool {
+	cm, err := r.compile(context.Background(), code)
+	if err != nil {
+		r.log.Debug("Failed to compile the binary", "err", err)
+		return false
+	}
+	fd, ok := cm.ExportedFunctions()[ExecuteFunc]
+	if !ok || len(fd.ParamTypes()) != 0 || len(fd.ResultTypes()) != 1 || fd.ResultTypes()[0] != api.ValueTypeI32 {
+		return false
+	}
+	for _, f := range cm.ImportedFunctions() {
+		mn, _, _ := f.Import()
+		if mn != HostModule {
+			return false
+		}
+	}
+	return true
+}
+
+// Execute executes the contract with the context, the state is updated only
+// if the execution succeeds. The execution fails with ErrOutOfFuel or
+// ErrStateTooLarge once the limits are hit.
+func (r *Runtime) Execute(ctx context.Context, code []byte, ec *Context) (*Result, error) {
+	if !r.IsContract(code) {
+		return nil, fmt.Errorf("binary is not a valid contract")
+	}
+	cm, err := r.compile(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	e := &execution{
+		ec:    ec,
+		state: append([]byte{}, ec.State...),
+	}
+	ctx = context.WithValue(ctx, execKey{}, e)
+	ctx, cancel := context.WithTimeout(ctx, r.lim.Timeout)
+	defer cancel()
+	m, err := r.rt.InstantiateModule(ctx, cm, wazero.NewModuleConfig().WithName("").WithStartFunctions())
+	if err != nil {
+		return nil, r.execError(m, err)
+	}
+	defer m.Close(ctx)
+	res, err := m.ExportedFunction(ExecuteFunc).Call(ctx)
+	if err != nil {
+		return nil, r.execError(m, err)
+	}
+	if status := api.DecodeI32(res[0]); status != 0 {
+		return nil, fmt.Errorf("contract execution failed with status %d", status)
+	}
+	return &Result{State: e.state, Events: e.events, Fuel: r.lim.Fuel - m.ExportedGlobal(FuelGlobal).Get()}, nil
+}
+
+// execError maps the trap to the limit error
+func (r *Runtime) execError(m api.Module, err error) error {
+	if errors.Is(err, ErrStateTooLarge) {
+		return ErrStateTooLarge
+	}
+	if m != nil && int64(m.ExportedGlobal(FuelGlobal).Get()) < 0 {
+		return ErrOutOfFuel
+	}
+	return err
+}
+
+// Close closes the runtime and the compiled binaries
+func (r *Runtime) Close() error {
+	return r.rt.Close(context.Background())
+}
@@ -0,0 +1,163 @@
+package wasm
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/rubixchain/rubixgoplatform/wrapper/logger"
+)
+
+func leb(n int) []byte {
+	b := make([]byte, 0)
+	for {
+		c := byte(n & 0x7f)
+		n >>= 7
+		if n != 0 {
+			c |= 0x80
+		}
+		b = append(b, c)
+		if n == 0 {
+			return b
+		}
+	}
+}
+
+func name(s string) []byte {
+	return append(leb(len(s)), s...)
+}
+
+func encSection(id byte, items ...[]byte) []byte {
+	p := leb(len(items))
+	for _, i := range items {
+		p = append(p, i...)
+	}
+	return append(append([]byte{id}, leb(len(p))...), p...)
+}
+
+func join(b ...[]byte) []byte {
+	return bytes.Join(b, nil)
+}
+
+// testContract builds the contract which stores the input as the state and
+// emits it as the event, the execution fails for the empty input
+func testContract(body []byte) []byte {
+	if body == nil {
+		body = []byte{
+			0x10, 0x00, 0x21, 0x00, // n = input_len()
+			0x41, 0x00, 0x20, 0x00, 0x10, 0x01, 0x1a, // input_read(0, n)
+			0x41, 0x00, 0x20, 0x00, 0x10, 0x02, // state_write(0, n)
+			0x41, 0x00, 0x20, 0x00, 0x10, 0x03, // emit_event(0, n)
+			0x20, 0x00, 0x45, // n == 0
+			0x0b,
+		}
+	}
+	code := join([]byte{0x01, 0x01, 0x7f}, body)
+	return join(
+		[]byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00},
+		encSection(1,
+			[]byte{0x60, 0x00, 0x01, 0x7f},
+			[]byte{0x60, 0x02, 0x7f, 0x7f, 0x01, 0x7f},
+			[]byte{0x60, 0x02, 0x7f, 0x7f, 0x00}),
+		encSection(2,
+			join(name(HostModule), name("input_len"), []byte{0x00, 0x00}),
+			join(name(HostModule), name("input_read"), []byte{0x00, 0x01}),
+			join(name(HostModule), name("state_write"), []byte{0x00, 0x02}),
+			join(name(HostModule), name("emit_event"), []byte{0x00, 0x02})),
+		encSection(3, []byte{0x00}),
+		encSection(5, []byte{0x00, 0x01}),
+		encSection(7,
+			join(name(ExecuteFunc), []byte{0x00, 0x04}),
+			join(name("memory"), []byte{0x02, 0x00})),
+		encSection(10, join(leb(len(code)), code)),
+	)
+}
+
+func TestRuntime(t *testing.T) {
+	log := logger.New(&logger.LoggerOptions{Name: "test", Color: []logger.ColorOption{logger.ColorOff}})
+	r, err := NewRuntime(log, nil)
+	if err != nil {
+		t.Fatal("Failed to create runtime", err)
+	}
+	defer r.Close()
+	code := testContract(nil)
+	if !r.IsContract(code) {
+		t.Fatal("Contract is not detected")
+	}
+	if r.IsContract([]byte("invalid")) {
+		t.Fatal("Invalid binary is detected as contract")
+	}
+	ec := &Context{CallerDID: "did", RBTAmount: 1, Input: []byte("new state"), State: []byte("old state")}
+	res, err := r.Execute(context.Background(), code, ec)
+	if err != nil {
+		t.Fatal("Failed to execute contract", err)
+	}
+	if string(res.State) != "new state" || len(res.Events) != 1 || string(res.Events[0]) != "new state" {
+		t.Fatal("Execution result mismatch")
+	}
+	ec.Input = nil
+	_, err = r.Execute(context.Background(), code, ec)
+	if err == nil {
+		t.Fatal("Failed execution is not reported")
+	}
+	if string(ec.State) != "old state" {
+		t.Fatal("State is updated by the failed execution")
+	}
+}
+
+func TestLimits(t *testing.T) {
+	log := logger.New(&logger.LoggerOptions{Name: "test", Color: []logger.ColorOption{logger.ColorOff}})
+	r, err := NewRuntime(log, &Limits{Fuel: 1000, MaxStateSize: 4})
+	if err != nil {
+		t.Fatal("Failed to create runtime", err)
+	}
+	defer r.Close()
+	ec := &Context{Input: []byte("1234")}
+	res, err := r.Execute(context.Background(), testContract(nil), ec)
+	if err != nil {
+		t.Fatal("Failed to execute contract", err)
+	}
+	res2, err := r.Execute(context.Background(), testContract(nil), ec)
+	if err != nil || res.Fuel == 0 || res.Fuel != res2.Fuel {
+		t.Fatal("Fuel is not deterministic", err)
+	}
+	ec.Input = []byte("12345")
+	_, err = r.Execute(context.Background(), testContract(nil), ec)
+	if err != ErrStateTooLarge {
+		t.Fatal("State size is not limited", err)
+	}
+	// loop forever
+	_, err = r.Execute(context.Background(), testContract([]byte{0x03, 0x40, 0x0c, 0x00, 0x0b, 0x41, 0x00, 0x0b}), ec)
+	if err != ErrOutOfFuel {
+		t.Fatal("Fuel is not limited", err)
+	}
+	// loop refilling the fuel global
+	refill := testContract([]byte{0x03, 0x40, 0x42, 0xc0, 0x84, 0x3d, 0x24, 0x00, 0x0c, 0x00, 0x0b, 0x41, 0x00, 0x0b})
+	if r.IsContract(refill) {
+		t.Fatal("Contract accessing the fuel global is detected as contract")
+	}
+	_, err = r.Execute(context.Background(), refill, ec)
+	if err == nil || err == context.DeadlineExceeded {
+		t.Fatal("Contract accessing the fuel global is executed", err)
+	}
+	_, err = meterBody([]byte{0x00, 0x23, 0x00, 0x1a, 0x0b}, 0)
+	if err == nil {
+		t.Fatal("Access to the fuel global is metered")
+	}
+	// memory.fill(0, 0, n) costs one fuel per byte
+	fill := func(n []byte) []byte {
+		return testContract(join([]byte{0x41, 0x00, 0x41, 0x00, 0x41}, n, []byte{0xfc, 0x0b, 0x00, 0x41, 0x00, 0x0b}))
+	}
+	res, err = r.Execute(context.Background(), fill([]byte{0x00}), ec)
+	if err != nil {
+		t.Fatal("Failed to execute contract", err)
+	}
+	res2, err = r.Execute(context.Background(), fill([]byte{0x08}), ec)
+	if err != nil || res2.Fuel != res.Fuel+8 {
+		t.Fatal("Bulk memory instruction is not charged by length", err)
+	}
+	_, err = r.Execute(context.Background(), fill([]byte{0x80, 0x80, 0x04}), ec)
+	if err != ErrOutOfFuel {
+		t.Fatal("Bulk memory instruction is not limited", err)
+	}
+}
@@ -2,17 +2,12 @@ package did
 
 import (
 	"context"
-	"encoding/json"
-	"errors"
 	"fmt"
 	"image"
-	"io"
 	"os"
-	"path/filepath"
 	"time"
 
-	ipfsnode "github.com/ipfs/go-ipfs-api"
-	files "github.com/ipfs/go-ipfs-files"
+	"github.com/rubixchain/rubixgoplatform/core/ipfsclient"
 	"github.com/rubixchain/rubixgoplatform/crypto"
 	"github.com/rubixchain/rubixgoplatform/nlss"
 	"github.com/rubixchain/rubixgoplatform/util"
@@ -28,12 +23,14 @@ type DIDChan struct {
 	Finish  chan bool
 	Req     *ensweb.Request
 	Timeout time.Duration
+	Ctx     context.Context
+	Cancel  context.CancelFunc
 }
 
 type DID struct {
 	dir  string
 	log  logger.Logger
-	ipfs *ipfsnode.Shell
+	ipfs ipfsclient.Client
 }
 
 type DIDCrypto interface {
@@ -44,7 +41,7 @@ type DIDCrypto interface {
 	PvtVerify(hash []byte, sign []byte) (bool, error)
 }
 
-func InitDID(dir string, log logger.Logger, ipfs *ipfsnode.Shell) *DID {
+func InitDID(dir string, log logger.Logger, ipfs ipfsclient.Client) *DID {
 	did := &DID{
 		dir:  dir,
 		log:  log,
@@ -369,58 +366,6 @@ func (d *DID) MigrateDID(didCreate *DIDCreate) (string, error) {
 	return did, nil
 }
 
-type object struct {
-	Hash string
-}
-
 func (d *DID) getDirHash(dir string) (string, error) {
-	stat, err := os.Lstat(dir)
-	if err != nil {
-		return "", err
-	}
-
-	sf, err := files.NewSerialFile(dir, false, stat)
-	if err != nil {
-		return "", err
-	}
-	defer sf.Close()
-	slf := files.NewSliceDirectory([]files.DirEntry{files.FileEntry(filepath.Base(dir), sf)})
-	defer slf.Close()
-	reader := files.NewMultiFileReader(slf, true)
-
-	resp, err := d.ipfs.Request("add").
-		Option("recursive", true).
-		Option("cid-version", 1).
-		Option("hash", "sha3-256").
-		Body(reader).
-		Send(context.Background())
-	if err != nil {
-		return "", err
-	}
-
-	defer resp.Close()
-
-	if resp.Error != nil {
-		return "", resp.Error
-	}
-	defer resp.Output.Close()
-	dec := json.NewDecoder(resp.Output)
-	var final string
-	for {
-		var out object
-		err = dec.Decode(&out)
-		if err != nil {
-			if err == io.EOF {
-				break
-			}
-			return "", err
-		}
-		final = out.Hash
-	}
-
-	if final == "" {
-		return "", errors.New("no results received")
-	}
-
-	return final, nil
+	return d.ipfs.AddDir(dir)
 }
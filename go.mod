@@ -12,6 +12,7 @@ require (
 	github.com/ipfs/go-ipfs-files v0.1.1
 	github.com/swaggo/swag v1.16.1
 	github.com/syndtr/goleveldb v1.0.1-0.20210819022825-2ae1ddf74ef7
+	github.com/tetratelabs/wazero v1.8.2
 	golang.org/x/crypto v0.16.0
 	golang.org/x/term v0.15.0
 	google.golang.org/grpc v1.54.0
@@ -33,7 +33,7 @@ func (rn *RubixNative) TransferRBT(ctx context.Context, in *protos.TransferRBTRe
 		Type:       int(in.Type),
 		Comment:    in.Comment,
 	}
-	br, err := c.TransferRBT(rt)
+	br, err := c.TransferRBTWithContext(ctx, rt)
 	if err != nil {
 		return nil, err
 	}
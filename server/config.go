@@ -100,6 +100,144 @@ func (s *Server) APIRemoveAllQuorum(req *ensweb.Request) *ensweb.Result {
 	return s.BasicResponse(req, true, "Removed all quorums successfully", nil)
 }
 
+// APIGetPledgeLeases will get the active pledge leases of the quorum
+func (s *Server) APIGetPledgeLeases(req *ensweb.Request) *ensweb.Result {
+	pls := s.c.GetPledgeLeases()
+	return s.BasicResponse(req, true, "Got all pledge leases successfully", pls)
+}
+
+// APIGetQuorumHealth will get the recorded health of the public quorums
+func (s *Server) APIGetQuorumHealth(req *ensweb.Request) *ensweb.Result {
+	qhs := s.c.GetQuorumHealth()
+	return s.BasicResponse(req, true, "Got quorum health successfully", qhs)
+}
+
+// APIGetUnpledgeStatus will get the status of the unpledge queue
+func (s *Server) APIGetUnpledgeStatus(req *ensweb.Request) *ensweb.Result {
+	us := s.c.GetUnpledgeStatus()
+	return s.BasicResponse(req, true, "Got unpledge status successfully", us)
+}
+
+// APIRetryUnpledge will queue the failed or cancelled token unpledge again
+func (s *Server) APIRetryUnpledge(req *ensweb.Request) *ensweb.Result {
+	var ur model.UnpledgeTokenRequest
+	err := s.ParseJSON(req, &ur)
+	if err != nil || ur.Token == "" {
+		return s.BasicResponse(req, false, "invlid input request", nil)
+	}
+	err = s.c.RetryUnpledge(ur.Token)
+	if err != nil {
+		return s.BasicResponse(req, false, "Failed to retry unpledge, "+err.Error(), nil)
+	}
+	return s.BasicResponse(req, true, "Token unpledge queued", nil)
+}
+
+// APICancelUnpledge will cancel the pending token unpledge
+func (s *Server) APICancelUnpledge(req *ensweb.Request) *ensweb.Result {
+	var ur model.UnpledgeTokenRequest
+	err := s.ParseJSON(req, &ur)
+	if err != nil || ur.Token == "" {
+		return s.BasicResponse(req, false, "invlid input request", nil)
+	}
+	err = s.c.CancelUnpledge(ur.Token)
+	if err != nil {
+		return s.BasicResponse(req, false, "Failed to cancel unpledge, "+err.Error(), nil)
+	}
+	return s.BasicResponse(req, true, "Token unpledge cancelled", nil)
+}
+
+// APIVerifyUnpledgeProof will verify the unpledge proof of the token
+func (s *Server) APIVerifyUnpledgeProof(req *ensweb.Request) *ensweb.Result {
+	var pr model.UnpledgeProofRequest
+	err := s.ParseJSON(req, &pr)
+	if err != nil || pr.Token == "" {
+		return s.BasicResponse(req, false, "invlid input request", nil)
+	}
+	rs, err := s.c.VerifyUnpledgeProof(pr.Token, pr.Proof)
+	if err != nil {
+		return s.BasicResponse(req, false, "Failed to verify unpledge proof, "+err.Error(), nil)
+	}
+	if !rs.Valid {
+		return s.BasicResponse(req, true, "Unpledge proof is invalid", rs)
+	}
+	return s.BasicResponse(req, true, "Unpledge proof is valid", rs)
+}
+
+// APIGetMigrations will get the applied & pending wallet migrations
+func (s *Server) APIGetMigrations(req *ensweb.Request) *ensweb.Result {
+	ml := s.c.GetMigrations()
+	return s.BasicResponse(req, true, "Got all migrations successfully", ml)
+}
+
+// APIRunMigrations will run the pending wallet migrations
+func (s *Server) APIRunMigrations(req *ensweb.Request) *ensweb.Result {
+	ml, err := s.c.RunMigrations()
+	if err != nil {
+		return s.BasicResponse(req, false, "Failed to run migrations, "+err.Error(), ml)
+	}
+	return s.BasicResponse(req, true, "Migrations done successfully", ml)
+}
+
+// APIBackup will create the backup of the node state
+func (s *Server) APIBackup(req *ensweb.Request) *ensweb.Result {
+	var br model.BackupRequest
+	err := s.ParseJSON(req, &br)
+	if err != nil {
+		return s.BasicResponse(req, false, "invlid input request", nil)
+	}
+	path, err := s.c.Backup(br.Path, br.Password)
+	if err != nil {
+		return s.BasicResponse(req, false, "Failed to create backup, "+err.Error(), nil)
+	}
+	return s.BasicResponse(req, true, "Backup created at "+path, path)
+}
+
+// APIVerifyWallet will verify the token chains against the wallet tokens
+func (s *Server) APIVerifyWallet(req *ensweb.Request) *ensweb.Result {
+	var vr model.VerifyWalletRequest
+	err := s.ParseJSON(req, &vr)
+	if err != nil {
+		return s.BasicResponse(req, false, "invlid input request", nil)
+	}
+	rp, err := s.c.VerifyWallet(&vr)
+	if err != nil {
+		return s.BasicResponse(req, false, "Failed to verify wallet, "+err.Error(), nil)
+	}
+	return s.BasicResponse(req, true, "Wallet verification done", rp)
+}
+
+// APIGetTokenIndexStatus will get the build status of the token index
+func (s *Server) APIGetTokenIndexStatus(req *ensweb.Request) *ensweb.Result {
+	ts := s.c.GetTokenIndexStatus()
+	return s.BasicResponse(req, true, "Got token index status", ts)
+}
+
+// APIVerifyTokenChain will verify all the blocks of the token chain
+func (s *Server) APIVerifyTokenChain(req *ensweb.Request) *ensweb.Result {
+	var vr model.VerifyTokenChainRequest
+	err := s.ParseJSON(req, &vr)
+	if err != nil || vr.Token == "" {
+		return s.BasicResponse(req, false, "invlid input request", nil)
+	}
+	if vr.TokenType == "" {
+		vr.TokenType = core.RBTString
+	}
+	tr, err := s.c.VerifyTokenChain(vr.Token, s.c.TokenType(vr.TokenType))
+	if err != nil {
+		return s.BasicResponse(req, false, "Failed to verify token chain, "+err.Error(), nil)
+	}
+	if !tr.Valid {
+		return s.BasicResponse(req, true, "Token chain is invalid", tr)
+	}
+	return s.BasicResponse(req, true, "Token chain is valid", tr)
+}
+
+// APIGetPubSubMetrics will get the message counters of the pubsub topics
+func (s *Server) APIGetPubSubMetrics(req *ensweb.Request) *ensweb.Result {
+	tms := s.c.GetPubSubMetrics()
+	return s.BasicResponse(req, true, "Got pubsub metrics", tms)
+}
+
 func (s *Server) APISetupDB(req *ensweb.Request) *ensweb.Result {
 	var sc cc.StorageConfig
 	err := s.ParseJSON(req, &sc)
@@ -52,9 +52,67 @@ func (s *Server) APIGetSmartContractTokenChainData(req *ensweb.Request) *ensweb.
 	return s.RenderJSON(req, sctdataReply, http.StatusOK)
 }
 
+// SmartContract godoc
+// @Summary      Get Smart Contract History
+// @Description  This API will return the indexed executions of the smart contract
+// @Tags         Smart Contract
+// @ID 			 get-smart-contract-history
+// @Accept       json
+// @Produce      json
+// @Param		 input body model.SCHistoryRequest true "Smart contract token"
+// @Success      200  {object}  model.SCHistoryResponse
+// @Router       /api/get-smart-contract-history [post]
+func (s *Server) APIGetSmartContractHistory(req *ensweb.Request) *ensweb.Result {
+	var hr model.SCHistoryRequest
+	err := s.ParseJSON(req, &hr)
+	if err != nil {
+		return s.BasicResponse(req, false, "Invalid input", nil)
+	}
+	return s.RenderJSON(req, s.c.GetSmartContractHistory(&hr), http.StatusOK)
+}
+
+// SmartContract godoc
+// @Summary      Get Smart Contract State
+// @Description  This API will return the smart contract state as of the block number or the time
+// @Tags         Smart Contract
+// @ID 			 get-smart-contract-state
+// @Accept       json
+// @Produce      json
+// @Param		 input body model.SCStateRequest true "Smart contract token with the block number or the unix time"
+// @Success      200  {object}  model.SCStateResponse
+// @Router       /api/get-smart-contract-state [post]
+func (s *Server) APIGetSmartContractState(req *ensweb.Request) *ensweb.Result {
+	var sr model.SCStateRequest
+	err := s.ParseJSON(req, &sr)
+	if err != nil {
+		return s.BasicResponse(req, false, "Invalid input", nil)
+	}
+	return s.RenderJSON(req, s.c.GetSmartContractState(&sr), http.StatusOK)
+}
+
+// SmartContract godoc
+// @Summary      Get Smart Contract State Diff
+// @Description  This API will return the difference between the smart contract states of two blocks
+// @Tags         Smart Contract
+// @ID 			 get-smart-contract-state-diff
+// @Accept       json
+// @Produce      json
+// @Param		 input body model.SCStateDiffRequest true "Smart contract token with the block numbers"
+// @Success      200  {object}  model.SCStateDiffResponse
+// @Router       /api/get-smart-contract-state-diff [post]
+func (s *Server) APIGetSmartContractStateDiff(req *ensweb.Request) *ensweb.Result {
+	var dr model.SCStateDiffRequest
+	err := s.ParseJSON(req, &dr)
+	if err != nil {
+		return s.BasicResponse(req, false, "Invalid input", nil)
+	}
+	return s.RenderJSON(req, s.c.GetSmartContractStateDiff(&dr), http.StatusOK)
+}
+
 type RegisterCallBackURLSwaggoInput struct {
 	Token       string `json:"token"`
 	CallBackURL string `json:"callbackurl"`
+	Secret      string `json:"secret"`
 }
 
 // SmartContract godoc
@@ -76,3 +134,47 @@ func (s *Server) APIRegisterCallbackURL(req *ensweb.Request) *ensweb.Result {
 	response := s.c.RegisterCallBackURL(&registerReq)
 	return s.RenderJSON(req, response, http.StatusOK)
 }
+
+// SmartContract godoc
+// @Summary      Get Callback Deliveries
+// @Description  This API will return the callback deliveries of the smart contract with the status
+// @Tags         Smart Contract
+// @ID 			 get-callback-deliveries
+// @Accept       json
+// @Produce      json
+// @Param		 token  query  string  false  "Smart contract token"
+// @Param		 status query  string  false  "Delivery status, pending, delivered or failed"
+// @Success      200  {object}  model.CallbackDeliveryResponse
+// @Router       /api/get-callback-deliveries [get]
+func (s *Server) APIGetCallbackDeliveries(req *ensweb.Request) *ensweb.Result {
+	token := s.GetQuerry(req, "token")
+	status := s.GetQuerry(req, "status")
+	cds, err := s.c.ListCallbackDeliveries(token, status)
+	if err != nil {
+		return s.BasicResponse(req, false, "Failed to get callback deliveries, "+err.Error(), nil)
+	}
+	return s.BasicResponse(req, true, "Got callback deliveries successfully", cds)
+}
+
+// SmartContract godoc
+// @Summary      Replay Callback Delivery
+// @Description  This API will queue the callback delivery again
+// @Tags         Smart Contract
+// @ID 			 replay-callback-delivery
+// @Accept       json
+// @Produce      json
+// @Param		 input body model.ReplayCallbackRequest true "Delivery ID"
+// @Success      200  {object}  model.BasicResponse
+// @Router       /api/replay-callback-delivery [post]
+func (s *Server) APIReplayCallbackDelivery(req *ensweb.Request) *ensweb.Result {
+	var rr model.ReplayCallbackRequest
+	err := s.ParseJSON(req, &rr)
+	if err != nil || rr.ID == "" {
+		return s.BasicResponse(req, false, "Invalid input", nil)
+	}
+	err = s.c.ReplayCallbackDelivery(rr.ID)
+	if err != nil {
+		return s.BasicResponse(req, false, "Failed to replay callback delivery, "+err.Error(), nil)
+	}
+	return s.BasicResponse(req, true, "Callback delivery queued", nil)
+}
@@ -140,7 +140,14 @@ func (s *Server) validateDIDAccess(req *ensweb.Request, did string) bool {
 
 func (s *Server) didResponse(req *ensweb.Request, reqID string) *ensweb.Result {
 	dc := s.c.GetWebReq(reqID)
-	ch := <-dc.OutChan
+	var ch interface{}
+	select {
+	case ch = <-dc.OutChan:
+	case <-req.GetHTTPRequest().Context().Done():
+		// client gave up, cancel the request and wait for the core to unwind
+		s.c.CancelWebReq(reqID)
+		ch = <-dc.OutChan
+	}
 	time.Sleep(time.Millisecond * 10)
 	sr, ok := ch.(*did.SignResponse)
 	if ok {
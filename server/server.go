@@ -148,10 +148,30 @@ func (s *Server) RegisterRoutes() {
 	s.AddRoute(setup.APIFetchSmartContract, "POST", s.AuthHandle(s.APIFetchSmartContract, true, s.AuthError, false))
 	s.AddRoute(setup.APIPublishContract, "POST", s.AuthHandle(s.APIPublishContract, true, s.AuthError, false))
 	s.AddRoute(setup.APISubscribecontract, "POST", s.AuthHandle(s.APISubscribecontract, true, s.AuthError, false))
+	s.AddRoute(setup.APIUnsubscribeContract, "POST", s.AuthHandle(s.APIUnsubscribeContract, true, s.AuthError, false))
+	s.AddRoute(setup.APIGetContractSubscriptions, "GET", s.AuthHandle(s.APIGetContractSubscriptions, true, s.AuthError, false))
 	s.AddRoute(setup.APIDumpSmartContractTokenChainBlock, "POST", s.AuthHandle(s.APIDumpSmartContractTokenChainBlock, true, s.AuthError, false))
 	s.AddRoute(setup.APIExecuteSmartContract, "POST", s.AuthHandle(s.APIExecuteSmartContract, true, s.AuthError, false))
 	s.AddRoute(setup.APIGetSmartContractTokenData, "POST", s.AuthHandle(s.APIGetSmartContractTokenChainData, true, s.AuthError, false))
 	s.AddRoute(setup.APIRegisterCallBackURL, "POST", s.AuthHandle(s.APIRegisterCallbackURL, true, s.AuthError, false))
+	s.AddRoute(setup.APIGetPledgeLeases, "GET", s.AuthHandle(s.APIGetPledgeLeases, true, s.AuthError, true))
+	s.AddRoute(setup.APIGetQuorumHealth, "GET", s.AuthHandle(s.APIGetQuorumHealth, true, s.AuthError, true))
+	s.AddRoute(setup.APIGetMigrations, "GET", s.AuthHandle(s.APIGetMigrations, true, s.AuthError, true))
+	s.AddRoute(setup.APIRunMigrations, "POST", s.AuthHandle(s.APIRunMigrations, true, s.AuthError, true))
+	s.AddRoute(setup.APIBackup, "POST", s.AuthHandle(s.APIBackup, true, s.AuthError, true))
+	s.AddRoute(setup.APIVerifyWallet, "POST", s.AuthHandle(s.APIVerifyWallet, true, s.AuthError, true))
+	s.AddRoute(setup.APIVerifyTokenChain, "POST", s.AuthHandle(s.APIVerifyTokenChain, true, s.AuthError, true))
+	s.AddRoute(setup.APIGetTokenIndexStatus, "GET", s.AuthHandle(s.APIGetTokenIndexStatus, true, s.AuthError, true))
+	s.AddRoute(setup.APIGetUnpledgeStatus, "GET", s.AuthHandle(s.APIGetUnpledgeStatus, true, s.AuthError, true))
+	s.AddRoute(setup.APIRetryUnpledge, "POST", s.AuthHandle(s.APIRetryUnpledge, true, s.AuthError, true))
+	s.AddRoute(setup.APICancelUnpledge, "POST", s.AuthHandle(s.APICancelUnpledge, true, s.AuthError, true))
+	s.AddRoute(setup.APIVerifyUnpledgeProof, "POST", s.AuthHandle(s.APIVerifyUnpledgeProof, false, s.AuthError, false))
+	s.AddRoute(setup.APIGetPubSubMetrics, "GET", s.AuthHandle(s.APIGetPubSubMetrics, true, s.AuthError, true))
+	s.AddRoute(setup.APIGetSmartContractHistory, "POST", s.AuthHandle(s.APIGetSmartContractHistory, true, s.AuthError, false))
+	s.AddRoute(setup.APIGetSmartContractState, "POST", s.AuthHandle(s.APIGetSmartContractState, true, s.AuthError, false))
+	s.AddRoute(setup.APIGetSmartContractStateDiff, "POST", s.AuthHandle(s.APIGetSmartContractStateDiff, true, s.AuthError, false))
+	s.AddRoute(setup.APIGetCallbackDeliveries, "GET", s.AuthHandle(s.APIGetCallbackDeliveries, true, s.AuthError, true))
+	s.AddRoute(setup.APIReplayCallbackDelivery, "POST", s.AuthHandle(s.APIReplayCallbackDelivery, true, s.AuthError, true))
 }
 
 func (s *Server) ExitFunc() error {
@@ -245,8 +245,9 @@ func (s *Server) APIPublishContract(request *ensweb.Request) *ensweb.Result {
 		return s.BasicResponse(request, false, "Failed to parse input", nil)
 	}
 
-	go s.c.PublishNewEvent(&newEvent)
-	return s.BasicResponse(request, true, "Smart contract published successfully", nil)
+	s.c.AddWebReq(request)
+	go s.c.PublishNewEvent(request.ID, &newEvent)
+	return s.didResponse(request, request.ID)
 }
 
 // SmartContract godoc
@@ -270,6 +271,40 @@ func (s *Server) APISubscribecontract(request *ensweb.Request) *ensweb.Result {
 	return s.BasicResponse(request, true, "Smart contract subscribed successfully", nil)
 }
 
+// SmartContract godoc
+// @Summary      Unsubscribe Smart Contract
+// @Description  This API endpoint cancels the smart contract subscription.
+// @Tags         Smart Contract
+// @Accept       json
+// @Produce      json
+// @Param        input body NewSubscriptionSwaggoInput true "Unsubscribe input contract"
+// @Success      200  {object}  model.BasicResponse
+// @Router       /api/unsubscribe-smart-contract [post]
+func (s *Server) APIUnsubscribeContract(request *ensweb.Request) *ensweb.Result {
+	var subscription model.NewSubscription
+	err := s.ParseJSON(request, &subscription)
+	if err != nil || subscription.SmartContractToken == "" {
+		return s.BasicResponse(request, false, "Failed to parse input", nil)
+	}
+	err = s.c.UnsubscribeContract(subscription.SmartContractToken)
+	if err != nil {
+		return s.BasicResponse(request, false, "Failed to unsubscribe smart contract, "+err.Error(), nil)
+	}
+	return s.BasicResponse(request, true, "Smart contract unsubscribed successfully", nil)
+}
+
+// SmartContract godoc
+// @Summary      Get Smart Contract Subscriptions
+// @Description  This API endpoint returns the smart contracts subscribed by the node.
+// @Tags         Smart Contract
+// @Produce      json
+// @Success      200  {object}  model.SCSubscriptionResponse
+// @Router       /api/get-smart-contract-subscriptions [get]
+func (s *Server) APIGetContractSubscriptions(request *ensweb.Request) *ensweb.Result {
+	subs := s.c.GetContractSubscriptions()
+	return s.BasicResponse(request, true, "Got smart contract subscriptions successfully", subs)
+}
+
 type ExecuteSmartContractSwaggoInput struct {
 	SmartContractToken string `json:"smartContractToken"`
 	ExecutorAddress    string `json:"executorAddr"`
@@ -56,9 +56,29 @@ const (
 	APIFetchSmartContract               string = "/api/fetch-smart-contract"
 	APIPublishContract                  string = "/api/publish-smart-contract"
 	APISubscribecontract                string = "/api/subscribe-smart-contract"
+	APIUnsubscribeContract              string = "/api/unsubscribe-smart-contract"
+	APIGetContractSubscriptions         string = "/api/get-smart-contract-subscriptions"
 	APIDumpSmartContractTokenChainBlock string = "/api/dump-smart-contract-token-chain"
 	APIGetSmartContractTokenData        string = "/api/get-smart-contract-token-chain-data"
 	APIRegisterCallBackURL              string = "/api/register-callback-url"
+	APIGetPledgeLeases                  string = "/api/get-pledge-leases"
+	APIGetQuorumHealth                  string = "/api/get-quorum-health"
+	APIGetMigrations                    string = "/api/get-migrations"
+	APIRunMigrations                    string = "/api/run-migrations"
+	APIBackup                           string = "/api/backup"
+	APIVerifyWallet                     string = "/api/verify-wallet"
+	APIVerifyTokenChain                 string = "/api/verify-token-chain"
+	APIGetTokenIndexStatus              string = "/api/get-token-index-status"
+	APIGetUnpledgeStatus                string = "/api/get-unpledge-status"
+	APIRetryUnpledge                    string = "/api/retry-unpledge"
+	APICancelUnpledge                   string = "/api/cancel-unpledge"
+	APIVerifyUnpledgeProof              string = "/api/verify-unpledge-proof"
+	APIGetPubSubMetrics                 string = "/api/get-pubsub-metrics"
+	APIGetSmartContractHistory          string = "/api/get-smart-contract-history"
+	APIGetSmartContractState            string = "/api/get-smart-contract-state"
+	APIGetSmartContractStateDiff        string = "/api/get-smart-contract-state-diff"
+	APIGetCallbackDeliveries            string = "/api/get-callback-deliveries"
+	APIReplayCallbackDelivery           string = "/api/replay-callback-delivery"
 )
 
 // jwt.RegisteredClaims
@@ -67,8 +67,7 @@ func ValidateWholeToken(tokenDetials string) (int, int, bool, error) {
 	if err != nil {
 		return -1, -1, false, err
 	}
-	tokenVal := -1
-	tokenVal = calcSHA256(tokenCountHash, maxTokenFromLevel(tokenLevel))
+	tokenVal := GetTokenNumber(tokenLevel, tokenCountHash)
 	if tokenVal == -1 {
 		return -1, -1, false, fmt.Errorf("token Count is invalid")
 	}
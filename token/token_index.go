@@ -0,0 +1,203 @@
+package token
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+const (
+	TokenIndexBatchSize int = 10000
+)
+
+var (
+	tokenIndexCountKey = []byte("c")
+	tokenIndexPrefix   = []byte("h")
+)
+
+// TokenIndex is the persistent hash to token number index. The token number
+// hash does not depend on the level, so the index holds the numbers from zero
+// up to the indexed count and a level is indexed once the count reaches the
+// maximum token number of the level. Only the first 8 bytes of the hash are
+// stored, the number found is confirmed by hashing it again.
+type TokenIndex struct {
+	l     sync.RWMutex
+	db    *leveldb.DB
+	count int
+}
+
+// ProgressFunc is called with the indexed count and the target count
+type ProgressFunc func(count int, total int)
+
+var (
+	dil sync.RWMutex
+	di  *TokenIndex
+)
+
+func NewTokenIndex(dir string) (*TokenIndex, error) {
+	db, err := leveldb.OpenFile(dir, nil)
+	if err != nil {
+		return nil, err
+	}
+	ti := &TokenIndex{
+		db: db,
+	}
+	v, err := db.Get(tokenIndexCountKey, nil)
+	if err == nil {
+		c, _ := binary.Uvarint(v)
+		ti.count = int(c)
+	} else if err != leveldb.ErrNotFound {
+		db.Close()
+		return nil, err
+	}
+	return ti, nil
+}
+
+// SetTokenIndex sets the index used by the token validation, validation
+// falls back to the linear search if the index is not set
+func SetTokenIndex(ti *TokenIndex) {
+	dil.Lock()
+	defer dil.Unlock()
+	di = ti
+}
+
+func getTokenIndex() *TokenIndex {
+	dil.RLock()
+	defer dil.RUnlock()
+	return di
+}
+
+func (ti *TokenIndex) Close() error {
+	return ti.db.Close()
+}
+
+// Count returns the number of token numbers indexed
+func (ti *TokenIndex) Count() int {
+	ti.l.RLock()
+	defer ti.l.RUnlock()
+	return ti.count
+}
+
+// IsLevelIndexed checks whether all the token numbers of the level are indexed
+func (ti *TokenIndex) IsLevelIndexed(level int) bool {
+	return ti.Count() >= maxTokenFromLevel(level)
+}
+
+// MaxTokenNumber returns the highest token number count across the levels
+func MaxTokenNumber() int {
+	max := 0
+	for _, v := range TokenMap {
+		if v > max {
+			max = v
+		}
+	}
+	return max
+}
+
+func tokenIndexKey(h []byte) []byte {
+	return append(append([]byte{}, tokenIndexPrefix...), h[:8]...)
+}
+
+// BuildLevel indexes the token numbers of the level, build resumes from the
+// last indexed number
+func (ti *TokenIndex) BuildLevel(level int, progress ProgressFunc) error {
+	max := maxTokenFromLevel(level)
+	if max == 0 {
+		return fmt.Errorf("invalid token level %d", level)
+	}
+	return ti.build(max, progress)
+}
+
+// BuildAll indexes the token numbers of all the levels
+func (ti *TokenIndex) BuildAll(progress ProgressFunc) error {
+	return ti.build(MaxTokenNumber(), progress)
+}
+
+func (ti *TokenIndex) build(max int, progress ProgressFunc) error {
+	c := ti.Count()
+	for c < max {
+		end := c + TokenIndexBatchSize
+		if end > max {
+			end = max
+		}
+		b := new(leveldb.Batch)
+		for i := c; i < end; i++ {
+			h := sha256.Sum256([]byte(strconv.Itoa(i)))
+			v := make([]byte, binary.MaxVarintLen64)
+			n := binary.PutUvarint(v, uint64(i))
+			b.Put(tokenIndexKey(h[:]), v[:n])
+		}
+		v := make([]byte, binary.MaxVarintLen64)
+		n := binary.PutUvarint(v, uint64(end))
+		b.Put(tokenIndexCountKey, v[:n])
+		err := ti.db.Write(b, nil)
+		if err != nil {
+			return err
+		}
+		ti.l.Lock()
+		ti.count = end
+		ti.l.Unlock()
+		c = end
+		if progress != nil {
+			progress(c, max)
+		}
+	}
+	return nil
+}
+
+// GetTokenNumber returns the token number of the hash in the level, the second
+// return is false if the level is not indexed yet
+func (ti *TokenIndex) GetTokenNumber(level int, hash string) (int, bool) {
+	if !ti.IsLevelIndexed(level) {
+		return -1, false
+	}
+	h, err := hex.DecodeString(hash)
+	if err != nil || len(h) != sha256.Size || hex.EncodeToString(h) != hash {
+		return -1, true
+	}
+	v, err := ti.db.Get(tokenIndexKey(h), nil)
+	if err != nil {
+		return -1, true
+	}
+	tn, _ := binary.Uvarint(v)
+	th := sha256.Sum256([]byte(strconv.FormatUint(tn, 10)))
+	if !bytes.Equal(th[:], h) {
+		// hash prefix collision, the search will resolve it
+		return -1, false
+	}
+	if int(tn) >= maxTokenFromLevel(level) {
+		return -1, true
+	}
+	return int(tn), true
+}
+
+// GetTokenNumber returns the token number of the hash in the level, it
+// uses the token index if the level is indexed else the linear search
+func GetTokenNumber(level int, hash string) int {
+	ti := getTokenIndex()
+	if ti != nil {
+		tn, ok := ti.GetTokenNumber(level, hash)
+		if ok {
+			return tn
+		}
+	}
+	return calcSHA256(hash, maxTokenFromLevel(level))
+}
+
+// GetTokenNumberFromHash returns the token number of the hash irrespective of
+// the level
+func GetTokenNumberFromHash(hash string) int {
+	m := MaxTokenNumber()
+	for l, v := range TokenMap {
+		if v == m {
+			return GetTokenNumber(l, hash)
+		}
+	}
+	return -1
+}
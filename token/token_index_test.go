@@ -0,0 +1,61 @@
+package token
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"testing"
+)
+
+func TestTokenIndex(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tokenindex")
+	if err != nil {
+		t.Fatal("Failed to create temp dir", err)
+	}
+	defer os.RemoveAll(dir)
+	ti, err := NewTokenIndex(dir)
+	if err != nil {
+		t.Fatal("Failed to open token index", err)
+	}
+	err = ti.BuildLevel(78, nil)
+	if err != nil {
+		t.Fatal("Failed to build token index", err)
+	}
+	if !ti.IsLevelIndexed(78) || ti.IsLevelIndexed(77) {
+		t.Fatal("Invalid indexed levels")
+	}
+	ti.Close()
+	// index must be reloaded from the disk
+	ti, err = NewTokenIndex(dir)
+	if err != nil {
+		t.Fatal("Failed to reopen token index", err)
+	}
+	defer ti.Close()
+	SetTokenIndex(ti)
+	defer SetTokenIndex(nil)
+	hash := func(n int) string {
+		return fmt.Sprintf("%x", sha256.Sum256([]byte(strconv.Itoa(n))))
+	}
+	for _, n := range []int{0, 1, 12345, 17601} {
+		tn, ok := ti.GetTokenNumber(78, hash(n))
+		if !ok || tn != n {
+			t.Fatal("Token number mismatch", n, tn)
+		}
+	}
+	if tn, ok := ti.GetTokenNumber(78, hash(17602)); !ok || tn != -1 {
+		t.Fatal("Token number beyond the level found", tn)
+	}
+	if _, ok := ti.GetTokenNumber(77, hash(1)); ok {
+		t.Fatal("Level not indexed is used")
+	}
+	// falls back to the search for the level not indexed
+	if tn := GetTokenNumber(77, hash(17605)); tn != 17605 {
+		t.Fatal("Token number search failed", tn)
+	}
+	_, tn, _, err := ValidateWholeToken(GetTokenString(78, 100))
+	if err != nil || tn != 100 {
+		t.Fatal("Failed to validate whole token", err)
+	}
+}
@@ -3,6 +3,7 @@ package adapter
 import (
 	"fmt"
 	"net/url"
+	"strings"
 
 	"github.com/rubixchain/rubixgoplatform/wrapper/config"
 	"github.com/rubixchain/rubixgoplatform/wrapper/uuid"
@@ -21,6 +22,9 @@ const (
 	sqlite3     string = "Sqlite3"
 )
 
+// sqliteBusyTimeout is the time in milliseconds a writer waits for the lock
+const sqliteBusyTimeout int = 5000
+
 // TenantIDStr ...
 const TenantIDStr string = "TenantId"
 
@@ -44,7 +48,16 @@ func NewAdapter(cfg *config.Config) (*Adapter, error) {
 		dsn := fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=disable", cfg.DBAddress, cfg.DBPort, cfg.DBUserName, cfg.DBName, cfg.DBPassword)
 		db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
 	case sqlite3:
-		db, err = gorm.Open(sqlite.Open(cfg.DBAddress), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+		// writers wait for the lock instead of failing with "database is locked"
+		dsn := cfg.DBAddress
+		if !strings.Contains(dsn, "_busy_timeout") {
+			sep := "?"
+			if strings.Contains(dsn, "?") {
+				sep = "&"
+			}
+			dsn = fmt.Sprintf("%s%s_busy_timeout=%d", dsn, sep, sqliteBusyTimeout)
+		}
+		db, err = gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
 	default:
 		dsn := fmt.Sprintf("sqlserver://%s:%s@%s:%s?database=%s", cfg.DBUserName, cfg.DBPassword, cfg.DBAddress, cfg.DBPort, cfg.DBName)
 		db, err = gorm.Open(mysql.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
@@ -66,6 +79,37 @@ func (adapter *Adapter) GetDB() *gorm.DB {
 	return adapter.db
 }
 
+// Begin starts the transaction, returned adapter runs all the queries in the transaction
+func (adapter *Adapter) Begin() (*Adapter, error) {
+	tx := adapter.db.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return &Adapter{
+		db:     tx,
+		dbType: adapter.dbType,
+	}, nil
+}
+
+// Commit commits the transaction
+func (adapter *Adapter) Commit() error {
+	return adapter.db.Commit().Error
+}
+
+// Rollback discards the transaction
+func (adapter *Adapter) Rollback() error {
+	return adapter.db.Rollback().Error
+}
+
+// Backup writes the consistent copy of the database into the file while it
+// is in use, only the Sqlite3 database is supported
+func (adapter *Adapter) Backup(fileName string) error {
+	if adapter.dbType != sqlite3 {
+		return fmt.Errorf("backup is not supported for %s, use the database backup tools", adapter.dbType)
+	}
+	return adapter.db.Exec("VACUUM INTO ?", fileName).Error
+}
+
 // InitTable Initialize table
 func (adapter *Adapter) InitTable(tableName string, item interface{}, force bool) error {
 	m := adapter.db.Table(tableName).Migrator()